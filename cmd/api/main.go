@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,11 +19,22 @@ import (
 	"github.com/yourusername/draft-forge/internal/agents"
 	apiHandlers "github.com/yourusername/draft-forge/internal/api"
 	"github.com/yourusername/draft-forge/internal/auth"
+	"github.com/yourusername/draft-forge/internal/credits"
 	"github.com/yourusername/draft-forge/internal/db"
 	dbagent "github.com/yourusername/draft-forge/internal/db/agent"
+	dbcontinuity "github.com/yourusername/draft-forge/internal/db/continuity"
+	dbcredits "github.com/yourusername/draft-forge/internal/db/credits"
 	dbproject "github.com/yourusername/draft-forge/internal/db/project"
+	dbresultcache "github.com/yourusername/draft-forge/internal/db/resultcache"
+	dbschedule "github.com/yourusername/draft-forge/internal/db/schedule"
+	dbsuggestion "github.com/yourusername/draft-forge/internal/db/suggestion"
+	dbusage "github.com/yourusername/draft-forge/internal/db/usage"
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
 	"github.com/yourusername/draft-forge/internal/projects"
 	"github.com/yourusername/draft-forge/internal/scaffold"
+	"github.com/yourusername/draft-forge/internal/scheduler"
+	"github.com/yourusername/draft-forge/internal/suggestions"
 )
 
 func main() {
@@ -101,15 +116,94 @@ func main() {
 	sqlxDB := sqlx.NewDb(dbConn, "postgres")
 
 	agentStore := dbagent.NewStore(sqlxDB)
-	agentService := agents.NewService(agentStore, artifactDir)
-	agentHandler := apiHandlers.NewAgentHandler(agentService)
-	protected := api.Group("", apiHandlers.AuthMiddleware(tokenManager, userStore))
-
 	projectStore := dbproject.NewStore(sqlxDB)
+	continuityStore := dbcontinuity.NewStore(sqlxDB)
+	usageStore := dbusage.NewStore(sqlxDB)
+	creditStore := dbcredits.NewStore(sqlxDB)
+	resultCacheStore := dbresultcache.NewStore(sqlxDB)
+	suggestionStore := dbsuggestion.NewStore(sqlxDB)
 	scaffoldRoot := os.Getenv("SCAFFOLD_ROOT")
 	if scaffoldRoot == "" {
 		scaffoldRoot = "scaffolds"
 	}
+	workspaceRoot := os.Getenv("WORKSPACE_ROOT")
+	if workspaceRoot == "" {
+		workspaceRoot = scaffoldRoot
+	}
+	contextBudget, _ := strconv.Atoi(os.Getenv("AGENT_CONTEXT_BUDGET"))
+	chunkTokens, _ := strconv.Atoi(os.Getenv("AGENT_CHUNK_TOKENS"))
+
+	workspaces := agents.NewLocalWorkspaces(workspaceRoot, projectStore)
+
+	agentOpts := []agents.Option{
+		agents.WithRegistry(agents.NewBuiltinRegistry(agents.BuiltinDeps{Ledger: continuityStore})),
+		agents.WithWorkspaces(workspaces, manuscript.NewBuilder(contextBudget)),
+		agents.WithChunking(manuscript.ChunkOptions{MaxTokens: chunkTokens, OverlapTokens: manuscript.DefaultOverlapTokens}),
+		agents.WithSuggestions(suggestionStore),
+	}
+	prices := agents.DefaultPriceTable
+	if path := os.Getenv("AGENT_PRICE_TABLE"); path != "" {
+		if prices, err = agents.LoadPriceTable(path); err != nil {
+			log.Fatal("Failed to load agent price table:", err)
+		}
+	}
+	agentOpts = append(agentOpts, agents.WithUsage(usageStore, prices))
+	if os.Getenv("AGENT_RESULT_CACHE") != "off" {
+		agentOpts = append(agentOpts, agents.WithResultCache(resultCacheStore))
+	}
+
+	freeCredits := credits.DefaultFreeGrant
+	if raw := os.Getenv("AGENT_FREE_CREDITS"); raw != "" {
+		freeCredits, _ = strconv.Atoi(raw)
+	}
+	creditService := credits.NewService(creditStore, freeCredits)
+	agentOpts = append(agentOpts, agents.WithCredits(creditService))
+
+	// Run events go out through Postgres NOTIFY so every instance's SSE streams see them.
+	eventBroker := agents.NewBroker()
+	eventRelay := dbagent.NewEventRelay(sqlxDB, databaseURL)
+	agentOpts = append(agentOpts, agents.WithEvents(eventRelay))
+	if maxRuntime, err := time.ParseDuration(os.Getenv("AGENT_MAX_RUNTIME")); err == nil {
+		agentOpts = append(agentOpts, agents.WithMaxRuntime(maxRuntime))
+	}
+	if apiKey := os.Getenv("OPENROUTER_API_KEY"); apiKey != "" {
+		agentOpts = append(agentOpts, agents.WithProvider(agents.NewOpenRouterProvider(nil, apiKey), os.Getenv("OPENROUTER_MODEL")))
+	} else {
+		log.Println("OPENROUTER_API_KEY not set; agent runs will not call a model")
+	}
+	agentService := agents.NewService(agentStore, artifactDir, agentOpts...)
+	agentHandler := apiHandlers.NewAgentHandler(agentService)
+	agentEventsHandler := apiHandlers.NewAgentEventsHandler(agentService, eventBroker)
+	usageHandler := apiHandlers.NewUsageHandler(agentService)
+	creditHandler := apiHandlers.NewCreditHandler(creditService)
+	pipelineHandler := apiHandlers.NewPipelineHandler(agentService)
+	suggestionHandler := apiHandlers.NewSuggestionHandler(suggestions.NewService(suggestionStore, workspaces, artifactDir))
+
+	schedulerInterval, _ := time.ParseDuration(os.Getenv("AGENT_SCHEDULER_INTERVAL"))
+	agentScheduler := scheduler.NewScheduler(dbschedule.NewStore(sqlxDB), agentService, schedulerInterval)
+	scheduleHandler := apiHandlers.NewScheduleHandler(agentScheduler)
+
+	workerConcurrency, _ := strconv.Atoi(os.Getenv("AGENT_WORKER_CONCURRENCY"))
+	workerPollInterval, _ := time.ParseDuration(os.Getenv("AGENT_WORKER_POLL_INTERVAL"))
+	agentWorker := agents.NewWorker(agentService, workerConcurrency, workerPollInterval)
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	workerDone := make(chan struct{})
+	go func() {
+		agentWorker.Run(workerCtx)
+		close(workerDone)
+	}()
+	go agentScheduler.Run(workerCtx)
+	go func() {
+		err := eventRelay.Listen(workerCtx, func(event models.RunEvent) {
+			_ = eventBroker.Publish(workerCtx, event)
+		})
+		if err != nil {
+			log.Printf("agent events: %v", err)
+		}
+	}()
+
+	protected := api.Group("", apiHandlers.AuthMiddleware(tokenManager, userStore))
+
 	localScaffolder := scaffold.NewLocalScaffolder(scaffoldRoot)
 	githubScaffolder := scaffold.NewGitHubScaffolder(nil)
 	projectScaffolder := &scaffold.CompositeScaffolder{
@@ -121,6 +215,12 @@ func main() {
 	projectHandler.Register(protected)
 
 	agentHandler.Register(protected)
+	agentEventsHandler.Register(protected)
+	usageHandler.Register(protected)
+	creditHandler.Register(protected)
+	scheduleHandler.Register(protected)
+	pipelineHandler.Register(protected)
+	suggestionHandler.Register(protected)
 
 	// Start server
 	port := os.Getenv("API_PORT")
@@ -128,10 +228,24 @@ func main() {
 		port = "8080"
 	}
 
+	go func() {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+		<-sigCh
+		log.Println("Shutting down DraftForge API")
+		// End open event streams; Shutdown waits for every connection to close.
+		eventBroker.Close()
+		_ = app.Shutdown()
+	}()
+
 	log.Printf("Starting DraftForge API on port %s", port)
 	if err := app.Listen(":" + port); err != nil {
 		log.Fatal(err)
 	}
+
+	// Let in-flight agent runs finish recording their outcome before exiting.
+	stopWorker()
+	<-workerDone
 }
 
 func customErrorHandler(c *fiber.Ctx, err error) error {
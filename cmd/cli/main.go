@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 
 	"github.com/joho/godotenv"
+	"github.com/yourusername/draft-forge/internal/agents"
+	"github.com/yourusername/draft-forge/internal/agents/eval"
 	"github.com/yourusername/draft-forge/internal/db"
 )
 
@@ -20,6 +25,7 @@ func main() {
 		fmt.Println("\nAvailable commands:")
 		fmt.Println("  migrate up    - Run all pending migrations")
 		fmt.Println("  migrate down  - Rollback the last migration")
+		fmt.Println("  eval          - Score agents against the golden manuscripts in evals/")
 		os.Exit(1)
 	}
 
@@ -31,6 +37,8 @@ func main() {
 			log.Fatal("Usage: cli migrate <up|down>")
 		}
 		handleMigrate(os.Args[2])
+	case "eval":
+		handleEval(os.Args[2:])
 	default:
 		log.Fatalf("Unknown command: %s", command)
 	}
@@ -52,3 +60,52 @@ func handleMigrate(direction string) {
 		log.Fatal(err)
 	}
 }
+
+func handleEval(args []string) {
+	flags := flag.NewFlagSet("eval", flag.ExitOnError)
+	suiteDir := flags.String("suite", "evals", "directory of eval cases")
+	agentName := flags.String("agent", "", "only run cases for this agent")
+	provider := flags.String("provider", "fake", "fake (per-case fixtures) or openrouter")
+	model := flags.String("model", os.Getenv("OPENROUTER_MODEL"), "model for the openrouter provider")
+	out := flags.String("out", "", "write the JSON report here instead of stdout")
+	_ = flags.Parse(args)
+
+	opts := eval.Options{Agent: *agentName}
+	switch *provider {
+	case "fake":
+	case "openrouter":
+		apiKey := os.Getenv("OPENROUTER_API_KEY")
+		if apiKey == "" {
+			log.Fatal("OPENROUTER_API_KEY environment variable is required for -provider openrouter")
+		}
+		opts.Provider = agents.NewOpenRouterProvider(nil, apiKey)
+		opts.Model = *model
+	default:
+		log.Fatalf("Unknown provider: %s", *provider)
+	}
+
+	suite, err := eval.LoadSuite(*suiteDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	report := eval.Run(context.Background(), suite, opts)
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	data = append(data, '\n')
+	if *out == "" {
+		os.Stdout.Write(data)
+	} else if err := os.WriteFile(*out, data, 0o644); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("eval: %d cases, precision %.2f, recall %.2f", len(report.Cases), report.Total.Precision, report.Total.Recall)
+	if failures := report.Failures(); len(failures) > 0 {
+		for _, failure := range failures {
+			log.Printf("eval: case failed: %s", failure)
+		}
+		os.Exit(1)
+	}
+}
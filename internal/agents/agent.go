@@ -0,0 +1,83 @@
+package agents
+
+import (
+	"context"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// ContextRequirements describes which parts of a project an agent reads.
+type ContextRequirements struct {
+	// ChangedFiles requests the files listed on the run (usually chapters).
+	ChangedFiles bool
+	// StoryBible requests CREATIVE.md plus docs/characters and docs/world.
+	StoryBible bool
+	// Editorial requests EDITORIAL.md for the current editing round.
+	Editorial bool
+	// Sources requests the bibliographies in docs/sources.
+	Sources bool
+}
+
+// Input is everything an agent receives for a single run.
+type Input struct {
+	Run   models.AgentRun
+	Files []string
+	// Context holds the documents selected by the agent's ContextRequirements. It is empty
+	// when the service has no workspace resolver configured.
+	Context manuscript.Context
+	// Chunk is the slice of the changed files this call should analyse. Long runs call
+	// Run once per chunk and then reduce; Chunk is nil when there is nothing to chunk.
+	Chunk    *manuscript.Chunk
+	Provider Provider
+	Model    string
+	// Prompt is the agent's prompt template: a project override or its embedded default.
+	// Agents fall back to their built-in template when it is nil.
+	Prompt *PromptTemplate
+	// Upstream holds the completed runs of earlier pipeline stages this run depends on.
+	Upstream []models.AgentRun
+	// Editorial is the focus of the project's current editing round. The Service filters
+	// and re-ranks issues by it after the agent returns.
+	Editorial manuscript.Editorial
+}
+
+// instructions renders the run's prompt template, or builtin when none is set.
+func (in Input) instructions(builtin *PromptTemplate) (string, error) {
+	prompt := in.Prompt
+	if prompt == nil {
+		prompt = builtin
+	}
+	return prompt.Render(in)
+}
+
+// Result is the output of an agent run before it is persisted. Issue IDs may be left
+// empty; the Service assigns stable IDs before validating the result.
+type Result struct {
+	Summary string
+	Issues  []models.Issue
+	// Metrics are agent-specific measurements persisted alongside the issues.
+	Metrics map[string]any
+	Usage   Usage
+	// Data is an agent-specific per-chunk payload handed to Reduce; it is not persisted.
+	// A reduced result's Data may implement committer to save agent state once the run
+	// has completed.
+	Data any
+	// Artifacts are extra files, keyed by base name, that the Service writes to the
+	// run's artifact subdirectory alongside the run artifact.
+	Artifacts map[string][]byte
+}
+
+// committer is state an agent wants saved only after its run is recorded as completed.
+type committer interface {
+	commit(ctx context.Context) error
+}
+
+// Agent is a registered agent type that the Service can dispatch runs to.
+type Agent interface {
+	// Name is the agent type used in API requests and stored on runs.
+	Name() string
+	// DefaultTrigger is the trigger the agent is designed around (commit, pr, manual, scheduled).
+	DefaultTrigger() string
+	ContextRequirements() ContextRequirements
+	Run(ctx context.Context, input Input) (Result, error)
+}
@@ -0,0 +1,31 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// Analyze runs an agent over a working tree the way a queued run would, but without
+// queueing or persisting anything: it returns the run with its validated Results,
+// PromptHash and EditorialRound set. Artifacts, credits, events and suggestions are
+// skipped, so the Service may be built without a RunStore. The eval harness uses it to
+// score agents offline.
+func (s *Service) Analyze(ctx context.Context, root string, run models.AgentRun) (models.AgentRun, error) {
+	agent, ok := s.registry.Get(run.AgentType)
+	if !ok {
+		return models.AgentRun{}, fmt.Errorf("%w: %s", ErrInvalidAgentType, run.AgentType)
+	}
+	a, err := s.analyse(ctx, root, run, agent, nil)
+	if err != nil {
+		return models.AgentRun{}, err
+	}
+	if a.input.Prompt != nil {
+		run.PromptHash = a.input.Prompt.Hash
+	}
+	run.EditorialRound = a.input.Editorial.Round
+	run.Status = "completed"
+	run.Results = &a.runResult
+	return run, nil
+}
@@ -0,0 +1,41 @@
+package agents
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeMatchesExecuteRun(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "chapters/01.md", styleFixture)
+	writeFile(t, root, "EDITORIAL.md", "**Round**: Voice Pass\n\n## Anti-Goals\n- adverbs\n")
+	store := newMockStore()
+	svc := NewService(store, t.TempDir(), WithWorkspaces(stubWorkspaces{1: root}, nil))
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "style", FilesChanged: []string{"chapters/01.md"}}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	claimed, _ := svc.claimNextRun(ctx)
+	if err := svc.executeRun(ctx, claimed); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+	executed, _ := store.GetRun(ctx, claimed.ID)
+
+	analyzed, err := NewService(nil, "").Analyze(ctx, root, claimed)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if analyzed.PromptHash != executed.PromptHash || analyzed.EditorialRound != "voice-pass" || analyzed.EditorialRound != executed.EditorialRound {
+		t.Fatalf("expected the same prompt and round, got %+v and %+v", analyzed, executed)
+	}
+	if !reflect.DeepEqual(analyzed.Results.Issues, executed.Results.Issues) {
+		t.Fatalf("expected the same issues, got %+v and %+v", analyzed.Results.Issues, executed.Results.Issues)
+	}
+	for _, issue := range analyzed.Results.Issues {
+		if issue.Category == "adverbs" {
+			t.Fatalf("expected the anti-goal to suppress adverbs, got %+v", issue)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// resultFormatInstructions asks models to reply in the modelResult JSON shape.
+const resultFormatInstructions = `Reply with a single JSON object and nothing else:
+{"summary": "<one paragraph>", "issues": [{"severity": "info|warning|error", "category": "<short category>", "message": "<what is wrong>", "file": "<repo-relative path>", "start_line": <int>, "end_line": <int>, "excerpt": "<exact quoted text>", "suggestion": "<how to fix>", "edit": {"replacement": "<exact rewritten text of start_line..end_line>"}, "confidence": <0..1>}]}
+Include "edit" only when proposing exact wording; it replaces the whole lines start_line..end_line. Never include it for issues that need the author's judgement.`
+
+// BuiltinDeps are the stores built-in agents use to keep state between runs. Nil
+// fields disable the corresponding persistence.
+type BuiltinDeps struct {
+	// Ledger backs ContinuityBot's fact ledger.
+	Ledger LedgerStore
+}
+
+func builtinAgents(deps BuiltinDeps) []Agent {
+	return []Agent{
+		continuityAgent{ledger: deps.Ledger},
+		styleAgent{},
+		timelineAgent{},
+		factAgent{},
+	}
+}
+
+// askModel sends the agent's instructions plus the rendered context (and any extra
+// notes) to the model and parses the reply. A reply that is not valid JSON is kept as
+// the summary rather than failing the run; it is still useful to the author.
+func askModel(ctx context.Context, input Input, name, instructions, notes string) (modelResult, Usage, error) {
+	content, usage, err := completeWithContext(ctx, input, name, instructions+"\n\n"+resultFormatInstructions, notes)
+	if err != nil {
+		return modelResult{}, Usage{}, err
+	}
+
+	parsed, err := parseModelResult(content)
+	if err != nil {
+		return modelResult{Summary: strings.TrimSpace(content)}, usage, nil
+	}
+	return parsed, usage, nil
+}
+
+// completeWithContext sends a system prompt for the named agent plus the rendered context
+// (and any extra notes) to the model and returns the raw reply.
+func completeWithContext(ctx context.Context, input Input, name, instructions, notes string) (string, Usage, error) {
+	user := renderContext(input)
+	if notes != "" {
+		user += "\n## Notes\n\n" + notes + "\n"
+	}
+
+	resp, err := input.Provider.Complete(ctx, CompletionRequest{
+		Model: input.Model,
+		Messages: []Message{
+			{Role: "system", Content: fmt.Sprintf("You are DraftForge's %s agent. %s", name, instructions)},
+			{Role: "user", Content: user},
+		},
+	})
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("call provider: %w", err)
+	}
+	return resp.Content, resp.Usage, nil
+}
+
+// renderContext lays out the reference documents and the chunk under review as Markdown
+// sections for a prompt. Chunk lines carry their source line numbers for anchoring.
+func renderContext(input Input) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Files changed: %s\n", strings.Join(input.Files, ", "))
+	for _, doc := range input.Context.Documents {
+		fmt.Fprintf(&b, "\n## %s (%s)\n\n%s\n", doc.Path, doc.Kind, doc.Content)
+	}
+	if goals := input.Editorial.Goals; len(goals) > 0 {
+		fmt.Fprintf(&b, "\n## Editorial goals (prioritise)\n\n- %s\n", strings.Join(goals, "\n- "))
+	}
+	if antiGoals := input.Editorial.AntiGoals; len(antiGoals) > 0 {
+		fmt.Fprintf(&b, "\n## Editorial anti-goals (do not report)\n\n- %s\n", strings.Join(antiGoals, "\n- "))
+	}
+	for _, run := range input.Upstream {
+		fmt.Fprintf(&b, "\n## Earlier stage: %s (run %d)\n\n%s", run.AgentType, run.ID, renderUpstream(run))
+	}
+	if input.Chunk != nil {
+		fmt.Fprintf(&b, "\n## Under review: %s (lines %d-%d)\n\n%s", input.Chunk.Path, input.Chunk.StartLine, input.Chunk.EndLine, input.Chunk.NumberedContent())
+	}
+	return b.String()
+}
@@ -0,0 +1,152 @@
+package agents
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// ResultCache stores per-chunk agent results so runs can skip chunks whose content has
+// not changed since an earlier run.
+type ResultCache interface {
+	Get(ctx context.Context, key models.ResultCacheKey) (json.RawMessage, bool, error)
+	Put(ctx context.Context, key models.ResultCacheKey, result json.RawMessage) error
+}
+
+// Cacheable is implemented by agents whose chunk results depend only on the chunk, the
+// context documents and the prompt. Their results are reused across runs when a
+// ResultCache is configured.
+type Cacheable interface {
+	// CacheVersion identifies the agent's built-in prompt and result shape. Change it
+	// whenever either changes so stale entries stop matching.
+	CacheVersion() string
+	// DecodeData restores Result.Data from its cached JSON form.
+	DecodeData(raw json.RawMessage) (any, error)
+}
+
+// WithResultCache reuses chunk results of Cacheable agents across runs.
+func WithResultCache(cache ResultCache) Option {
+	return func(s *Service) {
+		s.cache = cache
+	}
+}
+
+// cachedResult is the stored form of a chunk Result. Usage is not stored: a cache hit
+// costs nothing.
+type cachedResult struct {
+	Summary   string            `json:"summary"`
+	Issues    []models.Issue    `json:"issues"`
+	Metrics   map[string]any    `json:"metrics,omitempty"`
+	Data      json.RawMessage   `json:"data,omitempty"`
+	Artifacts map[string][]byte `json:"artifacts,omitempty"`
+}
+
+// decodeData unmarshals cached Result.Data into T for Cacheable.DecodeData.
+func decodeData[T any](raw json.RawMessage) (any, error) {
+	var data T
+	if len(raw) == 0 {
+		return data, nil
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// cacheKey returns the cache key for a chunk input, or false when the agent's results
+// cannot be cached.
+func (s *Service) cacheKey(agent Agent, input Input) (models.ResultCacheKey, bool) {
+	cacheable, ok := agent.(Cacheable)
+	if !ok || s.cache == nil || input.Chunk == nil {
+		return models.ResultCacheKey{}, false
+	}
+
+	model := ""
+	if input.Provider != nil {
+		model = input.Model
+	}
+	version := cacheable.CacheVersion()
+	if input.Prompt != nil {
+		version += "+" + input.Prompt.Hash[:12]
+	}
+	return models.ResultCacheKey{
+		ProjectID:     input.Run.ProjectID,
+		AgentType:     agent.Name(),
+		Model:         model,
+		PromptVersion: version,
+		ContentHash:   chunkContentHash(input),
+	}, true
+}
+
+// chunkContentHash covers everything a cacheable agent reads: the chunk's position and
+// text, the context documents sent alongside it, the bibliography and the results of
+// earlier pipeline stages.
+func chunkContentHash(input Input) string {
+	h := sha256.New()
+	c := input.Chunk
+	fmt.Fprintf(h, "chunk %s %d-%d %d %d\n%s\n", c.Path, c.StartLine, c.EndLine, c.OverlapLines, len(c.Content), c.Content)
+	for _, doc := range input.Context.Documents {
+		fmt.Fprintf(h, "doc %s %s %d\n%s\n", doc.Path, doc.Kind, len(doc.Content), doc.Content)
+	}
+	for _, doc := range input.Context.Sources {
+		fmt.Fprintf(h, "source %s %d\n%s\n", doc.Path, len(doc.Content), doc.Content)
+	}
+	for _, run := range input.Upstream {
+		upstream := renderUpstream(run)
+		fmt.Fprintf(h, "upstream %s %d\n%s\n", run.AgentType, len(upstream), upstream)
+	}
+	for _, goal := range input.Editorial.Goals {
+		fmt.Fprintf(h, "goal %s\n", goal)
+	}
+	for _, goal := range input.Editorial.AntiGoals {
+		fmt.Fprintf(h, "anti-goal %s\n", goal)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// loadCached returns the cached result for key. Cache errors are treated as misses so a
+// cache outage never fails a run.
+func (s *Service) loadCached(ctx context.Context, agent Agent, key models.ResultCacheKey) (Result, bool) {
+	raw, ok, err := s.cache.Get(ctx, key)
+	if err != nil || !ok {
+		return Result{}, false
+	}
+	var cached cachedResult
+	if err := json.Unmarshal(raw, &cached); err != nil {
+		return Result{}, false
+	}
+	data, err := agent.(Cacheable).DecodeData(cached.Data)
+	if err != nil {
+		return Result{}, false
+	}
+	return Result{
+		Summary:   cached.Summary,
+		Issues:    cached.Issues,
+		Metrics:   cached.Metrics,
+		Data:      data,
+		Artifacts: cached.Artifacts,
+	}, true
+}
+
+// storeCached saves a chunk result. Failures are ignored; the next run recomputes.
+func (s *Service) storeCached(ctx context.Context, key models.ResultCacheKey, result Result) {
+	data, err := json.Marshal(result.Data)
+	if err != nil {
+		return
+	}
+	raw, err := json.Marshal(cachedResult{
+		Summary:   result.Summary,
+		Issues:    result.Issues,
+		Metrics:   result.Metrics,
+		Data:      data,
+		Artifacts: result.Artifacts,
+	})
+	if err != nil {
+		return
+	}
+	_ = s.cache.Put(ctx, key, raw)
+}
@@ -0,0 +1,99 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+type memCache struct {
+	mu      sync.Mutex
+	entries map[models.ResultCacheKey]json.RawMessage
+}
+
+func (m *memCache) Get(_ context.Context, key models.ResultCacheKey) (json.RawMessage, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	raw, ok := m.entries[key]
+	return raw, ok, nil
+}
+
+func (m *memCache) Put(_ context.Context, key models.ResultCacheKey, result json.RawMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.entries == nil {
+		m.entries = map[models.ResultCacheKey]json.RawMessage{}
+	}
+	m.entries[key] = result
+	return nil
+}
+
+func TestExecuteRunReusesCachedChunks(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "chapters/01.md", "# One\n\nShe walked quickly to the door. It was opened by the wind.\n")
+	writeFile(t, root, "chapters/02.md", "# Two\n\nHe waits by the window and watches the rain.\n")
+	cache := &memCache{}
+	store := newMockStore()
+	svc := NewService(store, t.TempDir(), WithWorkspaces(stubWorkspaces{1: root}, nil), WithResultCache(cache))
+	ctx := context.Background()
+	files := []string{"chapters/01.md", "chapters/02.md"}
+
+	runOnce := func() models.AgentRun {
+		t.Helper()
+		if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "style", FilesChanged: files}); err != nil {
+			t.Fatalf("QueueRun returned error: %v", err)
+		}
+		claimed, _ := svc.claimNextRun(ctx)
+		if err := svc.executeRun(ctx, claimed); err != nil {
+			t.Fatalf("executeRun returned error: %v", err)
+		}
+		run, _ := store.GetRun(ctx, claimed.ID)
+		return run
+	}
+
+	first := runOnce()
+	if first.Results.Stats.CacheHits != 0 || len(cache.entries) != 2 {
+		t.Fatalf("expected a cold first run to fill the cache, got stats %+v and %d entries", first.Results.Stats, len(cache.entries))
+	}
+
+	second := runOnce()
+	if second.Results.Stats.CacheHits != 2 || second.Results.Stats.ChunksAnalyzed != 2 {
+		t.Fatalf("expected both chunks served from the cache, got %+v", second.Results.Stats)
+	}
+	if !reflect.DeepEqual(first.Results.Metrics, second.Results.Metrics) || len(first.Results.Issues) != len(second.Results.Issues) {
+		t.Fatalf("expected cached chunks to reduce to the same result, got %+v and %+v", first.Results, second.Results)
+	}
+
+	writeFile(t, root, "chapters/02.md", "# Two\n\nHe waited by the window and watched the rain fall.\n")
+	third := runOnce()
+	if third.Results.Stats.CacheHits != 1 {
+		t.Fatalf("expected only the unchanged chapter to hit the cache, got %+v", third.Results.Stats)
+	}
+}
+
+func TestCacheKeyTracksPromptAndModel(t *testing.T) {
+	svc := NewService(newMockStore(), "", WithResultCache(&memCache{}))
+	chunk := manuscript.Chunk{Path: "chapters/01.md", StartLine: 1, EndLine: 1, Content: "Text."}
+	input := Input{Run: models.AgentRun{ProjectID: 1}, Chunk: &chunk}
+
+	base, ok := svc.cacheKey(styleAgent{}, input)
+	if !ok || base.Model != "" || base.PromptVersion != "style/1" {
+		t.Fatalf("expected an offline style key, got %+v %v", base, ok)
+	}
+	if _, ok := svc.cacheKey(continuityAgent{}, input); ok {
+		t.Fatal("expected continuity results not to be cached")
+	}
+
+	input.Prompt, _ = NewPromptTemplate("custom", "Be terse.")
+	input.Provider = NewFakeProvider()
+	input.Model = "test/model"
+	custom, _ := svc.cacheKey(styleAgent{}, input)
+	if custom.Model != "test/model" || custom.PromptVersion == base.PromptVersion || custom.ContentHash != base.ContentHash {
+		t.Fatalf("expected model and prompt to change the key but not the content hash, got %+v", custom)
+	}
+}
@@ -0,0 +1,100 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// DefaultMaxRuntime bounds runs whose agents.yaml entry sets no max_runtime.
+const DefaultMaxRuntime = 30 * time.Minute
+
+var (
+	// ErrRunNotActive is returned when cancelling a run that has already finished.
+	ErrRunNotActive = errors.New("run is not queued or running")
+	ErrRunCancelled = errors.New("run cancelled")
+	ErrRunTimedOut  = errors.New("run exceeded its max runtime")
+	// ErrRunNotRunning is returned when a run finishes after it was cancelled elsewhere;
+	// its outcome is discarded.
+	ErrRunNotRunning = errors.New("run is no longer running")
+)
+
+// WithMaxRuntime sets the default wall-clock limit for a run; zero disables it.
+func WithMaxRuntime(limit time.Duration) Option {
+	return func(s *Service) {
+		s.maxRuntime = limit
+	}
+}
+
+// runtimeLimit returns the agent's max_runtime, falling back to the service default.
+func (s *Service) runtimeLimit(agentCfg AgentConfig) time.Duration {
+	if agentCfg.MaxRuntime > 0 {
+		return agentCfg.MaxRuntime
+	}
+	return s.maxRuntime
+}
+
+// startRun derives the context an agent runs under: cancelled with ErrRunCancelled by
+// CancelRun, or with ErrRunTimedOut once limit elapses. finish must be called when the
+// agent returns. Unsaved runs (ID 0, from Analyze) cannot be cancelled.
+func (s *Service) startRun(ctx context.Context, runID int64, limit time.Duration) (context.Context, func()) {
+	runCtx, cancel := context.WithCancelCause(ctx)
+	stopTimer := func() bool { return false }
+	if limit > 0 {
+		timer := time.AfterFunc(limit, func() { cancel(ErrRunTimedOut) })
+		stopTimer = timer.Stop
+	}
+
+	if runID != 0 {
+		s.activeMu.Lock()
+		s.active[runID] = cancel
+		s.activeMu.Unlock()
+	}
+
+	return runCtx, func() {
+		stopTimer()
+		if runID != 0 {
+			s.activeMu.Lock()
+			delete(s.active, runID)
+			s.activeMu.Unlock()
+		}
+		cancel(nil)
+	}
+}
+
+// CancelRun stops a queued or running run. A run executing in this process has its
+// context cancelled; one claimed by another instance is marked cancelled and its
+// outcome is discarded when it finishes.
+func (s *Service) CancelRun(ctx context.Context, projectID, runID int64) (models.AgentRun, error) {
+	run, err := s.store.GetRun(ctx, runID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.AgentRun{}, ErrRunNotFound
+		}
+		return models.AgentRun{}, err
+	}
+	if run.ProjectID != projectID {
+		return models.AgentRun{}, ErrRunNotFound
+	}
+
+	if err := s.store.MarkCancelled(ctx, runID, s.now()); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.AgentRun{}, ErrRunNotActive
+		}
+		return models.AgentRun{}, fmt.Errorf("cancel run: %w", err)
+	}
+
+	s.activeMu.Lock()
+	if cancel, ok := s.active[runID]; ok {
+		cancel(ErrRunCancelled)
+	}
+	s.activeMu.Unlock()
+	s.settleCredits(ctx, runID, false)
+	s.publish(ctx, run, models.RunEvent{Type: models.RunEventCancelled})
+	s.cancelDependents(ctx, run)
+
+	return s.store.GetRun(ctx, runID)
+}
@@ -0,0 +1,139 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// blockingAgent runs until its context is cancelled.
+type blockingAgent struct {
+	started chan struct{}
+}
+
+func (a *blockingAgent) Name() string           { return "slow" }
+func (a *blockingAgent) DefaultTrigger() string { return "manual" }
+func (a *blockingAgent) ContextRequirements() ContextRequirements {
+	return ContextRequirements{}
+}
+
+func (a *blockingAgent) Run(ctx context.Context, _ Input) (Result, error) {
+	close(a.started)
+	<-ctx.Done()
+	return Result{}, ctx.Err()
+}
+
+func newBlockingService(t *testing.T, opts ...Option) (*Service, *mockStore, *blockingAgent) {
+	t.Helper()
+	agent := &blockingAgent{started: make(chan struct{})}
+	registry := NewRegistry()
+	_ = registry.Register(agent)
+	store := newMockStore()
+	svc := NewService(store, t.TempDir(), append([]Option{WithRegistry(registry)}, opts...)...)
+	if _, err := svc.QueueRun(context.Background(), RunRequest{ProjectID: 1, AgentType: "slow"}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	return svc, store, agent
+}
+
+func TestCancelRunStopsRunningAgent(t *testing.T) {
+	svc, store, agent := newBlockingService(t)
+	ctx := context.Background()
+	claimed, _ := svc.claimNextRun(ctx)
+
+	done := make(chan error, 1)
+	go func() { done <- svc.executeRun(ctx, claimed) }()
+	<-agent.started
+
+	run, err := svc.CancelRun(ctx, 1, claimed.ID)
+	if err != nil || run.Status != "cancelled" {
+		t.Fatalf("expected the run to be cancelled, got %+v, %v", run, err)
+	}
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the agent to see the cancellation, got %v", err)
+	}
+	if run := store.runs[claimed.ID]; run.Status != "cancelled" {
+		t.Fatalf("expected the cancelled status to stick, got %+v", run)
+	}
+	if _, err := svc.CancelRun(ctx, 1, claimed.ID); !errors.Is(err, ErrRunNotActive) {
+		t.Fatalf("expected ErrRunNotActive, got %v", err)
+	}
+}
+
+func TestCancelRunRemovesQueuedRun(t *testing.T) {
+	svc, _, _ := newBlockingService(t)
+	ctx := context.Background()
+
+	if _, err := svc.CancelRun(ctx, 1, 1); err != nil {
+		t.Fatalf("CancelRun returned error: %v", err)
+	}
+	if _, err := svc.claimNextRun(ctx); err == nil {
+		t.Fatalf("expected a cancelled run not to be claimed")
+	}
+	if _, err := svc.CancelRun(ctx, 2, 1); !errors.Is(err, ErrRunNotFound) {
+		t.Fatalf("expected ErrRunNotFound for another project, got %v", err)
+	}
+}
+
+func TestExecuteRunTimesOutAfterMaxRuntime(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, AgentsConfigPath, "agents:\n  slow:\n    max_runtime: 20ms\n")
+	svc, store, _ := newBlockingService(t, WithWorkspaces(stubWorkspaces{1: root}, nil))
+	ctx := context.Background()
+	claimed, _ := svc.claimNextRun(ctx)
+
+	if err := svc.executeRun(ctx, claimed); err == nil {
+		t.Fatalf("expected executeRun to fail")
+	}
+	if run := store.runs[claimed.ID]; run.Status != "timed_out" {
+		t.Fatalf("expected the run to time out rather than retry, got %+v", run)
+	}
+}
+
+func TestExecuteRunDiscardsRunCancelledElsewhere(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+	}{
+		{name: "completed"},
+		{name: "failed", err: errors.New("model refused")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := NewRegistry()
+			_ = registry.Register(&stubAgent{name: "notes", err: tc.err})
+			store := newMockStore()
+			ledger := &memCredits{balance: 10_000}
+			broker := NewBroker()
+			svc := NewService(store, t.TempDir(), WithRegistry(registry), WithCredits(ledger), WithEvents(broker))
+			ctx := context.Background()
+
+			if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "notes"}); err != nil {
+				t.Fatalf("QueueRun returned error: %v", err)
+			}
+			claimed, _ := svc.claimNextRun(ctx)
+			events, unsubscribe := broker.Subscribe(nil)
+			defer unsubscribe()
+
+			// Another instance cancels the run; this one is unaware of it.
+			cancelled := store.runs[claimed.ID]
+			cancelled.Status = "cancelled"
+			store.runs[claimed.ID] = cancelled
+
+			err := svc.executeRun(ctx, claimed)
+			if tc.err == nil && !errors.Is(err, ErrRunNotRunning) {
+				t.Fatalf("expected ErrRunNotRunning, got %v", err)
+			}
+			unsubscribe()
+
+			if run := store.runs[claimed.ID]; run.Status != "cancelled" || run.Results != nil {
+				t.Fatalf("expected the cancellation to stand, got %+v", run)
+			}
+			if len(ledger.settled) != 0 || len(ledger.refunded) != 0 {
+				t.Fatalf("expected no credit settlement, got %+v", ledger)
+			}
+			for event := range events {
+				t.Fatalf("expected no lifecycle events, got %+v", event)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+var (
+	ErrRunNotCompleted   = errors.New("run has not completed")
+	ErrRunsNotComparable = errors.New("runs belong to different agents")
+)
+
+// CompareRuns matches the issues of two completed runs of the same agent by fingerprint
+// and reports which the head run resolved, introduced or still has. Repeated issues are
+// matched one for one.
+func (s *Service) CompareRuns(ctx context.Context, projectID, baseID, headID int64) (models.RunComparison, error) {
+	base, err := s.completedRun(ctx, projectID, baseID)
+	if err != nil {
+		return models.RunComparison{}, err
+	}
+	head, err := s.completedRun(ctx, projectID, headID)
+	if err != nil {
+		return models.RunComparison{}, err
+	}
+	if base.AgentType != head.AgentType {
+		return models.RunComparison{}, fmt.Errorf("%w: %s and %s", ErrRunsNotComparable, base.AgentType, head.AgentType)
+	}
+
+	comparison := models.RunComparison{
+		BaseRunID: base.ID,
+		HeadRunID: head.ID,
+		AgentType: head.AgentType,
+		Resolved:  []models.Issue{},
+		New:       []models.Issue{},
+		Unchanged: []models.Issue{},
+	}
+	// Base issue indexes by fingerprint, consumed in order as head issues match them.
+	unmatched := make(map[string][]int, len(base.Results.Issues))
+	for i, issue := range base.Results.Issues {
+		key := issue.Fingerprint()
+		unmatched[key] = append(unmatched[key], i)
+	}
+	matched := make([]bool, len(base.Results.Issues))
+	for _, issue := range head.Results.Issues {
+		key := issue.Fingerprint()
+		if len(unmatched[key]) == 0 {
+			comparison.New = append(comparison.New, issue)
+			continue
+		}
+		matched[unmatched[key][0]] = true
+		unmatched[key] = unmatched[key][1:]
+		comparison.Unchanged = append(comparison.Unchanged, issue)
+	}
+	for i, issue := range base.Results.Issues {
+		if !matched[i] {
+			comparison.Resolved = append(comparison.Resolved, issue)
+		}
+	}
+	return comparison, nil
+}
+
+// completedRun returns a project's run, requiring that it completed with results.
+func (s *Service) completedRun(ctx context.Context, projectID, runID int64) (models.AgentRun, error) {
+	run, err := s.store.GetRun(ctx, runID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.AgentRun{}, fmt.Errorf("%w: %d", ErrRunNotFound, runID)
+		}
+		return models.AgentRun{}, err
+	}
+	if run.ProjectID != projectID {
+		return models.AgentRun{}, fmt.Errorf("%w: %d", ErrRunNotFound, runID)
+	}
+	if run.Status != "completed" || run.Results == nil {
+		return models.AgentRun{}, fmt.Errorf("%w: run %d is %s", ErrRunNotCompleted, runID, run.Status)
+	}
+	return run, nil
+}
@@ -0,0 +1,51 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+func TestCompareRunsMatchesIssuesByFingerprint(t *testing.T) {
+	store := newMockStore()
+	eyes := models.Issue{ID: "continuity-a", Category: "continuity", File: "chapters/02.md", StartLine: 3, Excerpt: "her blue eyes", Message: "Eye colour changes."}
+	dead := models.Issue{ID: "continuity-b", Category: "continuity", File: "chapters/02.md", StartLine: 9, Excerpt: `"You came back," Tom said.`, Message: "Tom died in chapter one."}
+	hair := models.Issue{ID: "continuity-c", Category: "continuity", File: "chapters/03.md", StartLine: 4, Message: "Hair colour changes."}
+
+	movedEyes := eyes
+	movedEyes.ID, movedEyes.StartLine, movedEyes.Excerpt = "continuity-d", 12, "her  Blue eyes"
+	store.runs[1] = models.AgentRun{ID: 1, ProjectID: 1, AgentType: "continuity", Status: "completed",
+		Results: &models.RunResult{Issues: []models.Issue{eyes, dead, dead}}}
+	store.runs[2] = models.AgentRun{ID: 2, ProjectID: 1, AgentType: "continuity", Status: "completed",
+		Results: &models.RunResult{Issues: []models.Issue{movedEyes, dead, hair}}}
+	store.runs[3] = models.AgentRun{ID: 3, ProjectID: 1, AgentType: "style", Status: "completed", Results: &models.RunResult{}}
+	store.runs[4] = models.AgentRun{ID: 4, ProjectID: 1, AgentType: "continuity", Status: "running"}
+	svc := NewService(store, t.TempDir())
+	ctx := context.Background()
+
+	comparison, err := svc.CompareRuns(ctx, 1, 1, 2)
+	if err != nil {
+		t.Fatalf("CompareRuns returned error: %v", err)
+	}
+	if len(comparison.Unchanged) != 2 || comparison.Unchanged[0].StartLine != 12 {
+		t.Fatalf("expected the moved eye colour issue and one dead character issue to persist, got %+v", comparison.Unchanged)
+	}
+	if len(comparison.Resolved) != 1 || comparison.Resolved[0].ID != "continuity-b" {
+		t.Fatalf("expected the repeated dead character issue to be resolved, got %+v", comparison.Resolved)
+	}
+	if len(comparison.New) != 1 || comparison.New[0].ID != "continuity-c" {
+		t.Fatalf("expected the hair issue to be new, got %+v", comparison.New)
+	}
+
+	if _, err := svc.CompareRuns(ctx, 1, 1, 3); !errors.Is(err, ErrRunsNotComparable) {
+		t.Fatalf("expected ErrRunsNotComparable, got %v", err)
+	}
+	if _, err := svc.CompareRuns(ctx, 1, 1, 4); !errors.Is(err, ErrRunNotCompleted) {
+		t.Fatalf("expected ErrRunNotCompleted, got %v", err)
+	}
+	if _, err := svc.CompareRuns(ctx, 2, 1, 2); !errors.Is(err, ErrRunNotFound) {
+		t.Fatalf("expected ErrRunNotFound for another project, got %v", err)
+	}
+}
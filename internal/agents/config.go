@@ -0,0 +1,79 @@
+package agents
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AgentsConfigPath is the repo-relative path of a project's agent configuration.
+const AgentsConfigPath = ".draftforge/agents.yaml"
+
+var (
+	ErrAgentDisabled      = errors.New("agent disabled for this project")
+	ErrInvalidAgentConfig = errors.New("invalid agent configuration")
+)
+
+// ProjectConfig is a project's .draftforge/agents.yaml.
+type ProjectConfig struct {
+	Agents map[string]AgentConfig `yaml:"agents"`
+}
+
+// AgentConfig holds the per-agent settings from agents.yaml. Zero values fall back to
+// the service defaults; agents not listed in the file are enabled.
+type AgentConfig struct {
+	Enabled        *bool    `yaml:"enabled"`
+	Model          string   `yaml:"model"`
+	FallbackModels []string `yaml:"fallback_models"`
+	Temperature    *float64 `yaml:"temperature"`
+	MaxTokens      int      `yaml:"max_tokens"`
+	// MaxRuntime bounds a run's wall-clock time (e.g. "10m"); the run is cancelled and
+	// marked timed_out when it is exceeded.
+	MaxRuntime time.Duration `yaml:"max_runtime"`
+	// Prompt is a repo-relative path to a prompt template that replaces the agent's
+	// built-in one. It defaults to .draftforge/prompts/<agent>.md when that file exists.
+	Prompt string `yaml:"prompt"`
+}
+
+// IsEnabled reports whether the agent may run; agents are enabled unless set to false.
+func (c AgentConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// Agent returns the settings for the named agent.
+func (c ProjectConfig) Agent(name string) AgentConfig {
+	return c.Agents[name]
+}
+
+// LoadProjectConfig reads .draftforge/agents.yaml from a project's working tree. A
+// missing file yields an empty configuration.
+func LoadProjectConfig(root string) (ProjectConfig, error) {
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(AgentsConfigPath)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return ProjectConfig{}, nil
+	}
+	if err != nil {
+		return ProjectConfig{}, fmt.Errorf("read %s: %w", AgentsConfigPath, err)
+	}
+
+	var cfg ProjectConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return ProjectConfig{}, fmt.Errorf("%w: %s: %v", ErrInvalidAgentConfig, AgentsConfigPath, err)
+	}
+	for name, agent := range cfg.Agents {
+		switch {
+		case agent.Temperature != nil && (*agent.Temperature < 0 || *agent.Temperature > 2):
+			return ProjectConfig{}, fmt.Errorf("%w: %s: temperature must be between 0 and 2", ErrInvalidAgentConfig, name)
+		case agent.MaxTokens < 0:
+			return ProjectConfig{}, fmt.Errorf("%w: %s: max_tokens must be positive", ErrInvalidAgentConfig, name)
+		case agent.MaxRuntime < 0:
+			return ProjectConfig{}, fmt.Errorf("%w: %s: max_runtime must be positive", ErrInvalidAgentConfig, name)
+		}
+	}
+	return cfg, nil
+}
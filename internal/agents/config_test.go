@@ -0,0 +1,168 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const routedAgentsYAML = `agents:
+  notes:
+    model: primary/model
+    fallback_models: [backup/model]
+    temperature: 0.4
+    max_tokens: 900
+    prompt: .draftforge/prompts/notes.md
+  style:
+    enabled: false
+`
+
+func TestLoadProjectConfig(t *testing.T) {
+	root := t.TempDir()
+	cfg, err := LoadProjectConfig(root)
+	if err != nil || len(cfg.Agents) != 0 {
+		t.Fatalf("expected empty config without agents.yaml, got %+v, %v", cfg, err)
+	}
+
+	writeFile(t, root, AgentsConfigPath, routedAgentsYAML)
+	cfg, err = LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig returned error: %v", err)
+	}
+	notes := cfg.Agent("notes")
+	if notes.Model != "primary/model" || len(notes.FallbackModels) != 1 || *notes.Temperature != 0.4 || notes.MaxTokens != 900 {
+		t.Fatalf("unexpected notes config %+v", notes)
+	}
+	if !notes.IsEnabled() || cfg.Agent("style").IsEnabled() || !cfg.Agent("timeline").IsEnabled() {
+		t.Fatalf("unexpected enabled flags %+v", cfg.Agents)
+	}
+
+	writeFile(t, root, AgentsConfigPath, "agents:\n  style:\n    temperature: 3\n")
+	if _, err := LoadProjectConfig(root); !errors.Is(err, ErrInvalidAgentConfig) {
+		t.Fatalf("expected ErrInvalidAgentConfig, got %v", err)
+	}
+}
+
+type failingProvider struct {
+	failModels map[string]bool
+	fake       *FakeProvider
+}
+
+func (p failingProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	if p.failModels[req.Model] {
+		return CompletionResponse{}, &ProviderError{StatusCode: 503, Message: "unavailable"}
+	}
+	return p.fake.Complete(ctx, req)
+}
+
+func TestRoutedProviderFallsBack(t *testing.T) {
+	fake := NewFakeProvider(CompletionResponse{Content: "ok"})
+	temperature := 0.4
+	provider := routedProvider{
+		provider:    failingProvider{failModels: map[string]bool{"primary/model": true}, fake: fake},
+		fallbacks:   []string{"backup/model"},
+		temperature: &temperature,
+		maxTokens:   900,
+	}
+
+	resp, err := provider.Complete(context.Background(), CompletionRequest{Model: "primary/model"})
+	if err != nil || resp.Model != "backup/model" {
+		t.Fatalf("expected fallback response, got %+v, %v", resp, err)
+	}
+	reqs := fake.Requests()
+	if len(reqs) != 1 || *reqs[0].Temperature != 0.4 || reqs[0].MaxTokens != 900 {
+		t.Fatalf("expected routed settings on the request, got %+v", reqs)
+	}
+
+	provider.fallbacks = nil
+	if _, err := provider.Complete(context.Background(), CompletionRequest{Model: "primary/model"}); err == nil || !strings.Contains(err.Error(), "model primary/model") {
+		t.Fatalf("expected the last model error, got %v", err)
+	}
+}
+
+// statusProvider fails models with the given status and answers the rest from fake.
+type statusProvider struct {
+	status map[string]int
+	fake   *FakeProvider
+}
+
+func (p statusProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	if code := p.status[req.Model]; code != 0 {
+		return CompletionResponse{}, &ProviderError{StatusCode: code, Message: http.StatusText(code)}
+	}
+	return p.fake.Complete(ctx, req)
+}
+
+func TestRoutedProviderStopsOnRequestErrors(t *testing.T) {
+	fake := NewFakeProvider(CompletionResponse{Content: "ok"})
+	provider := routedProvider{
+		provider:  statusProvider{status: map[string]int{"primary/model": 401, "rate/limited": 429, "bad/request": 400}, fake: fake},
+		fallbacks: []string{"backup/model"},
+	}
+
+	_, err := provider.Complete(context.Background(), CompletionRequest{Model: "primary/model"})
+	var providerErr *ProviderError
+	if !errors.As(err, &providerErr) || providerErr.StatusCode != 401 || len(fake.Requests()) != 0 {
+		t.Fatalf("expected the auth error without trying the fallback, got %v", err)
+	}
+
+	provider.fallbacks = []string{"bad/request", "backup/model"}
+	_, err = provider.Complete(context.Background(), CompletionRequest{Model: "rate/limited"})
+	if err == nil || !strings.Contains(err.Error(), "model rate/limited") || !strings.Contains(err.Error(), "model bad/request") {
+		t.Fatalf("expected every attempt in the error, got %v", err)
+	}
+	if len(fake.Requests()) != 0 {
+		t.Fatalf("expected no fallback after a request error, got %+v", fake.Requests())
+	}
+}
+
+func TestQueueRunRejectsDisabledAgent(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, AgentsConfigPath, routedAgentsYAML)
+	svc := NewService(newMockStore(), t.TempDir(), WithWorkspaces(stubWorkspaces{1: root}, nil))
+
+	_, err := svc.QueueRun(context.Background(), RunRequest{ProjectID: 1, AgentType: "style"})
+	if !errors.Is(err, ErrAgentDisabled) {
+		t.Fatalf("expected ErrAgentDisabled, got %v", err)
+	}
+}
+
+func TestExecuteRunAppliesAgentConfig(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, AgentsConfigPath, routedAgentsYAML)
+	writeFile(t, root, ".draftforge/prompts/notes.md", "List every named character.")
+
+	provider := NewFakeProvider(CompletionResponse{Content: "Mara and Tom."})
+	registry := NewRegistry()
+	_ = registry.Register(&promptAgent{name: "notes", trigger: "manual", instructions: "Summarise the changes."})
+	store := newMockStore()
+	svc := NewService(store, t.TempDir(),
+		WithRegistry(registry),
+		WithProvider(provider, "test/model"),
+		WithWorkspaces(stubWorkspaces{1: root}, nil),
+	)
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "notes"}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	claimed, _ := svc.claimNextRun(ctx)
+	if err := svc.executeRun(ctx, claimed); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+
+	reqs := provider.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("expected one request, got %+v", reqs)
+	}
+	req := reqs[0]
+	if req.Model != "primary/model" || req.MaxTokens != 900 || req.Temperature == nil || *req.Temperature != 0.4 {
+		t.Fatalf("expected configured model settings, got %+v", req)
+	}
+	system := req.Messages[0].Content
+	if !strings.Contains(system, "List every named character.") || strings.Contains(system, "Summarise the changes.") {
+		t.Fatalf("expected the custom prompt to replace the built-in one, got %q", system)
+	}
+}
@@ -0,0 +1,620 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+var continuityInstructions = mustBuiltinPrompt("continuity")
+
+const continuityFormatInstructions = `Reply with a single JSON object and nothing else:
+{"summary": "<one paragraph>", "facts": [{"subject": "<character, place or object>", "kind": "attribute|relationship|location|object|status", "attribute": "<e.g. eyes, sister, lives in>", "value": "<value>", "line": <int>, "excerpt": "<exact quoted text>"}], "issues": [{"severity": "info|warning|error", "category": "<short category>", "message": "<what is wrong>", "file": "<repo-relative path>", "start_line": <int>, "end_line": <int>, "excerpt": "<exact quoted text>", "suggestion": "<how to fix>", "confidence": <0..1>}]}`
+
+// maxLedgerNotes caps how many established facts are listed in a chunk's prompt.
+const maxLedgerNotes = 40
+
+// LedgerStore persists the continuity fact ledger. Facts are replaced per source file and
+// each source's content hash is recorded so unchanged chapters can be skipped.
+type LedgerStore interface {
+	SourceHashes(ctx context.Context, projectID int64) (map[string]string, error)
+	ListFacts(ctx context.Context, projectID int64) ([]models.ContinuityFact, error)
+	// ReplaceSources swaps the facts and hashes of every given source in one transaction.
+	ReplaceSources(ctx context.Context, projectID int64, sources []models.ContinuitySource) error
+}
+
+// ledgerUpdate is the ledger refresh a continuity run has worked out. The Service
+// commits it only once the run is recorded as completed, so a run that fails later
+// leaves the ledger untouched and its chapters are analysed again next time.
+type ledgerUpdate struct {
+	ledger    LedgerStore
+	projectID int64
+	sources   []models.ContinuitySource
+}
+
+func (u ledgerUpdate) commit(ctx context.Context) error {
+	if u.ledger == nil || len(u.sources) == 0 {
+		return nil
+	}
+	return u.ledger.ReplaceSources(ctx, u.projectID, u.sources)
+}
+
+var colourWords = map[string]bool{
+	"black": true, "brown": true, "blue": true, "green": true, "grey": true, "gray": true,
+	"hazel": true, "amber": true, "red": true, "blonde": true, "blond": true, "auburn": true,
+	"white": true, "silver": true, "golden": true, "violet": true, "dark": true, "pale": true,
+}
+
+var relationshipWords = map[string]bool{
+	"brother": true, "sister": true, "mother": true, "father": true, "husband": true, "wife": true,
+	"son": true, "daughter": true, "uncle": true, "aunt": true, "cousin": true, "partner": true,
+	"spouse": true, "friend": true, "mentor": true,
+}
+
+// attributeAliases maps story-bible field names to the attribute names used in the ledger.
+var attributeAliases = map[string]string{
+	"eye": "eyes", "eye colour": "eyes", "eye color": "eyes", "eyes": "eyes",
+	"hair": "hair", "hair colour": "hair", "hair color": "hair",
+	"state": "status", "status": "status",
+}
+
+var deadValues = map[string]bool{"dead": true, "deceased": true, "died": true, "killed": true}
+
+var (
+	colourBeforePattern = regexp.MustCompile(`\b([A-Z][a-z]+)'s\s+([a-z]+)\s+(eyes|hair)\b`)
+	colourAfterPattern  = regexp.MustCompile(`\b([A-Z][a-z]+)'s\s+(eyes|hair)\s+(?:were|was|are|is|had turned)\s+([a-z]+)`)
+	relationPattern     = regexp.MustCompile(`\b([A-Z][a-z]+)'s\s+([a-z]+),?\s+([A-Z][a-z]+)\b`)
+	deathPattern        = regexp.MustCompile(`\b([A-Z][a-z]+)\s+(?:died|was killed|was dead|lay dead|is dead)\b`)
+	actionPattern       = regexp.MustCompile(`\b([A-Z][a-z]+)\s+(?:said|asked|replied|smiled|nodded|laughed|walked|ran|stood|shouted|whispered|grinned|frowned|sat|turned)\b`)
+	fieldPattern        = regexp.MustCompile(`^\s*(?:[-*+]\s+)?\**([A-Za-z][A-Za-z ]{0,30}?)\**\s*:\s*\**\s*(.+?)\s*$`)
+)
+
+// continuityChunk is the per-chunk payload handed from Run to Reduce.
+type continuityChunk struct {
+	Path string
+	Hash string
+	// Skipped is set when the chapter's content hash matches the ledger.
+	Skipped     bool
+	Facts       []models.ContinuityFact
+	Appearances []appearance
+}
+
+// appearance records a character speaking or acting on the page.
+type appearance struct {
+	Subject string
+	File    string
+	Line    int
+	Excerpt string
+}
+
+// continuityAgent (ContinuityBot) keeps a per-project ledger of facts established by the
+// story bible and chapter text, and checks changed chapters against it. Without a
+// LedgerStore it still checks the run against the story bible but remembers nothing.
+type continuityAgent struct {
+	ledger LedgerStore
+}
+
+func (continuityAgent) Name() string           { return "continuity" }
+func (continuityAgent) DefaultTrigger() string { return "pr" }
+func (continuityAgent) ContextRequirements() ContextRequirements {
+	return ContextRequirements{ChangedFiles: true, StoryBible: true}
+}
+
+func (a continuityAgent) Run(ctx context.Context, input Input) (Result, error) {
+	if input.Chunk == nil {
+		return Result{Summary: "No chapters to analyse."}, nil
+	}
+	chunk := *input.Chunk
+
+	hash := changedHash(input.Context, chunk.Path)
+	if a.ledger != nil && hash != "" {
+		hashes, err := a.ledger.SourceHashes(ctx, input.Run.ProjectID)
+		if err != nil {
+			return Result{}, fmt.Errorf("load ledger: %w", err)
+		}
+		if hashes[chunk.Path] == hash {
+			return Result{Data: continuityChunk{Path: chunk.Path, Hash: hash, Skipped: true}}, nil
+		}
+	}
+
+	facts, appearances := extractChapterFacts(chunk)
+	result := Result{}
+
+	if input.Provider != nil {
+		notes, err := a.ledgerNotes(ctx, input.Run.ProjectID, chunk)
+		if err != nil {
+			return Result{}, err
+		}
+		instructions, err := input.instructions(continuityInstructions)
+		if err != nil {
+			return Result{}, err
+		}
+		content, usage, err := completeWithContext(ctx, input, a.Name(), instructions+"\n\n"+continuityFormatInstructions, notes)
+		if err != nil {
+			return Result{}, err
+		}
+		result.Usage = usage
+		if parsed, err := parseModelResult(content); err == nil {
+			result.Summary = parsed.Summary
+			result.Issues = parsed.Issues
+			facts = mergeFacts(facts, parseModelFacts(content, chunk))
+		} else {
+			result.Summary = strings.TrimSpace(content)
+		}
+	}
+
+	result.Data = continuityChunk{Path: chunk.Path, Hash: hash, Facts: facts, Appearances: appearances}
+	return result, nil
+}
+
+// Reduce refreshes the ledger from changed story-bible documents and analysed chapters
+// and reports contradictions against what the ledger already holds. The new facts are
+// returned as a ledgerUpdate in the result's Data rather than saved here.
+func (a continuityAgent) Reduce(ctx context.Context, input Input, results []Result) (Result, error) {
+	var merged Result
+	var summaries []string
+	chapters := map[string]*continuityChunk{}
+	var order []string
+	for _, r := range results {
+		merged.Issues = append(merged.Issues, r.Issues...)
+		merged.Usage.PromptTokens += r.Usage.PromptTokens
+		merged.Usage.CompletionTokens += r.Usage.CompletionTokens
+		merged.Usage.TotalTokens += r.Usage.TotalTokens
+		if s := strings.TrimSpace(r.Summary); s != "" {
+			summaries = append(summaries, s)
+		}
+		c, ok := r.Data.(continuityChunk)
+		if !ok {
+			continue
+		}
+		if existing, ok := chapters[c.Path]; ok {
+			existing.Facts = append(existing.Facts, c.Facts...)
+			existing.Appearances = append(existing.Appearances, c.Appearances...)
+			existing.Skipped = existing.Skipped && c.Skipped
+			continue
+		}
+		chapter := c
+		chapters[c.Path] = &chapter
+		order = append(order, c.Path)
+	}
+
+	hashes := map[string]string{}
+	var stored []models.ContinuityFact
+	if a.ledger != nil {
+		var err error
+		if hashes, err = a.ledger.SourceHashes(ctx, input.Run.ProjectID); err != nil {
+			return Result{}, fmt.Errorf("load ledger: %w", err)
+		}
+		if stored, err = a.ledger.ListFacts(ctx, input.Run.ProjectID); err != nil {
+			return Result{}, fmt.Errorf("load ledger: %w", err)
+		}
+	}
+
+	// Sources being re-read this run replace whatever the ledger holds for them.
+	var updates []models.ContinuitySource
+	refreshed := map[string]bool{}
+	for _, doc := range input.Context.ByKind(manuscript.KindCharacter, manuscript.KindWorld) {
+		hash := doc.Hash()
+		if hashes[doc.Path] == hash {
+			continue
+		}
+		updates = append(updates, models.ContinuitySource{Path: doc.Path, Hash: hash, Facts: extractReferenceFacts(doc)})
+		refreshed[doc.Path] = true
+	}
+	skipped := 0
+	for _, p := range order {
+		c := chapters[p]
+		if c.Skipped {
+			skipped++
+			continue
+		}
+		updates = append(updates, models.ContinuitySource{Path: c.Path, Hash: c.Hash, Facts: c.Facts})
+		refreshed[c.Path] = true
+	}
+
+	var established, staleChapters []models.ContinuityFact
+	for _, fact := range stored {
+		if refreshed[fact.SourcePath] {
+			continue
+		}
+		established = append(established, fact)
+		if !isReferencePath(fact.SourcePath) {
+			staleChapters = append(staleChapters, fact)
+		}
+	}
+
+	var issues []models.Issue
+	var newFacts []models.ContinuityFact
+	for _, u := range updates {
+		for _, fact := range u.Facts {
+			fact.SourcePath = u.Path
+			newFacts = append(newFacts, fact)
+		}
+	}
+	sort.SliceStable(newFacts, func(i, j int) bool { return factBefore(newFacts[i], newFacts[j]) })
+	for _, fact := range newFacts {
+		if isReferencePath(fact.SourcePath) {
+			// A changed story bible can contradict chapters that were not re-read.
+			for _, old := range staleChapters {
+				if factsConflict(old, fact) {
+					issues = append(issues, contradictionIssue(old, fact, true))
+				}
+			}
+		} else {
+			for _, prior := range established {
+				if factsConflict(prior, fact) {
+					issues = append(issues, contradictionIssue(fact, prior, false))
+					break
+				}
+			}
+		}
+		established = append(established, fact)
+	}
+
+	for _, p := range order {
+		for _, seen := range chapters[p].Appearances {
+			for _, fact := range established {
+				if fact.Kind == models.FactStatus && deadValues[normaliseValue(fact.Value)] &&
+					sameSubject(fact.Subject, seen.Subject) && factBefore(fact, models.ContinuityFact{SourcePath: seen.File, Line: seen.Line}) {
+					issues = append(issues, models.Issue{
+						Severity:   models.SeverityError,
+						Category:   "continuity",
+						Message:    fmt.Sprintf("%s appears here, but is recorded as dead in %s.", seen.Subject, factLocation(fact)),
+						File:       seen.File,
+						StartLine:  seen.Line,
+						EndLine:    seen.Line,
+						Excerpt:    seen.Excerpt,
+						Suggestion: "Check whether this is a flashback or the wrong character.",
+						Confidence: 0.7,
+					})
+					break
+				}
+			}
+		}
+	}
+
+	if a.ledger != nil {
+		merged.Data = ledgerUpdate{ledger: a.ledger, projectID: input.Run.ProjectID, sources: updates}
+	}
+
+	merged.Issues = dedupeIssues(append(merged.Issues, issues...))
+	merged.Metrics = map[string]any{
+		"ledger_facts":     len(established),
+		"facts_recorded":   len(newFacts),
+		"chapters_skipped": skipped,
+		"contradictions":   len(issues),
+	}
+	merged.Summary = fmt.Sprintf("Checked %d chapters against %d ledger facts; %d contradictions.", len(order)-skipped, len(established), len(issues))
+	if skipped > 0 {
+		merged.Summary += fmt.Sprintf(" %d unchanged chapters skipped.", skipped)
+	}
+	if len(summaries) > 0 {
+		merged.Summary += "\n\n" + strings.Join(summaries, "\n\n")
+	}
+	return merged, nil
+}
+
+// ledgerNotes lists established facts about subjects mentioned in the chunk.
+func (a continuityAgent) ledgerNotes(ctx context.Context, projectID int64, chunk manuscript.Chunk) (string, error) {
+	if a.ledger == nil {
+		return "", nil
+	}
+	facts, err := a.ledger.ListFacts(ctx, projectID)
+	if err != nil {
+		return "", fmt.Errorf("load ledger: %w", err)
+	}
+
+	var lines []string
+	for _, fact := range facts {
+		if fact.SourcePath == chunk.Path || !strings.Contains(chunk.Content, firstWord(fact.Subject)) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- %s / %s: %s (%s)", fact.Subject, fact.Attribute, fact.Value, factLocation(fact)))
+		if len(lines) == maxLedgerNotes {
+			break
+		}
+	}
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return "Established facts:\n" + strings.Join(lines, "\n"), nil
+}
+
+// extractChapterFacts finds eye and hair colours, family relationships, deaths and
+// characters acting on the page in a chunk's narration (dialogue is ignored).
+func extractChapterFacts(chunk manuscript.Chunk) ([]models.ContinuityFact, []appearance) {
+	var facts []models.ContinuityFact
+	var appearances []appearance
+
+	skip := 0
+	if chunk.StartLine == 1 {
+		if _, end, err := manuscript.ParseFrontmatter(chunk.Content); err == nil {
+			skip = end
+		}
+	}
+	inFence := false
+	for i, raw := range strings.Split(chunk.Content, "\n") {
+		trimmed := strings.TrimSpace(raw)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || i < skip || i < chunk.OverlapLines || trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "<!--") {
+			continue
+		}
+
+		line := chunk.StartLine + i
+		narration, _ := stripDialogue(trimmed)
+		add := func(kind, subject, attribute, value string) {
+			facts = append(facts, models.ContinuityFact{
+				SourcePath: chunk.Path,
+				Kind:       kind,
+				Subject:    subject,
+				Attribute:  attribute,
+				Value:      value,
+				Line:       line,
+				Excerpt:    excerpt(trimmed),
+			})
+		}
+
+		for _, m := range colourBeforePattern.FindAllStringSubmatch(narration, -1) {
+			if colourWords[m[2]] {
+				add(models.FactAttribute, m[1], m[3], m[2])
+			}
+		}
+		for _, m := range colourAfterPattern.FindAllStringSubmatch(narration, -1) {
+			if colourWords[m[3]] {
+				add(models.FactAttribute, m[1], m[2], m[3])
+			}
+		}
+		for _, m := range relationPattern.FindAllStringSubmatch(narration, -1) {
+			if relationshipWords[m[2]] {
+				add(models.FactRelationship, m[1], m[2], m[3])
+			}
+		}
+		for _, m := range deathPattern.FindAllStringSubmatch(narration, -1) {
+			add(models.FactStatus, m[1], "status", "dead")
+		}
+		for _, m := range actionPattern.FindAllStringSubmatch(narration, -1) {
+			appearances = append(appearances, appearance{Subject: m[1], File: chunk.Path, Line: line, Excerpt: excerpt(trimmed)})
+		}
+	}
+	return facts, appearances
+}
+
+// extractReferenceFacts reads "Field: value" lines (and frontmatter fields) from a
+// character or world document. The subject is the frontmatter name, the first heading or
+// the file name, in that order.
+func extractReferenceFacts(doc manuscript.Document) []models.ContinuityFact {
+	fields, end, _ := manuscript.ParseFrontmatter(doc.Content)
+	subject, _ := fields["name"].(string)
+	lines := strings.Split(doc.Content, "\n")
+	if subject == "" {
+		for _, l := range lines[end:] {
+			if strings.HasPrefix(l, "# ") {
+				subject = strings.TrimSpace(strings.TrimPrefix(l, "# "))
+				break
+			}
+		}
+	}
+	if subject == "" {
+		base := strings.TrimSuffix(path.Base(doc.Path), path.Ext(doc.Path))
+		words := strings.Fields(strings.NewReplacer("-", " ", "_", " ").Replace(base))
+		for i, w := range words {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+		subject = strings.Join(words, " ")
+	}
+
+	defaultKind := models.FactAttribute
+	if doc.Kind == manuscript.KindWorld {
+		defaultKind = models.FactLocation
+	}
+	var facts []models.ContinuityFact
+	add := func(key, value string, line int, text string) {
+		attribute := strings.ToLower(strings.TrimSpace(key))
+		if alias, ok := attributeAliases[attribute]; ok {
+			attribute = alias
+		}
+		if attribute == "name" || attribute == "title" || value == "" {
+			return
+		}
+		kind := defaultKind
+		switch {
+		case relationshipWords[attribute]:
+			kind = models.FactRelationship
+		case attribute == "status":
+			kind = models.FactStatus
+			if deadValues[normaliseValue(value)] {
+				value = "dead"
+			}
+		}
+		facts = append(facts, models.ContinuityFact{
+			SourcePath: doc.Path,
+			Kind:       kind,
+			Subject:    subject,
+			Attribute:  attribute,
+			Value:      value,
+			Line:       line,
+			Excerpt:    excerpt(text),
+		})
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		switch v := fields[key].(type) {
+		case string, int, float64, bool:
+			value := fmt.Sprint(v)
+			add(key, value, 1, key+": "+value)
+		}
+	}
+	for i, l := range lines[end:] {
+		if m := fieldPattern.FindStringSubmatch(l); m != nil && len(strings.Fields(m[1])) <= 3 {
+			add(m[1], strings.Trim(m[2], "* "), end+i+1, strings.TrimSpace(l))
+		}
+	}
+	return facts
+}
+
+// parseModelFacts reads the facts list from a model reply, keeping facts anchored to
+// non-overlap lines of the chunk.
+func parseModelFacts(content string, chunk manuscript.Chunk) []models.ContinuityFact {
+	var reply struct {
+		Facts []models.ContinuityFact `json:"facts"`
+	}
+	if err := json.Unmarshal([]byte(stripCodeFence(content)), &reply); err != nil {
+		return nil
+	}
+
+	var facts []models.ContinuityFact
+	for _, f := range reply.Facts {
+		if f.Line < chunk.StartLine+chunk.OverlapLines || f.Line > chunk.EndLine {
+			continue
+		}
+		f.Subject, f.Attribute, f.Value = strings.TrimSpace(f.Subject), strings.ToLower(strings.TrimSpace(f.Attribute)), strings.TrimSpace(f.Value)
+		if f.Subject == "" || f.Attribute == "" || f.Value == "" {
+			continue
+		}
+		if alias, ok := attributeAliases[f.Attribute]; ok {
+			f.Attribute = alias
+		}
+		switch f.Kind {
+		case models.FactAttribute, models.FactRelationship, models.FactLocation, models.FactObject, models.FactStatus:
+		default:
+			f.Kind = models.FactAttribute
+		}
+		if f.Kind == models.FactStatus && deadValues[normaliseValue(f.Value)] {
+			f.Value = "dead"
+		}
+		f.ID, f.ProjectID, f.SourcePath = 0, 0, chunk.Path
+		f.Excerpt = excerpt(f.Excerpt)
+		facts = append(facts, f)
+	}
+	return facts
+}
+
+// mergeFacts adds model facts the deterministic pass did not already find on that line.
+func mergeFacts(facts, modelFacts []models.ContinuityFact) []models.ContinuityFact {
+	seen := map[string]bool{}
+	key := func(f models.ContinuityFact) string {
+		return fmt.Sprintf("%d|%s|%s|%s", f.Line, strings.ToLower(f.Subject), f.Attribute, normaliseValue(f.Value))
+	}
+	for _, f := range facts {
+		seen[key(f)] = true
+	}
+	for _, f := range modelFacts {
+		if !seen[key(f)] {
+			seen[key(f)] = true
+			facts = append(facts, f)
+		}
+	}
+	return facts
+}
+
+// factsConflict reports whether two single-valued attribute facts about the same subject
+// disagree. Values that contain one another ("green" and "green, flecked with gold") agree.
+func factsConflict(a, b models.ContinuityFact) bool {
+	if a.Kind != models.FactAttribute || b.Kind != models.FactAttribute || a.Attribute != b.Attribute || !sameSubject(a.Subject, b.Subject) {
+		return false
+	}
+	va, vb := normaliseValue(a.Value), normaliseValue(b.Value)
+	return va != vb && !containsWord(va, vb) && !containsWord(vb, va)
+}
+
+func contradictionIssue(at, other models.ContinuityFact, bibleChanged bool) models.Issue {
+	message := fmt.Sprintf("%s's %s is %q here, but %q in %s.", at.Subject, at.Attribute, at.Value, other.Value, factLocation(other))
+	suggestion := "Update the chapter or the story bible so they agree."
+	if bibleChanged {
+		message = fmt.Sprintf("%s's %s is %q here, but %s now says %q.", at.Subject, at.Attribute, at.Value, other.SourcePath, other.Value)
+	}
+	return models.Issue{
+		Severity:   models.SeverityWarning,
+		Category:   "continuity",
+		Message:    message,
+		File:       at.SourcePath,
+		StartLine:  at.Line,
+		EndLine:    at.Line,
+		Excerpt:    at.Excerpt,
+		Suggestion: suggestion,
+		Confidence: 0.8,
+	}
+}
+
+// changedHash returns the content hash of the changed file at path, if it was loaded.
+func changedHash(c manuscript.Context, path string) string {
+	for _, doc := range c.Changed {
+		if doc.Path == path {
+			return doc.Hash()
+		}
+	}
+	return ""
+}
+
+// isReferencePath reports whether a ledger source is part of the story bible rather
+// than a chapter. Story-bible facts hold from the start of the story.
+func isReferencePath(p string) bool {
+	return strings.HasPrefix(p, "docs/")
+}
+
+// factBefore orders facts by narrative position: story bible first, then chapters by
+// path and line.
+func factBefore(a, b models.ContinuityFact) bool {
+	if ra, rb := isReferencePath(a.SourcePath), isReferencePath(b.SourcePath); ra != rb {
+		return ra
+	}
+	if a.SourcePath != b.SourcePath {
+		return a.SourcePath < b.SourcePath
+	}
+	return a.Line < b.Line
+}
+
+func factLocation(f models.ContinuityFact) string {
+	if f.Line > 0 {
+		return fmt.Sprintf("%s:%d", f.SourcePath, f.Line)
+	}
+	return f.SourcePath
+}
+
+// sameSubject matches "Mara" against "Mara Venn" as well as exact (case-insensitive) names.
+func sameSubject(a, b string) bool {
+	a, b = strings.ToLower(strings.TrimSpace(a)), strings.ToLower(strings.TrimSpace(b))
+	if a == b {
+		return true
+	}
+	if !strings.Contains(a, " ") || !strings.Contains(b, " ") {
+		return firstWord(a) == firstWord(b)
+	}
+	return false
+}
+
+func firstWord(s string) string {
+	if fields := strings.Fields(s); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+func normaliseValue(s string) string {
+	s = strings.ToLower(strings.Trim(strings.TrimSpace(s), ".!"))
+	return strings.ReplaceAll(s, "gray", "grey")
+}
+
+func containsWord(haystack, word string) bool {
+	for _, w := range wordPattern.FindAllString(haystack, -1) {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
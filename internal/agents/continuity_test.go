@@ -0,0 +1,221 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+const continuityBible = `# Mara Venn
+
+- **Eye colour:** green
+- Hair: black
+- Sister: Ilse
+`
+
+const continuityChapterOne = `# Chapter One
+
+Mara's green eyes caught the light.
+
+Tom died in the fire that night.
+`
+
+const continuityChapterTwo = `# Chapter Two
+
+Mara's eyes were blue in the morning light.
+
+"You came back," Tom said.
+`
+
+type memLedger struct {
+	mu       sync.Mutex
+	hashes   map[string]string
+	facts    map[string][]models.ContinuityFact
+	replaced []string
+}
+
+func newMemLedger() *memLedger {
+	return &memLedger{hashes: map[string]string{}, facts: map[string][]models.ContinuityFact{}}
+}
+
+func (l *memLedger) SourceHashes(_ context.Context, _ int64) (map[string]string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make(map[string]string, len(l.hashes))
+	for k, v := range l.hashes {
+		out[k] = v
+	}
+	return out, nil
+}
+
+func (l *memLedger) ListFacts(_ context.Context, _ int64) ([]models.ContinuityFact, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var out []models.ContinuityFact
+	for _, facts := range l.facts {
+		out = append(out, facts...)
+	}
+	return out, nil
+}
+
+func (l *memLedger) ReplaceSources(_ context.Context, _ int64, sources []models.ContinuitySource) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, source := range sources {
+		l.hashes[source.Path] = source.Hash
+		l.facts[source.Path] = source.Facts
+		l.replaced = append(l.replaced, source.Path)
+	}
+	return nil
+}
+
+// runContinuity maps and reduces the changed documents, then commits the ledger update
+// as the Service does once a run completes.
+
+func runContinuity(t *testing.T, agent continuityAgent, changed ...manuscript.Document) Result {
+	t.Helper()
+	input := Input{
+		Run: models.AgentRun{ID: 1, ProjectID: 1},
+		Context: manuscript.Context{
+			Changed:   changed,
+			Documents: []manuscript.Document{{Path: "docs/characters/mara.md", Kind: manuscript.KindCharacter, Content: continuityBible}},
+		},
+	}
+	for _, doc := range changed {
+		input.Files = append(input.Files, doc.Path)
+	}
+
+	var results []Result
+	chunks := manuscript.ChunkDocuments(changed, manuscript.ChunkOptions{})
+	for i := range chunks {
+		input.Chunk = &chunks[i]
+		r, err := agent.Run(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+		results = append(results, r)
+	}
+	merged, err := agent.Reduce(context.Background(), input, results)
+	if err != nil {
+		t.Fatalf("Reduce returned error: %v", err)
+	}
+	if c, ok := merged.Data.(committer); ok {
+		if err := c.commit(context.Background()); err != nil {
+			t.Fatalf("commit returned error: %v", err)
+		}
+	}
+	return merged
+}
+
+func TestContinuityAgentReportsContradictions(t *testing.T) {
+	result := runContinuity(t, continuityAgent{},
+		manuscript.Document{Path: "chapters/01.md", Kind: manuscript.KindChapter, Content: continuityChapterOne},
+		manuscript.Document{Path: "chapters/02.md", Kind: manuscript.KindChapter, Content: continuityChapterTwo},
+	)
+
+	issues := issuesByCategory(result.Issues)["continuity"]
+	if len(issues) != 2 {
+		t.Fatalf("expected eye colour and dead character issues, got %+v", result.Issues)
+	}
+	eyes, dead := issues[0], issues[1]
+	if eyes.File != "chapters/02.md" || eyes.StartLine != 3 || !strings.Contains(eyes.Message, "docs/characters/mara.md:3") {
+		t.Fatalf("expected eye colour contradiction against the story bible, got %+v", eyes)
+	}
+	if dead.StartLine != 5 || dead.Severity != models.SeverityError || !strings.Contains(dead.Message, "chapters/01.md:5") {
+		t.Fatalf("expected dead character reappearing at line 5, got %+v", dead)
+	}
+}
+
+func TestContinuityAgentSkipsUnchangedChapters(t *testing.T) {
+	ledger := newMemLedger()
+	agent := continuityAgent{ledger: ledger}
+	chapterOne := manuscript.Document{Path: "chapters/01.md", Kind: manuscript.KindChapter, Content: continuityChapterOne}
+
+	first := runContinuity(t, agent, chapterOne)
+	if len(first.Issues) != 0 {
+		t.Fatalf("expected a clean first run, got %+v", first.Issues)
+	}
+	if len(ledger.facts["chapters/01.md"]) != 2 || len(ledger.facts["docs/characters/mara.md"]) != 3 {
+		t.Fatalf("expected chapter and bible facts in the ledger, got %+v", ledger.facts)
+	}
+
+	ledger.replaced = nil
+	second := runContinuity(t, agent, chapterOne,
+		manuscript.Document{Path: "chapters/02.md", Kind: manuscript.KindChapter, Content: continuityChapterTwo})
+
+	if len(ledger.replaced) != 1 || ledger.replaced[0] != "chapters/02.md" {
+		t.Fatalf("expected only the new chapter to be re-recorded, got %v", ledger.replaced)
+	}
+	if second.Metrics["chapters_skipped"] != 1 {
+		t.Fatalf("expected the unchanged chapter to be skipped, got %v", second.Metrics)
+	}
+	if len(issuesByCategory(second.Issues)["continuity"]) != 2 {
+		t.Fatalf("expected contradictions against ledger facts, got %+v", second.Issues)
+	}
+}
+
+func TestContinuityAgentFlagsChaptersWhenBibleChanges(t *testing.T) {
+	ledger := newMemLedger()
+	ledger.hashes["chapters/01.md"] = "old"
+	ledger.facts["chapters/01.md"] = []models.ContinuityFact{
+		{SourcePath: "chapters/01.md", Kind: models.FactAttribute, Subject: "Mara", Attribute: "hair", Value: "red", Line: 7},
+	}
+
+	result := runContinuity(t, continuityAgent{ledger: ledger},
+		manuscript.Document{Path: "chapters/03.md", Kind: manuscript.KindChapter, Content: "Nothing happens.\n"})
+
+	issues := issuesByCategory(result.Issues)["continuity"]
+	if len(issues) != 1 || issues[0].File != "chapters/01.md" || issues[0].StartLine != 7 || !strings.Contains(issues[0].Message, "now says") {
+		t.Fatalf("expected stale chapter fact to be flagged, got %+v", result.Issues)
+	}
+}
+
+// failCompleteStore loses the race to record a run as completed.
+type failCompleteStore struct {
+	*mockStore
+}
+
+func (failCompleteStore) MarkCompleted(context.Context, int64, json.RawMessage, time.Time) error {
+	return errors.New("connection reset")
+}
+
+func TestContinuityLedgerWaitsForCompletedRun(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "chapters/01.md", continuityChapterOne)
+	ledger := newMemLedger()
+	registry := NewRegistry()
+	_ = registry.Register(continuityAgent{ledger: ledger})
+	ctx := context.Background()
+
+	execute := func(store RunStore) error {
+		svc := NewService(store, t.TempDir(), WithRegistry(registry), WithWorkspaces(stubWorkspaces{1: root}, nil))
+		if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "continuity", FilesChanged: []string{"chapters/01.md"}}); err != nil {
+			t.Fatalf("QueueRun returned error: %v", err)
+		}
+		claimed, _ := svc.claimNextRun(ctx)
+		return svc.executeRun(ctx, claimed)
+	}
+
+	if err := execute(failCompleteStore{newMockStore()}); err == nil {
+		t.Fatal("expected executeRun to fail when the run cannot be completed")
+	}
+	if len(ledger.replaced) != 0 {
+		t.Fatalf("expected the ledger to be left alone, got %v", ledger.replaced)
+	}
+
+	store := newMockStore()
+	if err := execute(store); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+	run, _ := store.GetRun(ctx, 1)
+	if run.Results == nil || run.Results.Metrics["chapters_skipped"] != float64(0) || len(ledger.replaced) == 0 || ledger.replaced[len(ledger.replaced)-1] != "chapters/01.md" {
+		t.Fatalf("expected the chapter to be analysed again and recorded, got %+v / %v", run.Results, ledger.replaced)
+	}
+}
@@ -0,0 +1,66 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// CreditLedger holds and settles AI credits for runs (see the credits package).
+type CreditLedger interface {
+	Reserve(ctx context.Context, projectID int64, amount int) error
+	Release(ctx context.Context, projectID int64, amount int) error
+	Settle(ctx context.Context, runID int64) error
+	Refund(ctx context.Context, runID int64) error
+}
+
+// WithCredits makes QueueRun reserve each run's estimated cost and settles it when the
+// run ends.
+func WithCredits(ledger CreditLedger) Option {
+	return func(s *Service) {
+		s.credits = ledger
+	}
+}
+
+// reserveCredits holds a planned run's estimated cost and returns the run with the
+// reservation recorded on it.
+func (s *Service) reserveCredits(ctx context.Context, plan runPlan) (models.AgentRun, error) {
+	run := plan.run
+	if s.credits == nil {
+		return run, nil
+	}
+	est, err := s.estimate(plan.root, run, plan.agent, plan.agentCfg)
+	if err != nil {
+		return models.AgentRun{}, fmt.Errorf("estimate run: %w", err)
+	}
+	run.CreditsReserved = est.Credits
+	if err := s.credits.Reserve(ctx, run.ProjectID, run.CreditsReserved); err != nil {
+		return models.AgentRun{}, fmt.Errorf("reserve %d credits: %w", run.CreditsReserved, err)
+	}
+	return run, nil
+}
+
+// releaseCredits returns the reservation of a run that was never queued.
+func (s *Service) releaseCredits(ctx context.Context, run models.AgentRun) {
+	if s.credits == nil {
+		return
+	}
+	_ = s.credits.Release(ctx, run.ProjectID, run.CreditsReserved)
+}
+
+// settleCredits charges a completed run its actual cost, or refunds the reservation of a
+// run that ended any other way. Failures are logged; the run outcome stands.
+func (s *Service) settleCredits(ctx context.Context, runID int64, completed bool) {
+	if s.credits == nil {
+		return
+	}
+	settle := s.credits.Refund
+	if completed {
+		settle = s.credits.Settle
+	}
+	if err := settle(ctx, runID); err != nil {
+		log.Printf("agent credits: settle run %d: %v", runID, err)
+	}
+}
@@ -0,0 +1,109 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/credits"
+)
+
+type memCredits struct {
+	mu       sync.Mutex
+	balance  int
+	settled  []int64
+	refunded []int64
+}
+
+func (m *memCredits) Reserve(_ context.Context, _ int64, amount int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if amount > m.balance {
+		return credits.ErrInsufficientCredits
+	}
+	m.balance -= amount
+	return nil
+}
+
+func (m *memCredits) Release(_ context.Context, _ int64, amount int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.balance += amount
+	return nil
+}
+
+func (m *memCredits) Settle(_ context.Context, runID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.settled = append(m.settled, runID)
+	return nil
+}
+
+func (m *memCredits) Refund(_ context.Context, runID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.refunded = append(m.refunded, runID)
+	return nil
+}
+
+func newCreditService(t *testing.T, ledger *memCredits, provider Provider) (*Service, *mockStore) {
+	t.Helper()
+	root := t.TempDir()
+	writeFile(t, root, "chapters/01.md", "# One\n\nAlice opened the door and stepped into the rain.\n")
+	registry := NewRegistry()
+	_ = registry.Register(&promptAgent{name: "notes", trigger: "manual", instructions: "Summarise the changes."})
+	store := newMockStore()
+	prices := PriceTable{"test/model": {Prompt: 10_000, Completion: 10_000}}
+	svc := NewService(store, t.TempDir(),
+		WithRegistry(registry),
+		WithProvider(provider, "test/model"),
+		WithWorkspaces(stubWorkspaces{1: root}, nil),
+		WithUsage(&memUsage{}, prices),
+		WithCredits(ledger),
+	)
+	return svc, store
+}
+
+func TestQueueRunReservesEstimatedCredits(t *testing.T) {
+	ledger := &memCredits{balance: 10_000}
+	svc, _ := newCreditService(t, ledger, NewFakeProvider(CompletionResponse{Content: "Fine."}))
+	ctx := context.Background()
+
+	run, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "notes", FilesChanged: []string{"chapters/01.md"}})
+	if err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	if run.CreditsReserved <= 0 || ledger.balance != 10_000-run.CreditsReserved {
+		t.Fatalf("expected the estimate to be reserved, got run %+v and balance %d", run, ledger.balance)
+	}
+
+	claimed, _ := svc.claimNextRun(ctx)
+	if err := svc.executeRun(ctx, claimed); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+	if len(ledger.settled) != 1 || len(ledger.refunded) != 0 {
+		t.Fatalf("expected the completed run to be settled, got %+v", ledger)
+	}
+
+	ledger.balance = 1
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "notes", FilesChanged: []string{"chapters/01.md"}}); !errors.Is(err, credits.ErrInsufficientCredits) {
+		t.Fatalf("expected ErrInsufficientCredits, got %v", err)
+	}
+}
+
+func TestFailedRunRefundsCredits(t *testing.T) {
+	ledger := &memCredits{balance: 10_000}
+	svc, _ := newCreditService(t, ledger, NewFakeProvider())
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "notes", FilesChanged: []string{"chapters/01.md"}}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	claimed, _ := svc.claimNextRun(ctx)
+	_ = svc.executeRun(ctx, claimed)
+
+	if len(ledger.refunded) != 1 || len(ledger.settled) != 0 {
+		t.Fatalf("expected the failed run to be refunded, got %+v", ledger)
+	}
+}
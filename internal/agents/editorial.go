@@ -0,0 +1,106 @@
+package agents
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"unicode"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// loadEditorial parses the project's EDITORIAL.md. Projects without one, or runs without a
+// workspace, get an empty focus.
+func loadEditorial(root string) (manuscript.Editorial, error) {
+	if root == "" {
+		return manuscript.Editorial{}, nil
+	}
+	content, err := manuscript.ReadFile(root, manuscript.EditorialPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return manuscript.Editorial{}, nil
+	}
+	if err != nil {
+		return manuscript.Editorial{}, fmt.Errorf("read %s: %w", manuscript.EditorialPath, err)
+	}
+	return manuscript.ParseEditorial(content), nil
+}
+
+// applyEditorialFocus drops issues whose category an anti-goal names and raises issues
+// whose category a goal names by one severity level. A category matches when its words
+// appear in the goal, so "passive-voice" matches "Cut the passive voice". The counts are
+// added to the result's metrics.
+func applyEditorialFocus(focus manuscript.Editorial, result *Result) {
+	if len(focus.Goals) == 0 && len(focus.AntiGoals) == 0 {
+		return
+	}
+	goals, antiGoals := focusWords(focus.Goals), focusWords(focus.AntiGoals)
+
+	kept := result.Issues[:0]
+	suppressed, boosted := 0, 0
+	for _, issue := range result.Issues {
+		category := focusPhrase(issue.Category)
+		switch {
+		case mentionsCategory(antiGoals, category):
+			suppressed++
+			continue
+		case mentionsCategory(goals, category):
+			if raised := raiseSeverity(issue.Severity); raised != issue.Severity {
+				issue.Severity = raised
+				boosted++
+			}
+		}
+		kept = append(kept, issue)
+	}
+	result.Issues = kept
+
+	if suppressed == 0 && boosted == 0 {
+		return
+	}
+	if result.Metrics == nil {
+		result.Metrics = map[string]any{}
+	}
+	result.Metrics["editorial_suppressed"] = suppressed
+	result.Metrics["editorial_boosted"] = boosted
+}
+
+func raiseSeverity(s models.Severity) models.Severity {
+	switch s {
+	case models.SeverityInfo:
+		return models.SeverityWarning
+	case models.SeverityWarning:
+		return models.SeverityError
+	}
+	return s
+}
+
+// focusWords normalises goal bullets for mentionsCategory.
+func focusWords(items []string) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = " " + focusPhrase(item) + " "
+	}
+	return out
+}
+
+// mentionsCategory reports whether any normalised goal contains the category as whole
+// words, allowing a plural ("adverbs" matches "adverb").
+func mentionsCategory(goals []string, category string) bool {
+	if category == "" {
+		return false
+	}
+	for _, goal := range goals {
+		if strings.Contains(goal, " "+category+" ") || strings.Contains(goal, " "+category+"s ") {
+			return true
+		}
+	}
+	return false
+}
+
+// focusPhrase lowercases s and collapses everything but letters and digits to single spaces.
+func focusPhrase(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
@@ -0,0 +1,63 @@
+package agents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+func TestApplyEditorialFocus(t *testing.T) {
+	focus := manuscript.Editorial{
+		Goals:     []string{"Cut the passive voice"},
+		AntiGoals: []string{"Don't worry about adverbs yet"},
+	}
+	result := Result{Issues: []models.Issue{
+		{Severity: models.SeverityInfo, Category: "passive-voice", Message: "Passive."},
+		{Severity: models.SeverityWarning, Category: "adverb", Message: "Adverb."},
+		{Severity: models.SeverityWarning, Category: "tense", Message: "Tense shift."},
+	}}
+
+	applyEditorialFocus(focus, &result)
+
+	if len(result.Issues) != 2 || result.Issues[0].Severity != models.SeverityWarning || result.Issues[1].Category != "tense" {
+		t.Fatalf("expected adverbs dropped and passive voice raised, got %+v", result.Issues)
+	}
+	if result.Issues[1].Severity != models.SeverityWarning {
+		t.Fatalf("expected unrelated issues untouched, got %+v", result.Issues[1])
+	}
+	if result.Metrics["editorial_suppressed"] != 1 || result.Metrics["editorial_boosted"] != 1 {
+		t.Fatalf("expected focus metrics, got %v", result.Metrics)
+	}
+}
+
+func TestExecuteRunTagsEditorialRound(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, manuscript.EditorialPath, "**Round**: Pacing Pass\n**Start Date**: 2024-03-01\n\n## Anti-Goals\n- Notes\n")
+
+	registry := NewRegistry()
+	_ = registry.Register(&stubAgent{name: "notes", result: Result{Issues: []models.Issue{
+		{Severity: models.SeverityInfo, Category: "note", Message: "Door."},
+		{Severity: models.SeverityInfo, Category: "pacing", Message: "Slow."},
+	}}})
+	store := newMockStore()
+	svc := NewService(store, t.TempDir(), WithRegistry(registry), WithWorkspaces(stubWorkspaces{1: root}, nil))
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "notes"}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	claimed, _ := svc.claimNextRun(ctx)
+	if err := svc.executeRun(ctx, claimed); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+
+	run, _ := store.GetRun(ctx, claimed.ID)
+	if run.EditorialRound != "2024-03-01-pacing-pass" {
+		t.Fatalf("expected the run to be tagged with the round, got %q", run.EditorialRound)
+	}
+	if run.Results == nil || len(run.Results.Issues) != 1 || run.Results.Issues[0].Category != "pacing" {
+		t.Fatalf("expected the anti-goal category to be suppressed, got %+v", run.Results)
+	}
+}
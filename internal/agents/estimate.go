@@ -0,0 +1,99 @@
+package agents
+
+import (
+	"context"
+
+	"github.com/yourusername/draft-forge/internal/credits"
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+const (
+	// promptOverheadTokens covers the system prompt and format instructions sent with
+	// every call.
+	promptOverheadTokens = 400
+	// defaultCompletionTokens is the expected reply length when max_tokens is unset.
+	defaultCompletionTokens = 800
+)
+
+// Estimate is the projected model usage and cost of a run.
+type Estimate struct {
+	AgentType        string          `json:"agent_type"`
+	Model            string          `json:"model"`
+	Calls            int             `json:"calls"`
+	ChunkCount       int             `json:"chunk_count"`
+	Chunks           []ChunkEstimate `json:"chunks"`
+	PromptTokens     int             `json:"prompt_tokens"`
+	CompletionTokens int             `json:"completion_tokens"`
+	TotalTokens      int             `json:"total_tokens"`
+	CostCents        float64         `json:"cost_cents"`
+	Credits          int             `json:"credits"`
+}
+
+// ChunkEstimate is one planned model call.
+type ChunkEstimate struct {
+	Label        string `json:"label"`
+	PromptTokens int    `json:"prompt_tokens"`
+}
+
+// EstimateRun validates a run request like QueueRun and returns the run's chunk plan,
+// token counts and cost without queueing it or calling a provider.
+func (s *Service) EstimateRun(ctx context.Context, req RunRequest) (Estimate, error) {
+	plan, err := s.planRun(ctx, req)
+	if err != nil {
+		return Estimate{}, err
+	}
+	return s.estimate(plan.root, plan.run, plan.agent, plan.agentCfg)
+}
+
+// estimate projects a run's cost from the context it would send: one call per chunk
+// of the changed files (or one call when nothing changed), each carrying the rendered
+// context. Tokens are approximated locally. Runs without a provider make no model
+// calls and cost nothing.
+func (s *Service) estimate(root string, run models.AgentRun, agent Agent, agentCfg AgentConfig) (Estimate, error) {
+	input, err := s.agentInput(root, run, agentCfg)
+	if err != nil {
+		return Estimate{}, err
+	}
+	input.Context, err = s.buildContext(root, run, agent.ContextRequirements())
+	if err != nil {
+		return Estimate{}, err
+	}
+
+	est := Estimate{AgentType: run.AgentType, Model: input.Model, Chunks: []ChunkEstimate{}}
+	completion := defaultCompletionTokens
+	if agentCfg.MaxTokens > 0 {
+		completion = agentCfg.MaxTokens
+	}
+	overhead := promptOverheadTokens
+	if input.Prompt != nil {
+		overhead += manuscript.EstimateTokens(input.Prompt.Text)
+	}
+
+	chunks := manuscript.ChunkDocuments(input.Context.Changed, s.chunking)
+	if len(chunks) == 0 {
+		est.Calls = 1
+		est.PromptTokens = overhead + manuscript.EstimateTokens(renderContext(input))
+	}
+	for i := range chunks {
+		input.Chunk = &chunks[i]
+		tokens := overhead + manuscript.EstimateTokens(renderContext(input))
+		est.Chunks = append(est.Chunks, ChunkEstimate{Label: chunks[i].Label(), PromptTokens: tokens})
+		est.Calls++
+		est.PromptTokens += tokens
+	}
+	est.ChunkCount = len(chunks)
+	est.CompletionTokens = est.Calls * completion
+	est.TotalTokens = est.PromptTokens + est.CompletionTokens
+
+	if s.provider == nil {
+		return est, nil
+	}
+	prices := s.prices
+	if prices == nil {
+		prices = DefaultPriceTable
+	}
+	est.CostCents = prices.CostCents(est.Model, Usage{PromptTokens: est.PromptTokens, CompletionTokens: est.CompletionTokens})
+	est.Credits = credits.ForCost(est.CostCents)
+	return est, nil
+}
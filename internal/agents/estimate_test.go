@@ -0,0 +1,51 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+)
+
+func TestEstimateRunPlansChunksWithoutCallingProvider(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "chapters/01.md", "# One\n\nAlice opened the door and stepped into the rain.\n\nThe street was empty.\n")
+	writeFile(t, root, "chapters/02.md", "# Two\n\nBob waited under the awning until the storm passed.\n")
+	registry := NewRegistry()
+	_ = registry.Register(&promptAgent{name: "notes", trigger: "manual", requirements: ContextRequirements{ChangedFiles: true}, instructions: "Summarise the changes."})
+	provider := NewFakeProvider()
+	store := newMockStore()
+	svc := NewService(store, t.TempDir(),
+		WithRegistry(registry),
+		WithProvider(provider, "test/model"),
+		WithWorkspaces(stubWorkspaces{1: root}, nil),
+		WithChunking(manuscript.ChunkOptions{MaxTokens: 10}),
+		WithUsage(&memUsage{}, PriceTable{"test/model": {Prompt: 10_000, Completion: 10_000}}),
+	)
+	ctx := context.Background()
+
+	est, err := svc.EstimateRun(ctx, RunRequest{ProjectID: 1, AgentType: "notes", FilesChanged: []string{"chapters/01.md", "chapters/02.md"}})
+	if err != nil {
+		t.Fatalf("EstimateRun returned error: %v", err)
+	}
+	if est.Model != "test/model" || est.ChunkCount < 2 || len(est.Chunks) != est.ChunkCount || est.Calls != est.ChunkCount {
+		t.Fatalf("expected one call per chunk on the configured model, got %+v", est)
+	}
+	if est.PromptTokens <= est.ChunkCount*promptOverheadTokens || est.CompletionTokens != est.Calls*defaultCompletionTokens {
+		t.Fatalf("expected prompt and completion token counts, got %+v", est)
+	}
+	if est.TotalTokens != est.PromptTokens+est.CompletionTokens || est.CostCents <= 0 || est.Credits <= 0 {
+		t.Fatalf("expected a priced estimate, got %+v", est)
+	}
+	if len(provider.Requests()) != 0 {
+		t.Fatalf("expected no provider calls, got %d", len(provider.Requests()))
+	}
+	if runs, _ := store.ListRuns(ctx, 1); len(runs) != 0 {
+		t.Fatalf("expected no run to be inserted, got %+v", runs)
+	}
+
+	if _, err := svc.EstimateRun(ctx, RunRequest{ProjectID: 1, AgentType: "missing"}); !errors.Is(err, ErrInvalidAgentType) {
+		t.Fatalf("expected ErrInvalidAgentType, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+func TestMatchScoresPerCategory(t *testing.T) {
+	expect := []Expectation{
+		{Category: "continuity", File: "chapters/02.md", Line: 3},
+		{Category: "continuity", File: "chapters/02.md", Line: 9},
+		{Category: "tense"},
+	}
+	issues := []models.Issue{
+		{Category: "Continuity", File: "chapters/02.md", StartLine: 2, EndLine: 2},
+		{Category: "continuity", File: "chapters/02.md", StartLine: 20},
+		{Category: "tense", File: "chapters/01.md", StartLine: 4},
+		{Category: "pacing", File: "chapters/01.md", StartLine: 1},
+	}
+
+	scores, missed, unexpected := Match(expect, issues, 1)
+
+	continuity := scores["continuity"]
+	if continuity.TruePositives != 1 || continuity.FalsePositives != 1 || continuity.FalseNegatives != 1 || continuity.Precision != 0.5 || continuity.Recall != 0.5 {
+		t.Fatalf("unexpected continuity score %+v", continuity)
+	}
+	if tense := scores["tense"]; tense.Precision != 1 || tense.Recall != 1 || tense.F1 != 1 {
+		t.Fatalf("expected an unanchored expectation to match any tense issue, got %+v", tense)
+	}
+	if pacing := scores["pacing"]; pacing.Precision != 0 || pacing.Recall != 1 {
+		t.Fatalf("expected an unexpected category to cost precision only, got %+v", pacing)
+	}
+	if len(missed) != 1 || missed[0].Line != 9 || len(unexpected) != 2 {
+		t.Fatalf("expected one miss and two unexpected issues, got %+v %+v", missed, unexpected)
+	}
+}
+
+func TestRunRecordsCaseFailures(t *testing.T) {
+	dir := t.TempDir()
+	caseDir := filepath.Join(dir, "broken")
+	if err := os.MkdirAll(filepath.Join(caseDir, ProjectDir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	caseYAML := "agent: style\nfiles: [chapters/01.md]\nresponses: missing.json\nexpect:\n  - category: tense\n"
+	if err := os.WriteFile(filepath.Join(caseDir, CaseFile), []byte(caseYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	suite, err := LoadSuite(dir)
+	if err != nil {
+		t.Fatalf("LoadSuite returned error: %v", err)
+	}
+	report := Run(context.Background(), suite, Options{})
+	if len(report.Failures()) != 1 || report.Total.FalseNegatives != 1 || report.Total.Recall != 0 {
+		t.Fatalf("expected the failed case to miss its expectation, got %+v", report)
+	}
+}
+
+func TestLoadCaseValidates(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, CaseFile), []byte("files: [chapters/01.md]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCase(dir); !errors.Is(err, ErrInvalidCase) {
+		t.Fatalf("expected ErrInvalidCase without an agent, got %v", err)
+	}
+}
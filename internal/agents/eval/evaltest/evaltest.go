@@ -0,0 +1,42 @@
+// Package evaltest runs eval suites from Go tests, so prompt and agent changes are
+// checked against the golden manuscripts by go test.
+package evaltest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/agents/eval"
+)
+
+// RunSuite loads and runs the suite in dir for a Go test, failing tb if it cannot be loaded.
+func RunSuite(tb testing.TB, dir string, opts eval.Options) eval.Report {
+	tb.Helper()
+	suite, err := eval.LoadSuite(dir)
+	if err != nil {
+		tb.Fatalf("load eval suite: %v", err)
+	}
+	return eval.Run(context.Background(), suite, opts)
+}
+
+// RequireScores fails tb when a case errored or the report's overall precision or
+// recall falls below the given floors. Use it to keep prompt and agent changes from
+// regressing the golden manuscripts.
+func RequireScores(tb testing.TB, report eval.Report, minPrecision, minRecall float64) {
+	tb.Helper()
+	for _, failure := range report.Failures() {
+		tb.Errorf("eval case failed: %s", failure)
+	}
+	if report.Total.Precision < minPrecision || report.Total.Recall < minRecall {
+		for _, c := range report.Cases {
+			for _, e := range c.Missed {
+				tb.Logf("%s: missed %s at %s:%d (%s)", c.Name, e.Category, e.File, e.Line, e.Note)
+			}
+			for _, issue := range c.Unexpected {
+				tb.Logf("%s: unexpected %s at %s:%d: %s", c.Name, issue.Category, issue.File, issue.StartLine, issue.Message)
+			}
+		}
+		tb.Errorf("eval scores precision %.2f recall %.2f, want at least %.2f and %.2f",
+			report.Total.Precision, report.Total.Recall, minPrecision, minRecall)
+	}
+}
@@ -0,0 +1,23 @@
+package evaltest
+
+import (
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/agents/eval"
+)
+
+func TestGoldenSuite(t *testing.T) {
+	report := RunSuite(t, "../../../../evals", eval.Options{})
+	if len(report.Cases) != 3 {
+		t.Fatalf("expected three golden cases, got %d", len(report.Cases))
+	}
+	for _, c := range report.Cases {
+		if c.PromptHash == "" {
+			t.Fatalf("expected %s to record its prompt hash", c.Name)
+		}
+	}
+	if style := report.Cases[1]; style.Name != "style-voice" || style.Provider != eval.ProviderFake {
+		t.Fatalf("expected the style case to use its fake responses, got %+v", style)
+	}
+	RequireScores(t, report, 1, 1)
+}
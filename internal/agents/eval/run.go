@@ -0,0 +1,149 @@
+package eval
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourusername/draft-forge/internal/agents"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// Provider names recorded on case reports.
+const (
+	ProviderNone       = "none"
+	ProviderFake       = "fake"
+	ProviderConfigured = "configured"
+)
+
+// Options configures a suite run.
+type Options struct {
+	// Provider, when set, answers every case's model calls. Otherwise cases with a
+	// responses fixture use a FakeProvider loaded from it and the rest run without a
+	// model, exercising only the agents' deterministic checks.
+	Provider agents.Provider
+	// Model is passed to the provider; empty uses agents.DefaultModel.
+	Model string
+	// Agent limits the run to cases for one agent.
+	Agent string
+	Now   func() time.Time
+}
+
+// Report is the JSON written by `cli eval`. Keep field names stable; reports are
+// compared across commits to track prompt and model changes.
+type Report struct {
+	Suite       string           `json:"suite"`
+	Model       string           `json:"model,omitempty"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	Cases       []CaseReport     `json:"cases"`
+	Categories  map[string]Score `json:"categories"`
+	Total       Score            `json:"total"`
+}
+
+// CaseReport scores one case. Error is set when the agent failed; every expectation then
+// counts as missed.
+type CaseReport struct {
+	Name       string           `json:"name"`
+	Agent      string           `json:"agent"`
+	Provider   string           `json:"provider"`
+	PromptHash string           `json:"prompt_hash,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	Categories map[string]Score `json:"categories"`
+	Total      Score            `json:"total"`
+	Missed     []Expectation    `json:"missed,omitempty"`
+	Unexpected []models.Issue   `json:"unexpected,omitempty"`
+}
+
+// Run scores every case in the suite. Case failures are recorded on the report rather
+// than returned, so one broken agent does not hide the others' scores.
+func Run(ctx context.Context, suite Suite, opts Options) Report {
+	now := opts.Now
+	if now == nil {
+		now = time.Now
+	}
+	report := Report{
+		Suite:       suite.Dir,
+		Model:       opts.Model,
+		GeneratedAt: now().UTC(),
+		Cases:       []CaseReport{},
+		Categories:  map[string]Score{},
+	}
+
+	for _, c := range suite.Cases {
+		if opts.Agent != "" && c.Agent != opts.Agent {
+			continue
+		}
+		cr := runCase(ctx, c, opts)
+		for category, score := range cr.Categories {
+			total := report.Categories[category]
+			total.Add(score)
+			report.Categories[category] = total
+		}
+		report.Total.Add(cr.Total)
+		report.Cases = append(report.Cases, cr)
+	}
+	report.Total.compute()
+	return report
+}
+
+func runCase(ctx context.Context, c Case, opts Options) CaseReport {
+	cr := CaseReport{Name: c.Name, Agent: c.Agent, Provider: ProviderNone}
+
+	var serviceOpts []agents.Option
+	switch {
+	case opts.Provider != nil:
+		cr.Provider = ProviderConfigured
+		serviceOpts = append(serviceOpts, agents.WithProvider(opts.Provider, opts.Model))
+	case c.Responses != "":
+		fake, err := agents.LoadFakeProvider(filepath.Join(c.Dir, c.Responses))
+		if err != nil {
+			return failedCase(cr, c, err)
+		}
+		cr.Provider = ProviderFake
+		serviceOpts = append(serviceOpts, agents.WithProvider(fake, opts.Model))
+	}
+
+	svc := agents.NewService(nil, "", serviceOpts...)
+	run, err := svc.Analyze(ctx, filepath.Join(c.Dir, ProjectDir), models.AgentRun{
+		AgentType:    c.Agent,
+		Trigger:      "manual",
+		FilesChanged: c.Files,
+	})
+	if err != nil {
+		return failedCase(cr, c, err)
+	}
+	cr.PromptHash = run.PromptHash
+
+	cr.Categories, cr.Missed, cr.Unexpected = Match(c.Expect, run.Results.Issues, c.Tolerance)
+	cr.Total = sumScores(cr.Categories)
+	return cr
+}
+
+// failedCase records an agent error, counting every expectation as missed.
+func failedCase(cr CaseReport, c Case, err error) CaseReport {
+	cr.Error = err.Error()
+	cr.Categories, cr.Missed, _ = Match(c.Expect, nil, c.Tolerance)
+	cr.Total = sumScores(cr.Categories)
+	return cr
+}
+
+func sumScores(scores map[string]Score) Score {
+	var total Score
+	for _, score := range scores {
+		total.Add(score)
+	}
+	total.compute()
+	return total
+}
+
+// Failures lists the cases that errored, for callers that want a non-zero exit.
+func (r Report) Failures() []string {
+	var out []string
+	for _, c := range r.Cases {
+		if c.Error != "" {
+			out = append(out, c.Name+": "+strings.TrimSpace(c.Error))
+		}
+	}
+	return out
+}
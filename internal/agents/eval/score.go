@@ -0,0 +1,109 @@
+package eval
+
+import (
+	"strings"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// Score counts matches for one category (or a whole case or suite).
+//
+// Precision is the share of reported issues that were expected and Recall the share of
+// expected issues that were reported. Both are 1 when there was nothing to get wrong, so
+// a category the agent correctly left silent does not drag averages down.
+type Score struct {
+	TruePositives  int     `json:"true_positives"`
+	FalsePositives int     `json:"false_positives"`
+	FalseNegatives int     `json:"false_negatives"`
+	Precision      float64 `json:"precision"`
+	Recall         float64 `json:"recall"`
+	F1             float64 `json:"f1"`
+}
+
+// Add accumulates another score's counts and recomputes the ratios.
+func (s *Score) Add(o Score) {
+	s.TruePositives += o.TruePositives
+	s.FalsePositives += o.FalsePositives
+	s.FalseNegatives += o.FalseNegatives
+	s.compute()
+}
+
+func (s *Score) compute() {
+	s.Precision = ratio(s.TruePositives, s.TruePositives+s.FalsePositives)
+	s.Recall = ratio(s.TruePositives, s.TruePositives+s.FalseNegatives)
+	s.F1 = 0
+	if s.Precision+s.Recall > 0 {
+		s.F1 = 2 * s.Precision * s.Recall / (s.Precision + s.Recall)
+	}
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 1
+	}
+	return float64(n) / float64(d)
+}
+
+// Match pairs reported issues with expectations. Each expectation is satisfied by at
+// most one issue, taken in report order. It returns per-category scores (keyed by the
+// lowercased category), the expectations nobody reported and the issues nobody expected.
+func Match(expect []Expectation, issues []models.Issue, tolerance int) (map[string]Score, []Expectation, []models.Issue) {
+	scores := map[string]Score{}
+	matched := make([]bool, len(expect))
+	var unexpected []models.Issue
+
+	for _, issue := range issues {
+		category := strings.ToLower(issue.Category)
+		score := scores[category]
+		found := false
+		for i, e := range expect {
+			if !matched[i] && e.matches(issue, tolerance) {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if found {
+			score.TruePositives++
+		} else {
+			score.FalsePositives++
+			unexpected = append(unexpected, issue)
+		}
+		scores[category] = score
+	}
+
+	var missed []Expectation
+	for i, e := range expect {
+		if matched[i] {
+			continue
+		}
+		category := strings.ToLower(e.Category)
+		score := scores[category]
+		score.FalseNegatives++
+		scores[category] = score
+		missed = append(missed, e)
+	}
+
+	for category, score := range scores {
+		score.compute()
+		scores[category] = score
+	}
+	return scores, missed, unexpected
+}
+
+func (e Expectation) matches(issue models.Issue, tolerance int) bool {
+	if !strings.EqualFold(e.Category, issue.Category) {
+		return false
+	}
+	if e.File != "" && e.File != issue.File {
+		return false
+	}
+	if e.Line == 0 {
+		return true
+	}
+	if issue.StartLine == 0 {
+		return false
+	}
+	end := max(issue.EndLine, issue.StartLine)
+	return e.Line >= issue.StartLine-tolerance && e.Line <= end+tolerance
+}
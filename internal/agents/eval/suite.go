@@ -0,0 +1,125 @@
+// Package eval scores agents against golden manuscripts: small project trees with
+// seeded, known issues. Each case runs one agent offline and compares the issues it
+// reports with the case's expectations, giving precision and recall per category.
+package eval
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CaseFile is the golden description inside each case directory. The manuscript under
+// test lives beside it in ProjectDir.
+const (
+	CaseFile   = "case.yaml"
+	ProjectDir = "project"
+)
+
+var ErrInvalidCase = errors.New("invalid eval case")
+
+// Suite is a directory of cases, one per subdirectory holding a case.yaml.
+type Suite struct {
+	Dir   string
+	Cases []Case
+}
+
+// Case is one golden manuscript:
+//
+//	agent: continuity
+//	files: [chapters/01.md, chapters/02.md]
+//	responses: responses.json   # optional fake provider fixtures
+//	line_tolerance: 1           # optional slack when matching lines
+//	expect:
+//	  - category: continuity
+//	    file: chapters/02.md
+//	    line: 3
+//	    note: eye colour contradicts the character sheet
+type Case struct {
+	Name        string        `yaml:"-"`
+	Dir         string        `yaml:"-"`
+	Agent       string        `yaml:"agent"`
+	Description string        `yaml:"description"`
+	Files       []string      `yaml:"files"`
+	Responses   string        `yaml:"responses"`
+	Tolerance   int           `yaml:"line_tolerance"`
+	Expect      []Expectation `yaml:"expect"`
+}
+
+// Expectation is a seeded issue the agent should report. File and Line are optional;
+// when set, a reported issue must be anchored there (within the case's line tolerance).
+type Expectation struct {
+	Category string `yaml:"category" json:"category"`
+	File     string `yaml:"file" json:"file,omitempty"`
+	Line     int    `yaml:"line" json:"line,omitempty"`
+	Note     string `yaml:"note" json:"note,omitempty"`
+}
+
+// LoadSuite reads every case under dir, sorted by name.
+func LoadSuite(dir string) (Suite, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return Suite{}, fmt.Errorf("read suite: %w", err)
+	}
+
+	suite := Suite{Dir: dir}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		caseDir := filepath.Join(dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(caseDir, CaseFile)); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		c, err := LoadCase(caseDir)
+		if err != nil {
+			return Suite{}, err
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+	sort.Slice(suite.Cases, func(i, j int) bool { return suite.Cases[i].Name < suite.Cases[j].Name })
+	return suite, nil
+}
+
+// LoadCase reads and validates the case.yaml in dir.
+func LoadCase(dir string) (Case, error) {
+	data, err := os.ReadFile(filepath.Join(dir, CaseFile))
+	if err != nil {
+		return Case{}, fmt.Errorf("read case: %w", err)
+	}
+	var c Case
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return Case{}, fmt.Errorf("%w: %s: %v", ErrInvalidCase, dir, err)
+	}
+	c.Name = filepath.Base(dir)
+	c.Dir = dir
+	if err := c.validate(); err != nil {
+		return Case{}, fmt.Errorf("%w: %s: %v", ErrInvalidCase, c.Name, err)
+	}
+	return c, nil
+}
+
+func (c Case) validate() error {
+	switch {
+	case strings.TrimSpace(c.Agent) == "":
+		return fmt.Errorf("agent is required")
+	case len(c.Files) == 0:
+		return fmt.Errorf("files are required")
+	case c.Tolerance < 0:
+		return fmt.Errorf("line_tolerance must not be negative")
+	}
+	for i, e := range c.Expect {
+		if strings.TrimSpace(e.Category) == "" {
+			return fmt.Errorf("expectation %d: category is required", i)
+		}
+		if e.Line < 0 {
+			return fmt.Errorf("expectation %d: line must not be negative", i)
+		}
+	}
+	return nil
+}
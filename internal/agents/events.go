@@ -0,0 +1,126 @@
+package agents
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// subscriberBuffer is how many events a slow subscriber may fall behind before events
+// are dropped for it.
+const subscriberBuffer = 64
+
+// EventPublisher receives run lifecycle events from the Service.
+type EventPublisher interface {
+	Publish(ctx context.Context, event models.RunEvent) error
+}
+
+// WithEvents publishes run lifecycle events. Use a *Broker directly for a single
+// instance, or a relay that fans out through Postgres LISTEN/NOTIFY into each
+// instance's Broker.
+func WithEvents(publisher EventPublisher) Option {
+	return func(s *Service) {
+		s.events = publisher
+	}
+}
+
+// Broker fans run events out to in-process subscribers such as SSE streams.
+type Broker struct {
+	mu          sync.Mutex
+	nextID      int
+	subscribers map[int]subscriber
+	closed      bool
+}
+
+type subscriber struct {
+	events chan models.RunEvent
+	filter func(models.RunEvent) bool
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[int]subscriber)}
+}
+
+// Publish delivers event to every matching subscriber without blocking; a subscriber
+// whose buffer is full misses the event.
+func (b *Broker) Publish(_ context.Context, event models.RunEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, sub := range b.subscribers {
+		if sub.filter != nil && !sub.filter(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe returns a channel of events matching filter (all events when nil) and a
+// function that unsubscribes. The channel is closed on unsubscribe or when the broker
+// is closed.
+func (b *Broker) Subscribe(filter func(models.RunEvent) bool) (<-chan models.RunEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	events := make(chan models.RunEvent, subscriberBuffer)
+	if b.closed {
+		close(events)
+		return events, func() {}
+	}
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = subscriber{events: events, filter: filter}
+
+	return events, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[id]; ok {
+			delete(b.subscribers, id)
+			close(events)
+		}
+	}
+}
+
+// Close ends every subscription so long-lived streams finish during shutdown.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for id, sub := range b.subscribers {
+		delete(b.subscribers, id)
+		close(sub.events)
+	}
+}
+
+// publish emits a lifecycle event for run. Delivery is best effort: a failure is logged
+// and never fails the run.
+func (s *Service) publish(ctx context.Context, run models.AgentRun, event models.RunEvent) {
+	if s.events == nil {
+		return
+	}
+	event.RunID = run.ID
+	event.ProjectID = run.ProjectID
+	event.AgentType = run.AgentType
+	event.Timestamp = s.now()
+	if event.Status == "" {
+		event.Status = event.Type
+	}
+	if err := s.events.Publish(ctx, event); err != nil {
+		log.Printf("agent events: publish %s for run %d: %v", event.Type, run.ID, err)
+	}
+}
+
+// publishProgress reports chunk progress along with the issues of the chunk that just
+// finished, if any.
+func (s *Service) publishProgress(ctx context.Context, run models.AgentRun, progress models.RunProgress, issues []models.Issue) {
+	s.publish(ctx, run, models.RunEvent{
+		Type:     models.RunEventProgress,
+		Status:   "running",
+		Progress: &progress,
+		Issues:   issues,
+	})
+}
@@ -0,0 +1,71 @@
+package agents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+func TestServicePublishesRunLifecycle(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "chapters/01.md", "# One\n\nAlice opened the door.\n")
+	writeFile(t, root, "chapters/02.md", "# Two\n\nBob closed it.\n")
+
+	registry := NewRegistry()
+	_ = registry.Register(&stubAgent{name: "notes", result: Result{Issues: []models.Issue{{Severity: models.SeverityInfo, Category: "note", Message: "Door."}}}})
+	broker := NewBroker()
+	svc := NewService(newMockStore(), t.TempDir(),
+		WithRegistry(registry),
+		WithWorkspaces(stubWorkspaces{1: root}, nil),
+		WithChunking(manuscript.ChunkOptions{MaxTokens: 10}),
+		WithEvents(broker),
+	)
+	events, unsubscribe := broker.Subscribe(func(event models.RunEvent) bool { return event.RunID == 1 })
+	defer unsubscribe()
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "notes", FilesChanged: []string{"chapters/01.md", "chapters/02.md"}}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	claimed, _ := svc.claimNextRun(ctx)
+	if err := svc.executeRun(ctx, claimed); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+	unsubscribe()
+
+	var types []string
+	var partial int
+	for event := range events {
+		types = append(types, event.Type)
+		partial += len(event.Issues)
+	}
+	want := []string{"queued", "running", "progress", "progress", "progress", "progress", "completed"}
+	if len(types) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("expected events %v, got %v", want, types)
+		}
+	}
+	if partial != 2 {
+		t.Fatalf("expected each chunk's issues on its progress event, got %d", partial)
+	}
+}
+
+func TestBrokerCloseEndsSubscriptions(t *testing.T) {
+	broker := NewBroker()
+	events, unsubscribe := broker.Subscribe(nil)
+	broker.Close()
+	if _, ok := <-events; ok {
+		t.Fatalf("expected the subscription to be closed")
+	}
+	unsubscribe()
+
+	late, _ := broker.Subscribe(nil)
+	if _, ok := <-late; ok {
+		t.Fatalf("expected subscriptions after Close to be closed")
+	}
+}
@@ -0,0 +1,449 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/draft-forge/internal/citations"
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+var factInstructions = mustBuiltinPrompt("fact")
+
+// maxLibraryNotes caps how many bibliography entries are listed in a chunk's prompt.
+const maxLibraryNotes = 60
+
+var (
+	claimPattern      = regexp.MustCompile(`(?i)\d|%|\b(?:according to|research|study|studies|survey|percent|per cent|million|billion|data|evidence|found that|showed that|estimated|reported|statistics?)\b`)
+	attributedPattern = regexp.MustCompile(`\b(\p{Lu}[\p{L}'’-]+)(?:\s+et al\.?)?\s*(?:\(|,\s*)(\d{4})\b`)
+	namePattern       = regexp.MustCompile(`\b\p{Lu}[\p{L}'’-]+`)
+)
+
+// factClaim is a sentence that states something checkable, with the citations attached to it.
+type factClaim struct {
+	File      string
+	Line      int
+	Column    int
+	Text      string
+	Keys      []string
+	Footnotes []string
+	// Attributions are "Author (Year)" mentions in the sentence.
+	Attributions []attribution
+	// Names are the capitalised words, used to spot citations that point at a different
+	// author than the one the text names.
+	Names []string
+}
+
+type attribution struct {
+	Author string
+	Year   int
+}
+
+// citationUse is a Pandoc citation key found in the text or in a footnote.
+type citationUse struct {
+	File    string
+	Line    int
+	Key     string
+	Excerpt string
+}
+
+type footnoteRef struct {
+	File  string
+	Label string
+	Line  int
+}
+
+type footnoteDef struct {
+	File  string
+	Label string
+	Line  int
+	Text  string
+}
+
+// factChunk is the per-chunk payload handed from Run to Reduce. Footnote references and
+// definitions are matched in Reduce since they usually sit in different chunks.
+type factChunk struct {
+	Claims    []factClaim
+	Citations []citationUse
+	Refs      []footnoteRef
+	Defs      []footnoteDef
+}
+
+// factAgent (FactBot) extracts factual claims from non-fiction chapters, matches them to
+// Pandoc citations and footnotes, and checks those against the BibTeX or CSL-JSON files
+// in docs/sources. Everything except the optional model pass runs offline.
+type factAgent struct{}
+
+func (factAgent) Name() string           { return "fact" }
+func (factAgent) DefaultTrigger() string { return "manual" }
+func (factAgent) ContextRequirements() ContextRequirements {
+	return ContextRequirements{ChangedFiles: true, Sources: true}
+}
+
+func (factAgent) CacheVersion() string { return "fact/1" }
+
+func (factAgent) DecodeData(raw json.RawMessage) (any, error) { return decodeData[factChunk](raw) }
+
+func (a factAgent) Run(ctx context.Context, input Input) (Result, error) {
+	if input.Chunk == nil {
+		return Result{Summary: "No chapters to analyse."}, nil
+	}
+
+	data := extractClaims(*input.Chunk)
+	result := Result{Data: data}
+
+	if input.Provider != nil {
+		library, _ := loadLibrary(input.Context.Sources)
+		instructions, err := input.instructions(factInstructions)
+		if err != nil {
+			return Result{}, err
+		}
+		parsed, usage, err := askModel(ctx, input, a.Name(), instructions, factNotes(library, data.Claims))
+		if err != nil {
+			return Result{}, err
+		}
+		result.Summary = parsed.Summary
+		result.Issues = parsed.Issues
+		result.Usage = usage
+	}
+	return result, nil
+}
+
+// Reduce matches claims, citations and footnotes across chunks against the bibliography.
+func (a factAgent) Reduce(_ context.Context, input Input, results []Result) (Result, error) {
+	var merged Result
+	var summaries []string
+	var data factChunk
+	for _, r := range results {
+		merged.Issues = append(merged.Issues, r.Issues...)
+		merged.Usage.PromptTokens += r.Usage.PromptTokens
+		merged.Usage.CompletionTokens += r.Usage.CompletionTokens
+		merged.Usage.TotalTokens += r.Usage.TotalTokens
+		if s := strings.TrimSpace(r.Summary); s != "" {
+			summaries = append(summaries, s)
+		}
+		if c, ok := r.Data.(factChunk); ok {
+			data.Claims = append(data.Claims, c.Claims...)
+			data.Citations = append(data.Citations, c.Citations...)
+			data.Refs = append(data.Refs, c.Refs...)
+			data.Defs = append(data.Defs, c.Defs...)
+		}
+	}
+
+	library, issues := loadLibrary(input.Context.Sources)
+
+	defs := map[string]footnoteDef{}
+	for _, def := range data.Defs {
+		defs[def.File+"#"+def.Label] = def
+		for _, c := range citations.FindCitations(def.Text) {
+			data.Citations = append(data.Citations, citationUse{File: def.File, Line: def.Line, Key: c.Key, Excerpt: excerpt(def.Text)})
+		}
+	}
+	referenced := map[string]bool{}
+	for _, ref := range data.Refs {
+		id := ref.File + "#" + ref.Label
+		referenced[id] = true
+		if _, ok := defs[id]; !ok {
+			issues = append(issues, factIssue(ref.File, ref.Line, models.SeverityError, "citation",
+				fmt.Sprintf("Footnote [^%s] has no definition.", ref.Label), "[^"+ref.Label+"]", 1))
+		}
+	}
+	for _, def := range data.Defs {
+		if !referenced[def.File+"#"+def.Label] {
+			issues = append(issues, factIssue(def.File, def.Line, models.SeverityInfo, "citation",
+				fmt.Sprintf("Footnote [^%s] is defined but never referenced.", def.Label), excerpt(def.Text), 1))
+		} else if len(library) > 0 && len(footnoteEntries(def, library)) == 0 && len(citations.FindCitations(def.Text)) == 0 {
+			issues = append(issues, factIssue(def.File, def.Line, models.SeverityInfo, "citation",
+				fmt.Sprintf("Footnote [^%s] does not match any source in docs/sources.", def.Label), excerpt(def.Text), 0.6))
+		}
+	}
+
+	if len(library) == 0 && len(data.Citations) > 0 {
+		issues = append(issues, models.Issue{
+			Severity:   models.SeverityInfo,
+			Category:   "bibliography",
+			Message:    "No bibliography found in docs/sources; citation keys were not checked.",
+			Suggestion: "Add a BibTeX (.bib) or CSL-JSON (.json) file to docs/sources.",
+			Confidence: 1,
+		})
+	} else {
+		for _, use := range data.Citations {
+			if _, ok := library[use.Key]; !ok {
+				issues = append(issues, factIssue(use.File, use.Line, models.SeverityError, "citation",
+					fmt.Sprintf("Citation key @%s is not in docs/sources.", use.Key), use.Excerpt, 1))
+			}
+		}
+	}
+
+	cited := 0
+	for _, claim := range data.Claims {
+		if len(claim.Keys) == 0 && len(claim.Footnotes) == 0 {
+			issue := factIssue(claim.File, claim.Line, models.SeverityWarning, "uncited-claim", "Factual claim has no citation.", claim.Text, 0.6)
+			issue.StartColumn = claim.Column
+			issue.Suggestion = "Add a citation or footnote pointing at a source in docs/sources."
+			issues = append(issues, issue)
+			continue
+		}
+		cited++
+		issues = append(issues, checkAttribution(claim, claimEntries(claim, defs, library), library)...)
+	}
+
+	merged.Issues = dedupeIssues(append(merged.Issues, issues...))
+	merged.Metrics = map[string]any{
+		"claims":         len(data.Claims),
+		"cited_claims":   cited,
+		"uncited_claims": len(data.Claims) - cited,
+		"citations":      len(data.Citations),
+		"footnotes":      len(data.Defs),
+		"sources":        len(library),
+	}
+	merged.Summary = fmt.Sprintf("Found %d claims (%d cited) and %d citations against %d sources; %d issues.",
+		len(data.Claims), cited, len(data.Citations), len(library), len(merged.Issues))
+	if len(summaries) > 0 {
+		merged.Summary += "\n\n" + strings.Join(summaries, "\n\n")
+	}
+	return merged, nil
+}
+
+// extractClaims finds claim sentences, citations and footnotes in a chunk. A citation or
+// footnote marker belongs to the sentence it sits in, or to the previous sentence when it
+// directly follows the full stop ("...in 2019.[^1]").
+func extractClaims(chunk manuscript.Chunk) factChunk {
+	var data factChunk
+	for _, p := range extractParagraphs(chunk) {
+		if strings.HasPrefix(p.text, "[^") {
+			for i, line := range strings.Split(p.text, "\n") {
+				if label, text, ok := citations.ParseFootnoteDefinition(line); ok {
+					data.Defs = append(data.Defs, footnoteDef{File: p.file, Label: label, Line: p.startLine + i, Text: text})
+				}
+			}
+			continue
+		}
+
+		cites := citations.FindCitations(p.text)
+		refs := citations.FindFootnoteRefs(p.text)
+		masked := []byte(p.text)
+		for _, c := range cites {
+			blank(masked, c.Start, c.End)
+		}
+		for _, r := range refs {
+			blank(masked, r.Start, r.End)
+		}
+
+		sentences := splitSentences(string(masked))
+		firstWord := make([]int, len(sentences))
+		for i, s := range sentences {
+			firstWord[i] = s.start + wordPattern.FindStringIndex(string(masked[s.start:s.end]))[0]
+		}
+		owner := func(offset int) int {
+			i := sort.Search(len(firstWord), func(i int) bool { return firstWord[i] > offset }) - 1
+			return max(i, 0)
+		}
+
+		keys := make([][]string, len(sentences))
+		notes := make([][]string, len(sentences))
+		for _, c := range cites {
+			line, _ := p.position(c.Start)
+			data.Citations = append(data.Citations, citationUse{File: p.file, Line: line, Key: c.Key, Excerpt: p.text[c.Start:c.End]})
+			if len(sentences) > 0 {
+				i := owner(c.Start)
+				keys[i] = append(keys[i], c.Key)
+			}
+		}
+		for _, r := range refs {
+			line, _ := p.position(r.Start)
+			data.Refs = append(data.Refs, footnoteRef{File: p.file, Label: r.Label, Line: line})
+			if len(sentences) > 0 {
+				i := owner(r.Start)
+				notes[i] = append(notes[i], r.Label)
+			}
+		}
+
+		for i, s := range sentences {
+			text := string(masked[s.start:s.end])
+			if len(s.words) < 4 || !claimPattern.MatchString(text) {
+				continue
+			}
+			line, col := p.position(firstWord[i])
+			claim := factClaim{
+				File:      p.file,
+				Line:      line,
+				Column:    col,
+				Text:      excerpt(strings.Join(strings.Fields(p.text[firstWord[i]:s.end]), " ")),
+				Keys:      keys[i],
+				Footnotes: notes[i],
+			}
+			for _, m := range attributedPattern.FindAllStringSubmatch(text, -1) {
+				year, _ := strconv.Atoi(m[2])
+				claim.Attributions = append(claim.Attributions, attribution{Author: m[1], Year: year})
+			}
+			claim.Names = namePattern.FindAllString(text, -1)
+			data.Claims = append(data.Claims, claim)
+		}
+	}
+	return data
+}
+
+func blank(b []byte, start, end int) {
+	for i := start; i < end; i++ {
+		b[i] = ' '
+	}
+}
+
+// loadLibrary parses the bibliography files; files that fail to parse are reported.
+func loadLibrary(docs []manuscript.Document) (citations.Library, []models.Issue) {
+	library := citations.Library{}
+	var issues []models.Issue
+	for _, doc := range docs {
+		var entries []citations.Entry
+		var err error
+		switch strings.ToLower(path.Ext(doc.Path)) {
+		case ".bib":
+			entries, err = citations.ParseBibTeX(doc.Content)
+		case ".json":
+			entries, err = citations.ParseCSLJSON([]byte(doc.Content))
+		default:
+			continue
+		}
+		if err != nil {
+			issues = append(issues, models.Issue{
+				Severity:   models.SeverityWarning,
+				Category:   "bibliography",
+				Message:    fmt.Sprintf("Could not read %s: %v", doc.Path, err),
+				File:       doc.Path,
+				Confidence: 1,
+			})
+			continue
+		}
+		for i := range entries {
+			entries[i].Source = doc.Path
+		}
+		library.Add(entries...)
+	}
+	return library, issues
+}
+
+// claimEntries returns the bibliography entries a claim cites, directly or via footnotes.
+func claimEntries(claim factClaim, defs map[string]footnoteDef, library citations.Library) []citations.Entry {
+	var entries []citations.Entry
+	for _, key := range claim.Keys {
+		if e, ok := library[key]; ok {
+			entries = append(entries, e)
+		}
+	}
+	for _, label := range claim.Footnotes {
+		if def, ok := defs[claim.File+"#"+label]; ok {
+			entries = append(entries, footnoteEntries(def, library)...)
+		}
+	}
+	return entries
+}
+
+// footnoteEntries resolves a footnote to bibliography entries, by citation key or by an
+// "Author (Year)" / "Author, Year" reference in its text.
+func footnoteEntries(def footnoteDef, library citations.Library) []citations.Entry {
+	var entries []citations.Entry
+	for _, c := range citations.FindCitations(def.Text) {
+		if e, ok := library[c.Key]; ok {
+			entries = append(entries, e)
+		}
+	}
+	for _, m := range attributedPattern.FindAllStringSubmatch(def.Text, -1) {
+		year, _ := strconv.Atoi(m[2])
+		for _, key := range library.Keys() {
+			if e := library[key]; e.HasAuthor(m[1]) && e.Year == year {
+				entries = append(entries, e)
+			}
+		}
+	}
+	return entries
+}
+
+// checkAttribution flags claims whose wording disagrees with what they cite: an
+// "Author (Year)" whose year differs from the cited entry, or a named author who wrote a
+// different source in the library than the one cited.
+func checkAttribution(claim factClaim, cited []citations.Entry, library citations.Library) []models.Issue {
+	if len(cited) == 0 {
+		return nil
+	}
+	citedBy := func(author string) (citations.Entry, bool) {
+		for _, e := range cited {
+			if e.HasAuthor(author) {
+				return e, true
+			}
+		}
+		return citations.Entry{}, false
+	}
+
+	var issues []models.Issue
+	for _, a := range claim.Attributions {
+		if e, ok := citedBy(a.Author); ok && e.Year != 0 && e.Year != a.Year {
+			issues = append(issues, factIssue(claim.File, claim.Line, models.SeverityWarning, "citation-mismatch",
+				fmt.Sprintf("Text cites %s (%d), but @%s was published in %d.", a.Author, a.Year, e.Key, e.Year), claim.Text, 0.8))
+		}
+	}
+	for _, name := range claim.Names {
+		if _, ok := citedBy(name); ok {
+			continue
+		}
+		for _, key := range library.Keys() {
+			if e := library[key]; e.HasAuthor(name) {
+				issues = append(issues, factIssue(claim.File, claim.Line, models.SeverityWarning, "citation-mismatch",
+					fmt.Sprintf("Text names %s, but the citation points at %s rather than @%s.", name, citedKeys(cited), e.Key), claim.Text, 0.6))
+				break
+			}
+		}
+	}
+	return issues
+}
+
+func citedKeys(entries []citations.Entry) string {
+	keys := make([]string, 0, len(entries))
+	for _, e := range entries {
+		keys = append(keys, "@"+e.Key)
+	}
+	return strings.Join(keys, ", ")
+}
+
+// factNotes lists the bibliography and the uncited claims for the model pass.
+func factNotes(library citations.Library, claims []factClaim) string {
+	var b strings.Builder
+	if len(library) == 0 {
+		b.WriteString("Bibliography: none (docs/sources is empty).\n")
+	} else {
+		b.WriteString("Bibliography:\n")
+		for i, key := range library.Keys() {
+			if i == maxLibraryNotes {
+				fmt.Fprintf(&b, "- ... %d more\n", len(library)-maxLibraryNotes)
+				break
+			}
+			b.WriteString("- " + library[key].String() + "\n")
+		}
+	}
+	for _, claim := range claims {
+		if len(claim.Keys) == 0 && len(claim.Footnotes) == 0 {
+			fmt.Fprintf(&b, "Uncited claim already reported (line %d): %s\n", claim.Line, claim.Text)
+		}
+	}
+	return b.String()
+}
+
+func factIssue(file string, line int, severity models.Severity, category, message, quoted string, confidence float64) models.Issue {
+	return models.Issue{
+		Severity:   severity,
+		Category:   category,
+		Message:    message,
+		File:       file,
+		StartLine:  line,
+		EndLine:    line,
+		Excerpt:    quoted,
+		Confidence: confidence,
+	}
+}
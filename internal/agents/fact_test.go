@@ -0,0 +1,123 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+const factChapter = `# Chapter Three
+
+Cod landings fell by 40% between 1990 and 1992 [@smith2019]. The collapse surprised everyone.
+
+In 1992 the government closed the fishery for two years.
+
+Smith (2017) argued the moratorium came too late.[^1] Jones estimated losses at 30,000 jobs [@lee2001].
+
+See the missing source [@ghost2020] for more data on this.[^2]
+
+[^1]: Smith (2019), p. 12.
+[^3]: An unused note.
+`
+
+const factBibliography = `@article{smith2019,
+  author = {Smith, Jane and Jones, Robert},
+  title = {The Cod Collapse},
+  year = {2019}
+}
+@book{lee2001, author = {Ann Lee}, title = {Fisheries}, year = 2001}
+`
+
+func runFact(t *testing.T, input Input, sources ...manuscript.Document) Result {
+	t.Helper()
+	agent := factAgent{}
+	input.Context.Sources = sources
+	chunks := manuscript.ChunkDocuments([]manuscript.Document{{Path: "chapters/03.md", Content: factChapter}}, manuscript.ChunkOptions{})
+	var results []Result
+	for i := range chunks {
+		input.Chunk = &chunks[i]
+		r, err := agent.Run(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+		results = append(results, r)
+	}
+	merged, err := agent.Reduce(context.Background(), input, results)
+	if err != nil {
+		t.Fatalf("Reduce returned error: %v", err)
+	}
+	return merged
+}
+
+func TestFactAgentChecksClaimsAgainstSources(t *testing.T) {
+	result := runFact(t, Input{}, manuscript.Document{Path: "docs/sources/refs.bib", Kind: manuscript.KindSource, Content: factBibliography})
+
+	byCategory := issuesByCategory(result.Issues)
+	uncited := byCategory["uncited-claim"]
+	if len(uncited) != 1 || uncited[0].StartLine != 5 || uncited[0].StartColumn != 1 {
+		t.Fatalf("expected one uncited claim on line 5, got %+v", uncited)
+	}
+
+	mismatches := byCategory["citation-mismatch"]
+	if len(mismatches) != 2 {
+		t.Fatalf("expected year and author mismatches on line 7, got %+v", mismatches)
+	}
+	var messages []string
+	for _, m := range mismatches {
+		if m.StartLine != 7 {
+			t.Fatalf("expected mismatch on line 7, got %+v", m)
+		}
+		messages = append(messages, m.Message)
+	}
+	joined := strings.Join(messages, "\n")
+	if !strings.Contains(joined, "Smith (2017), but @smith2019 was published in 2019") || !strings.Contains(joined, "names Jones") {
+		t.Fatalf("unexpected mismatch messages:\n%s", joined)
+	}
+
+	var unknownKey, missingNote, unusedNote bool
+	for _, issue := range byCategory["citation"] {
+		switch {
+		case strings.Contains(issue.Message, "@ghost2020") && issue.Severity == models.SeverityError:
+			unknownKey = true
+		case strings.Contains(issue.Message, "[^2] has no definition"):
+			missingNote = true
+		case strings.Contains(issue.Message, "[^3] is defined but never referenced") && issue.StartLine == 12:
+			unusedNote = true
+		}
+	}
+	if !unknownKey || !missingNote || !unusedNote {
+		t.Fatalf("expected unknown key, missing and unused footnote issues, got %+v", byCategory["citation"])
+	}
+
+	if result.Metrics["claims"] != 5 || result.Metrics["uncited_claims"] != 1 || result.Metrics["sources"] != 2 {
+		t.Fatalf("unexpected metrics %v", result.Metrics)
+	}
+}
+
+func TestFactAgentWithoutBibliography(t *testing.T) {
+	result := runFact(t, Input{})
+
+	bibliography := issuesByCategory(result.Issues)["bibliography"]
+	if len(bibliography) != 1 || bibliography[0].Severity != models.SeverityInfo {
+		t.Fatalf("expected a single missing-bibliography note, got %+v", result.Issues)
+	}
+	for _, issue := range result.Issues {
+		if strings.Contains(issue.Message, "@ghost2020") {
+			t.Fatalf("expected keys not to be checked without a bibliography, got %+v", issue)
+		}
+	}
+}
+
+func TestFactAgentReportsUnreadableBibliography(t *testing.T) {
+	result := runFact(t, Input{}, manuscript.Document{Path: "docs/sources/refs.json", Kind: manuscript.KindSource, Content: "{not json"})
+
+	for _, issue := range issuesByCategory(result.Issues)["bibliography"] {
+		if issue.File == "docs/sources/refs.json" && issue.Severity == models.SeverityWarning {
+			return
+		}
+	}
+	t.Fatalf("expected the unreadable file to be reported, got %+v", result.Issues)
+}
@@ -0,0 +1,65 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+var ErrFakeResponsesExhausted = errors.New("fake provider has no responses left")
+
+// FakeProvider replays canned responses in order and records every request it receives.
+// It never touches the network, which makes agent runs deterministic in tests and offline.
+type FakeProvider struct {
+	mu        sync.Mutex
+	responses []CompletionResponse
+	next      int
+	requests  []CompletionRequest
+}
+
+func NewFakeProvider(responses ...CompletionResponse) *FakeProvider {
+	return &FakeProvider{responses: responses}
+}
+
+// LoadFakeProvider reads a JSON array of CompletionResponse fixtures from path.
+func LoadFakeProvider(path string) (*FakeProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fixtures: %w", err)
+	}
+	var responses []CompletionResponse
+	if err := json.Unmarshal(data, &responses); err != nil {
+		return nil, fmt.Errorf("decode fixtures: %w", err)
+	}
+	return NewFakeProvider(responses...), nil
+}
+
+func (f *FakeProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return CompletionResponse{}, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, req)
+	if f.next >= len(f.responses) {
+		return CompletionResponse{}, ErrFakeResponsesExhausted
+	}
+	resp := f.responses[f.next]
+	f.next++
+	if resp.Model == "" {
+		resp.Model = req.Model
+	}
+	return resp, nil
+}
+
+// Requests returns a copy of the requests received so far.
+func (f *FakeProvider) Requests() []CompletionRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]CompletionRequest(nil), f.requests...)
+}
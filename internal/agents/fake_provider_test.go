@@ -0,0 +1,39 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestFakeProviderReplaysFixtures(t *testing.T) {
+	provider, err := LoadFakeProvider(filepath.Join("testdata", "fake_responses.json"))
+	if err != nil {
+		t.Fatalf("LoadFakeProvider returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	first, err := provider.Complete(ctx, CompletionRequest{Model: "openai/gpt-4o-mini"})
+	if err != nil {
+		t.Fatalf("first Complete returned error: %v", err)
+	}
+	second, err := provider.Complete(ctx, CompletionRequest{Model: "anthropic/claude-3.5-sonnet"})
+	if err != nil {
+		t.Fatalf("second Complete returned error: %v", err)
+	}
+
+	if first.Usage.TotalTokens != 128 || second.Content != "Chapter 2 switches tense mid-scene." {
+		t.Fatalf("unexpected replay order: %+v, %+v", first, second)
+	}
+	if second.Model != "anthropic/claude-3.5-sonnet" {
+		t.Fatalf("expected missing fixture model to default to request model, got %q", second.Model)
+	}
+	if len(provider.Requests()) != 2 {
+		t.Fatalf("expected 2 recorded requests, got %d", len(provider.Requests()))
+	}
+
+	if _, err := provider.Complete(ctx, CompletionRequest{}); !errors.Is(err, ErrFakeResponsesExhausted) {
+		t.Fatalf("expected ErrFakeResponsesExhausted, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// promptAgent is a test agent that sends its instructions to the model and reports the
+// parsed reply. Without a provider it returns a single informational issue.
+type promptAgent struct {
+	name         string
+	trigger      string
+	requirements ContextRequirements
+	instructions string
+}
+
+func (a *promptAgent) Name() string                             { return a.name }
+func (a *promptAgent) DefaultTrigger() string                   { return a.trigger }
+func (a *promptAgent) ContextRequirements() ContextRequirements { return a.requirements }
+
+func (a *promptAgent) Run(ctx context.Context, input Input) (Result, error) {
+	if input.Provider == nil {
+		return Result{
+			Summary: fmt.Sprintf("%s agent completed", a.name),
+			Issues: []models.Issue{
+				{
+					Severity:   models.SeverityInfo,
+					Category:   "system",
+					Message:    "No model provider configured; analysis skipped.",
+					Confidence: 1,
+				},
+			},
+		}, nil
+	}
+
+	builtin, err := NewPromptTemplate(builtinPromptSource, a.instructions)
+	if err != nil {
+		return Result{}, err
+	}
+	instructions, err := input.instructions(builtin)
+	if err != nil {
+		return Result{}, err
+	}
+	parsed, usage, err := askModel(ctx, input, a.name, instructions, "")
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Summary: parsed.Summary, Issues: parsed.Issues, Usage: usage}, nil
+}
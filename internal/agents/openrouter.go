@@ -0,0 +1,123 @@
+package agents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// OpenRouterProvider calls the OpenRouter (OpenAI-compatible) chat completions API.
+type OpenRouterProvider struct {
+	Client *http.Client
+	APIURL string
+	APIKey string
+	// Referer and Title identify the app in OpenRouter's dashboard; both are optional.
+	Referer string
+	Title   string
+}
+
+func NewOpenRouterProvider(client *http.Client, apiKey string) *OpenRouterProvider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &OpenRouterProvider{
+		Client: client,
+		APIURL: "https://openrouter.ai/api/v1",
+		APIKey: apiKey,
+		Title:  "DraftForge",
+	}
+}
+
+type chatCompletionRequest struct {
+	Model       string    `json:"model"`
+	Messages    []Message `json:"messages"`
+	Temperature *float64  `json:"temperature,omitempty"`
+	MaxTokens   int       `json:"max_tokens,omitempty"`
+}
+
+type chatCompletionResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+	Usage Usage `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (p *OpenRouterProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	if p.APIKey == "" {
+		return CompletionResponse{}, errors.New("openrouter api key not configured")
+	}
+
+	body, err := json.Marshal(chatCompletionRequest{
+		Model:       req.Model,
+		Messages:    req.Messages,
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+	})
+	if err != nil {
+		return CompletionResponse{}, fmt.Errorf("marshal request: %w", err)
+	}
+
+	url := strings.TrimRight(p.APIURL, "/") + "/chat/completions"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+p.APIKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.Referer != "" {
+		httpReq.Header.Set("HTTP-Referer", p.Referer)
+	}
+	if p.Title != "" {
+		httpReq.Header.Set("X-Title", p.Title)
+	}
+
+	resp, err := p.Client.Do(httpReq)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return CompletionResponse{}, fmt.Errorf("read response: %w", err)
+	}
+
+	var parsed chatCompletionResponse
+	decodeErr := json.Unmarshal(respBody, &parsed)
+
+	if resp.StatusCode >= 300 {
+		message := strings.TrimSpace(string(respBody))
+		if decodeErr == nil && parsed.Error != nil && parsed.Error.Message != "" {
+			message = parsed.Error.Message
+		}
+		return CompletionResponse{}, &ProviderError{StatusCode: resp.StatusCode, Message: message}
+	}
+	if decodeErr != nil {
+		return CompletionResponse{}, fmt.Errorf("decode response: %w", decodeErr)
+	}
+	if parsed.Error != nil {
+		return CompletionResponse{}, &ProviderError{StatusCode: resp.StatusCode, Message: parsed.Error.Message}
+	}
+	if len(parsed.Choices) == 0 {
+		return CompletionResponse{}, errors.New("openrouter response contained no choices")
+	}
+
+	model := parsed.Model
+	if model == "" {
+		model = req.Model
+	}
+	return CompletionResponse{
+		Model:   model,
+		Content: parsed.Choices[0].Message.Content,
+		Usage:   parsed.Usage,
+	}, nil
+}
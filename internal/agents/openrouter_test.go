@@ -0,0 +1,76 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenRouterProviderComplete(t *testing.T) {
+	var got chatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("unexpected authorization header %q", r.Header.Get("Authorization"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"model": "openai/gpt-4o-mini",
+			"choices": [{"message": {"role": "assistant", "content": "All clear."}}],
+			"usage": {"prompt_tokens": 42, "completion_tokens": 3, "total_tokens": 45}
+		}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenRouterProvider(server.Client(), "test-key")
+	provider.APIURL = server.URL
+
+	temperature := 0.0
+	resp, err := provider.Complete(context.Background(), CompletionRequest{
+		Model:       "openai/gpt-4o-mini",
+		Messages:    []Message{{Role: "user", Content: "Check chapter 1"}},
+		Temperature: &temperature,
+		MaxTokens:   256,
+	})
+	if err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+
+	if resp.Content != "All clear." || resp.Usage.TotalTokens != 45 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if got.Model != "openai/gpt-4o-mini" || got.MaxTokens != 256 || got.Temperature == nil || *got.Temperature != 0 {
+		t.Fatalf("unexpected request payload %+v", got)
+	}
+	if len(got.Messages) != 1 || got.Messages[0].Content != "Check chapter 1" {
+		t.Fatalf("unexpected messages %+v", got.Messages)
+	}
+}
+
+func TestOpenRouterProviderErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error": {"message": "rate limited"}}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenRouterProvider(server.Client(), "test-key")
+	provider.APIURL = server.URL
+
+	_, err := provider.Complete(context.Background(), CompletionRequest{Model: "m"})
+	var providerErr *ProviderError
+	if !errors.As(err, &providerErr) {
+		t.Fatalf("expected ProviderError, got %v", err)
+	}
+	if providerErr.StatusCode != http.StatusTooManyRequests || providerErr.Message != "rate limited" {
+		t.Fatalf("unexpected provider error %+v", providerErr)
+	}
+}
@@ -0,0 +1,245 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// maxUpstreamIssues caps how many of an earlier stage's issues are passed on as context.
+const maxUpstreamIssues = 50
+
+var (
+	ErrInvalidPipeline  = errors.New("invalid pipeline")
+	ErrPipelineNotFound = errors.New("pipeline not found")
+)
+
+// PipelineStage is one agent in a pipeline. DependsOn names the agent types of earlier
+// stages whose results this stage waits for and receives as context.
+type PipelineStage struct {
+	AgentType string
+	DependsOn []string
+}
+
+// PipelineRequest queues several agents over the same files as one pipeline.
+type PipelineRequest struct {
+	ProjectID    int64
+	Trigger      string
+	FilesChanged []string
+	Stages       []PipelineStage
+}
+
+// QueuePipeline validates the stages, reserves credits for every run and queues them
+// together under a new pipeline ID. Stages without dependencies may run in parallel;
+// a stage that fails, times out or is cancelled cancels every stage downstream of it.
+func (s *Service) QueuePipeline(ctx context.Context, req PipelineRequest) (models.Pipeline, error) {
+	order, dependsOn, err := orderStages(req.Stages)
+	if err != nil {
+		return models.Pipeline{}, err
+	}
+
+	pipelineID := uuid.NewString()
+	runs := make([]models.AgentRun, 0, len(order))
+	release := func() {
+		for _, run := range runs {
+			s.releaseCredits(ctx, run)
+		}
+	}
+	for _, i := range order {
+		plan, err := s.planRun(ctx, RunRequest{
+			ProjectID:    req.ProjectID,
+			AgentType:    req.Stages[i].AgentType,
+			Trigger:      req.Trigger,
+			FilesChanged: req.FilesChanged,
+		})
+		if err != nil {
+			release()
+			return models.Pipeline{}, err
+		}
+		run, err := s.reserveCredits(ctx, plan)
+		if err != nil {
+			release()
+			return models.Pipeline{}, err
+		}
+		run.PipelineID = pipelineID
+		runs = append(runs, run)
+	}
+
+	inserted, err := s.store.InsertPipeline(ctx, runs, dependsOn)
+	if err != nil {
+		release()
+		return models.Pipeline{}, fmt.Errorf("insert pipeline: %w", err)
+	}
+	for _, run := range inserted {
+		s.publish(ctx, run, models.RunEvent{Type: models.RunEventQueued})
+	}
+	s.nudgeWorker()
+
+	return newPipeline(pipelineID, inserted), nil
+}
+
+// GetPipeline returns a pipeline's runs and aggregated status.
+func (s *Service) GetPipeline(ctx context.Context, projectID int64, pipelineID string) (models.Pipeline, error) {
+	if _, err := uuid.Parse(pipelineID); err != nil {
+		return models.Pipeline{}, ErrPipelineNotFound
+	}
+	runs, err := s.store.ListPipelineRuns(ctx, pipelineID)
+	if err != nil {
+		return models.Pipeline{}, err
+	}
+	if len(runs) == 0 || runs[0].ProjectID != projectID {
+		return models.Pipeline{}, ErrPipelineNotFound
+	}
+	return newPipeline(pipelineID, runs), nil
+}
+
+func newPipeline(id string, runs []models.AgentRun) models.Pipeline {
+	return models.Pipeline{
+		ID:        id,
+		ProjectID: runs[0].ProjectID,
+		Status:    models.PipelineStatus(runs),
+		Runs:      runs,
+		CreatedAt: runs[0].CreatedAt,
+	}
+}
+
+// orderStages returns the stage indexes in dependency order (keeping request order
+// where dependencies allow) and, for each ordered stage, the positions in that order
+// of the stages it depends on.
+func orderStages(stages []PipelineStage) ([]int, [][]int, error) {
+	if len(stages) == 0 {
+		return nil, nil, fmt.Errorf("%w: no stages", ErrInvalidPipeline)
+	}
+	index := make(map[string]int, len(stages))
+	for i, stage := range stages {
+		if _, dup := index[stage.AgentType]; dup {
+			return nil, nil, fmt.Errorf("%w: %s appears twice", ErrInvalidPipeline, stage.AgentType)
+		}
+		index[stage.AgentType] = i
+	}
+	for _, stage := range stages {
+		for _, dep := range stage.DependsOn {
+			if _, ok := index[dep]; !ok || dep == stage.AgentType {
+				return nil, nil, fmt.Errorf("%w: %s cannot depend on %q", ErrInvalidPipeline, stage.AgentType, dep)
+			}
+		}
+	}
+
+	position := make(map[string]int, len(stages))
+	var order []int
+	var dependsOn [][]int
+	for len(order) < len(stages) {
+		progressed := false
+		for i, stage := range stages {
+			if _, placed := position[stage.AgentType]; placed {
+				continue
+			}
+			deps := make([]int, 0, len(stage.DependsOn))
+			ready := true
+			for _, dep := range stage.DependsOn {
+				p, ok := position[dep]
+				if !ok {
+					ready = false
+					break
+				}
+				deps = append(deps, p)
+			}
+			if !ready {
+				continue
+			}
+			position[stage.AgentType] = len(order)
+			order = append(order, i)
+			dependsOn = append(dependsOn, deps)
+			progressed = true
+		}
+		if !progressed {
+			return nil, nil, fmt.Errorf("%w: dependency cycle", ErrInvalidPipeline)
+		}
+	}
+	return order, dependsOn, nil
+}
+
+// upstreamResults loads the completed runs a pipeline run depends on.
+func (s *Service) upstreamResults(ctx context.Context, run models.AgentRun) ([]models.AgentRun, error) {
+	var upstream []models.AgentRun
+	for _, id := range run.DependsOn {
+		dep, err := s.store.GetRun(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("load run %d: %w", id, err)
+		}
+		if dep.Status == "completed" && dep.Results != nil {
+			upstream = append(upstream, dep)
+		}
+	}
+	return upstream, nil
+}
+
+// cancelDependents cancels and refunds the queued runs downstream of a pipeline run
+// that will not complete.
+func (s *Service) cancelDependents(ctx context.Context, run models.AgentRun) {
+	if run.PipelineID == "" {
+		return
+	}
+	message := upstreamMessage(run)
+	cancelled, err := s.store.CancelDependents(ctx, run.ID, message, s.now())
+	if err != nil {
+		log.Printf("agent pipeline %s: %v", run.PipelineID, err)
+		return
+	}
+	for _, dep := range cancelled {
+		s.settleCredits(ctx, dep.ID, false)
+		s.publish(ctx, dep, models.RunEvent{Type: models.RunEventCancelled, Error: message})
+	}
+}
+
+// upstreamMessage is the error recorded on the runs cancelled because run did not complete.
+func upstreamMessage(run models.AgentRun) string {
+	return fmt.Sprintf("upstream %s run %d did not complete", run.AgentType, run.ID)
+}
+
+// cancelledDependents lists the pipeline runs that were cancelled because run did not
+// complete, so they can be requeued along with it.
+func (s *Service) cancelledDependents(ctx context.Context, run models.AgentRun) ([]models.AgentRun, error) {
+	if run.PipelineID == "" {
+		return nil, nil
+	}
+	runs, err := s.store.ListPipelineRuns(ctx, run.PipelineID)
+	if err != nil {
+		return nil, fmt.Errorf("list pipeline runs: %w", err)
+	}
+	message := upstreamMessage(run)
+	var cancelled []models.AgentRun
+	for _, dep := range runs {
+		if dep.Status == "cancelled" && dep.Error == message {
+			cancelled = append(cancelled, dep)
+		}
+	}
+	return cancelled, nil
+}
+
+// renderUpstream describes an earlier stage's results for a later stage's prompt.
+func renderUpstream(run models.AgentRun) string {
+	var b strings.Builder
+	if summary := strings.TrimSpace(run.Results.Summary); summary != "" {
+		b.WriteString(summary)
+		b.WriteString("\n")
+	}
+	for i, issue := range run.Results.Issues {
+		if i == maxUpstreamIssues {
+			fmt.Fprintf(&b, "- ... %d more issues\n", len(run.Results.Issues)-i)
+			break
+		}
+		location := issue.File
+		if issue.StartLine > 0 {
+			location = fmt.Sprintf("%s:%d", issue.File, issue.StartLine)
+		}
+		fmt.Fprintf(&b, "- [%s] %s %s: %s\n", issue.Severity, location, issue.Category, issue.Message)
+	}
+	return b.String()
+}
@@ -0,0 +1,185 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+func TestOrderStages(t *testing.T) {
+	order, dependsOn, err := orderStages([]PipelineStage{
+		{AgentType: "style", DependsOn: []string{"continuity", "timeline"}},
+		{AgentType: "continuity"},
+		{AgentType: "timeline", DependsOn: []string{"continuity"}},
+	})
+	if err != nil {
+		t.Fatalf("orderStages returned error: %v", err)
+	}
+	if len(order) != 3 || order[0] != 1 || order[1] != 2 || order[2] != 0 {
+		t.Fatalf("expected continuity, timeline, style, got %v", order)
+	}
+	if len(dependsOn[0]) != 0 || dependsOn[1][0] != 0 || len(dependsOn[2]) != 2 || dependsOn[2][1] != 1 {
+		t.Fatalf("expected dependencies by position, got %v", dependsOn)
+	}
+
+	invalid := [][]PipelineStage{
+		nil,
+		{{AgentType: "style"}, {AgentType: "style"}},
+		{{AgentType: "style", DependsOn: []string{"pacing"}}},
+		{{AgentType: "style", DependsOn: []string{"style"}}},
+		{{AgentType: "style", DependsOn: []string{"timeline"}}, {AgentType: "timeline", DependsOn: []string{"style"}}},
+	}
+	for _, stages := range invalid {
+		if _, _, err := orderStages(stages); !errors.Is(err, ErrInvalidPipeline) {
+			t.Errorf("%+v: expected ErrInvalidPipeline, got %v", stages, err)
+		}
+	}
+}
+
+func TestPipelinePassesUpstreamResultsToLaterStages(t *testing.T) {
+	store := newMockStore()
+	first := &stubAgent{name: "continuity", result: Result{
+		Summary: "Eye colour drifts.",
+		Issues:  []models.Issue{{Severity: models.SeverityWarning, Category: "continuity", Message: "Eyes change colour.", File: "chapters/02.md", StartLine: 3}},
+	}}
+	second := &stubAgent{name: "style"}
+	registry := NewRegistry()
+	_ = registry.Register(first)
+	_ = registry.Register(second)
+	svc := NewService(store, t.TempDir(), WithRegistry(registry))
+	ctx := context.Background()
+
+	pipeline, err := svc.QueuePipeline(ctx, PipelineRequest{ProjectID: 1, Stages: []PipelineStage{
+		{AgentType: "style", DependsOn: []string{"continuity"}},
+		{AgentType: "continuity"},
+	}})
+	if err != nil {
+		t.Fatalf("QueuePipeline returned error: %v", err)
+	}
+	if pipeline.Status != "queued" || len(pipeline.Runs) != 2 || pipeline.Runs[1].DependsOn[0] != pipeline.Runs[0].ID {
+		t.Fatalf("expected two queued runs with style after continuity, got %+v", pipeline)
+	}
+
+	claimed, _ := svc.claimNextRun(ctx)
+	if claimed.AgentType != "continuity" {
+		t.Fatalf("expected continuity to run first, got %+v", claimed)
+	}
+	if _, err := svc.claimNextRun(ctx); !errors.Is(err, models.ErrNotFound) {
+		t.Fatalf("expected style to wait for continuity, got %v", err)
+	}
+	if got, _ := svc.GetPipeline(ctx, 1, pipeline.ID); got.Status != "running" {
+		t.Fatalf("expected a running pipeline, got %s", got.Status)
+	}
+	if err := svc.executeRun(ctx, claimed); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+
+	claimed, err = svc.claimNextRun(ctx)
+	if err != nil || claimed.AgentType != "style" {
+		t.Fatalf("expected style to be claimable, got %+v, %v", claimed, err)
+	}
+	if err := svc.executeRun(ctx, claimed); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+	upstream := second.inputs[0].Upstream
+	if len(upstream) != 1 || upstream[0].AgentType != "continuity" {
+		t.Fatalf("expected continuity results as upstream context, got %+v", upstream)
+	}
+	if rendered := renderUpstream(upstream[0]); !strings.Contains(rendered, "Eye colour drifts.") || !strings.Contains(rendered, "chapters/02.md:3 continuity: Eyes change colour.") {
+		t.Fatalf("unexpected upstream rendering %q", rendered)
+	}
+
+	got, err := svc.GetPipeline(ctx, 1, pipeline.ID)
+	if err != nil || got.Status != "completed" {
+		t.Fatalf("expected a completed pipeline, got %+v, %v", got, err)
+	}
+	if _, err := svc.GetPipeline(ctx, 2, pipeline.ID); !errors.Is(err, ErrPipelineNotFound) {
+		t.Fatalf("expected another project's pipeline to be hidden, got %v", err)
+	}
+}
+
+func TestPipelineFailureCancelsDependents(t *testing.T) {
+	store := newMockStore()
+	registry := NewRegistry()
+	_ = registry.Register(&stubAgent{name: "continuity", err: errors.New("boom")})
+	_ = registry.Register(&stubAgent{name: "timeline"})
+	_ = registry.Register(&stubAgent{name: "style"})
+	svc := NewService(store, t.TempDir(), WithRegistry(registry), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	ctx := context.Background()
+
+	pipeline, err := svc.QueuePipeline(ctx, PipelineRequest{ProjectID: 1, Stages: []PipelineStage{
+		{AgentType: "continuity"},
+		{AgentType: "timeline", DependsOn: []string{"continuity"}},
+		{AgentType: "style", DependsOn: []string{"timeline"}},
+	}})
+	if err != nil {
+		t.Fatalf("QueuePipeline returned error: %v", err)
+	}
+
+	claimed, _ := svc.claimNextRun(ctx)
+	if err := svc.executeRun(ctx, claimed); err == nil {
+		t.Fatal("expected executeRun to fail")
+	}
+
+	got, _ := svc.GetPipeline(ctx, 1, pipeline.ID)
+	if got.Status != "failed" {
+		t.Fatalf("expected a failed pipeline, got %+v", got)
+	}
+	for _, run := range got.Runs[1:] {
+		if run.Status != "cancelled" || !strings.Contains(run.Error, "continuity run 1") {
+			t.Fatalf("expected downstream runs to be cancelled, got %+v", run)
+		}
+	}
+}
+
+func TestRequeueRevivesCancelledDependents(t *testing.T) {
+	store := newMockStore()
+	registry := NewRegistry()
+	continuity := &stubAgent{name: "continuity", err: &ProviderError{StatusCode: 503, Message: "unavailable"}}
+	_ = registry.Register(continuity)
+	_ = registry.Register(&stubAgent{name: "timeline"})
+	_ = registry.Register(&stubAgent{name: "style"})
+	svc := NewService(store, t.TempDir(), WithRegistry(registry), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	ctx := context.Background()
+
+	pipeline, err := svc.QueuePipeline(ctx, PipelineRequest{ProjectID: 1, Stages: []PipelineStage{
+		{AgentType: "continuity"},
+		{AgentType: "timeline", DependsOn: []string{"continuity"}},
+		{AgentType: "style", DependsOn: []string{"timeline"}},
+	}})
+	if err != nil {
+		t.Fatalf("QueuePipeline returned error: %v", err)
+	}
+	claimed, _ := svc.claimNextRun(ctx)
+	_ = svc.executeRun(ctx, claimed)
+	if run, _ := store.GetRun(ctx, claimed.ID); run.Status != "dead_letter" {
+		t.Fatalf("expected the first stage to be dead-lettered, got %+v", run)
+	}
+
+	continuity.err = nil
+	if _, err := svc.RequeueRun(ctx, 1, claimed.ID); err != nil {
+		t.Fatalf("RequeueRun returned error: %v", err)
+	}
+	got, _ := svc.GetPipeline(ctx, 1, pipeline.ID)
+	for _, run := range got.Runs {
+		if run.Status != "queued" || run.Error != "" {
+			t.Fatalf("expected every stage back on the queue, got %+v", run)
+		}
+	}
+
+	for range got.Runs {
+		next, err := svc.claimNextRun(ctx)
+		if err != nil {
+			t.Fatalf("claimNextRun returned error: %v", err)
+		}
+		if err := svc.executeRun(ctx, next); err != nil {
+			t.Fatalf("executeRun returned error: %v", err)
+		}
+	}
+	if got, _ := svc.GetPipeline(ctx, 1, pipeline.ID); got.Status != "completed" {
+		t.Fatalf("expected the requeued pipeline to complete, got %+v", got)
+	}
+}
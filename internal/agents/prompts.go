@@ -0,0 +1,129 @@
+package agents
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// ProjectPromptsDir holds a project's prompt overrides, one <agent>.md per agent.
+const ProjectPromptsDir = ".draftforge/prompts"
+
+// builtinPromptSource is the PromptTemplate.Source of the embedded defaults.
+const builtinPromptSource = "builtin"
+
+//go:embed prompts/*.md
+var promptsFS embed.FS
+
+// PromptTemplate is an agent's instructions as a Go text/template, rendered over the
+// run's context before each model call.
+type PromptTemplate struct {
+	// Source is "builtin" or the repo-relative path of a project override.
+	Source string
+	// Text is the unrendered template.
+	Text string
+	// Hash is the SHA-256 of Text, recorded on runs so results can be traced to the
+	// exact prompt that produced them.
+	Hash string
+	tmpl *template.Template
+}
+
+// promptData is what prompt templates are rendered over:
+//
+//	{{.Agent}} {{.Trigger}}     agent type and run trigger
+//	{{.Files}}                  files changed on the run ({{join .Files ", "}})
+//	{{.Documents}}              reference documents (.Path, .Kind, .Content)
+//	{{.Chunk}}                  the chunk under review, or nil (.Path, .StartLine, .EndLine, .Label)
+//	{{.Upstream}}               completed runs of earlier pipeline stages
+//	{{.Editorial}}              the current editing round (.Round, .Goals, .AntiGoals)
+type promptData struct {
+	Agent     string
+	Trigger   string
+	Files     []string
+	Documents []manuscript.Document
+	Chunk     *manuscript.Chunk
+	Upstream  []models.AgentRun
+	Editorial manuscript.Editorial
+}
+
+var promptFuncs = template.FuncMap{"join": strings.Join}
+
+// NewPromptTemplate parses a prompt template. Templates that fail to parse are
+// reported as ErrInvalidAgentConfig.
+func NewPromptTemplate(source, text string) (*PromptTemplate, error) {
+	tmpl, err := template.New(source).Funcs(promptFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: prompt %s: %v", ErrInvalidAgentConfig, source, err)
+	}
+	sum := sha256.Sum256([]byte(text))
+	return &PromptTemplate{Source: source, Text: text, Hash: hex.EncodeToString(sum[:]), tmpl: tmpl}, nil
+}
+
+// Render executes the template for one model call.
+func (p *PromptTemplate) Render(input Input) (string, error) {
+	data := promptData{
+		Agent:     input.Run.AgentType,
+		Trigger:   input.Run.Trigger,
+		Files:     input.Files,
+		Documents: input.Context.Documents,
+		Chunk:     input.Chunk,
+		Upstream:  input.Upstream,
+		Editorial: input.Editorial,
+	}
+	var b strings.Builder
+	if err := p.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("render prompt %s: %w", p.Source, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// builtinPrompt returns the embedded default prompt for an agent, or nil when it has none.
+func builtinPrompt(agent string) *PromptTemplate {
+	data, err := promptsFS.ReadFile(path.Join("prompts", agent+".md"))
+	if err != nil {
+		return nil
+	}
+	prompt, err := NewPromptTemplate(builtinPromptSource, string(data))
+	if err != nil {
+		panic(err)
+	}
+	return prompt
+}
+
+// mustBuiltinPrompt returns an embedded default prompt that the agent cannot run without.
+func mustBuiltinPrompt(agent string) *PromptTemplate {
+	prompt := builtinPrompt(agent)
+	if prompt == nil {
+		panic(fmt.Sprintf("agents: no built-in prompt for %s", agent))
+	}
+	return prompt
+}
+
+// loadPrompt resolves an agent's prompt template: the agents.yaml "prompt" file if set,
+// else .draftforge/prompts/<agent>.md when the project has one, else the embedded
+// default. It returns nil for agents without any prompt.
+func loadPrompt(root, agent string, cfg AgentConfig) (*PromptTemplate, error) {
+	if root != "" {
+		rel := cfg.Prompt
+		if rel == "" {
+			rel = path.Join(ProjectPromptsDir, agent+".md")
+		}
+		content, err := manuscript.ReadFile(root, rel)
+		switch {
+		case err == nil:
+			return NewPromptTemplate(rel, content)
+		case cfg.Prompt != "" || !errors.Is(err, fs.ErrNotExist):
+			return nil, fmt.Errorf("%w: prompt %s: %v", ErrInvalidAgentConfig, rel, err)
+		}
+	}
+	return builtinPrompt(agent), nil
+}
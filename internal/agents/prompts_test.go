@@ -0,0 +1,122 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+func TestBuiltinPromptsExistForDefaultAgents(t *testing.T) {
+	for _, agent := range []string{"continuity", "fact", "style", "timeline"} {
+		prompt := builtinPrompt(agent)
+		if prompt == nil || prompt.Source != builtinPromptSource || len(prompt.Hash) != 64 {
+			t.Fatalf("expected a built-in prompt for %s, got %+v", agent, prompt)
+		}
+	}
+	if builtinPrompt("notes") != nil {
+		t.Fatal("expected no built-in prompt for an unknown agent")
+	}
+}
+
+func TestLoadPromptPrefersProjectOverride(t *testing.T) {
+	root := t.TempDir()
+
+	prompt, err := loadPrompt(root, "style", AgentConfig{})
+	if err != nil || prompt.Source != builtinPromptSource {
+		t.Fatalf("expected the built-in prompt without an override, got %+v %v", prompt, err)
+	}
+
+	writeFile(t, root, ".draftforge/prompts/style.md", "House style for {{.Agent}}.")
+	override, err := loadPrompt(root, "style", AgentConfig{})
+	if err != nil || override.Source != ".draftforge/prompts/style.md" || override.Hash == prompt.Hash {
+		t.Fatalf("expected the project override, got %+v %v", override, err)
+	}
+
+	if _, err := loadPrompt(root, "style", AgentConfig{Prompt: "missing.md"}); !errors.Is(err, ErrInvalidAgentConfig) {
+		t.Fatalf("expected a missing configured prompt to be rejected, got %v", err)
+	}
+}
+
+func TestPromptTemplateRendersContext(t *testing.T) {
+	prompt, err := NewPromptTemplate("custom", "{{.Agent}} on {{join .Files \", \"}}{{with .Chunk}} at {{.Label}}{{end}}.\n")
+	if err != nil {
+		t.Fatalf("NewPromptTemplate returned error: %v", err)
+	}
+	chunk := manuscript.Chunk{Path: "chapters/01.md", StartLine: 1, EndLine: 9}
+	got, err := prompt.Render(Input{
+		Run:   models.AgentRun{AgentType: "style"},
+		Files: []string{"chapters/01.md", "chapters/02.md"},
+		Chunk: &chunk,
+	})
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	want := "style on chapters/01.md, chapters/02.md at " + chunk.Label() + "."
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if _, err := NewPromptTemplate("broken", "{{.Agent"); !errors.Is(err, ErrInvalidAgentConfig) {
+		t.Fatalf("expected an unparsable template to be rejected, got %v", err)
+	}
+	bad, _ := NewPromptTemplate("bad", "{{.Unknown}}")
+	if _, err := bad.Render(Input{}); err == nil {
+		t.Fatal("expected an unknown field to fail rendering")
+	}
+}
+
+func TestExecuteRunRecordsPromptHash(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "chapters/01.md", "# One\n\nMara walked home.\n")
+	writeFile(t, root, ".draftforge/prompts/style.md", "Review {{with .Chunk}}{{.Path}}{{end}} for house style.")
+
+	provider := NewFakeProvider(CompletionResponse{Content: `{"summary": "Fine.", "issues": []}`})
+	store := newMockStore()
+	svc := NewService(store, t.TempDir(),
+		WithProvider(provider, "test/model"),
+		WithWorkspaces(stubWorkspaces{1: root}, nil),
+	)
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "style", FilesChanged: []string{"chapters/01.md"}}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	claimed, _ := svc.claimNextRun(ctx)
+	if err := svc.executeRun(ctx, claimed); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+
+	override, _ := loadPrompt(root, "style", AgentConfig{})
+	run, _ := store.GetRun(ctx, claimed.ID)
+	if run.PromptHash != override.Hash {
+		t.Fatalf("expected the override's hash on the run, got %q", run.PromptHash)
+	}
+	reqs := provider.Requests()
+	if len(reqs) != 1 || !strings.Contains(reqs[0].Messages[0].Content, "Review chapters/01.md for house style.") {
+		t.Fatalf("expected the rendered override in the system prompt, got %+v", reqs)
+	}
+}
+
+func TestExecuteRunReportsInvalidPrompt(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, ".draftforge/prompts/style.md", "{{.Agent")
+	store := newMockStore()
+	svc := NewService(store, t.TempDir(), WithWorkspaces(stubWorkspaces{1: root}, nil))
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "style"}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	claimed, _ := svc.claimNextRun(ctx)
+	err := svc.executeRun(ctx, claimed)
+	if !errors.Is(err, ErrInvalidAgentConfig) || !strings.HasPrefix(err.Error(), "load prompt: ") {
+		t.Fatalf("expected a load prompt error, got %v", err)
+	}
+	if run, _ := store.GetRun(ctx, claimed.ID); run.Status != "failed" {
+		t.Fatalf("expected the run to fail, got %+v", run)
+	}
+}
@@ -0,0 +1,106 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// DefaultModel is used when neither the caller nor the project config selects a model.
+const DefaultModel = "openai/gpt-4o-mini"
+
+// Message is a single chat message exchanged with a model.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// CompletionRequest describes a chat completion call. Temperature is optional so callers
+// can distinguish "unset" from an explicit zero.
+type CompletionRequest struct {
+	Model       string
+	Messages    []Message
+	Temperature *float64
+	MaxTokens   int
+}
+
+// Usage reports token consumption for a single completion.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// CompletionResponse is the provider-agnostic result of a completion call.
+type CompletionResponse struct {
+	Model   string `json:"model"`
+	Content string `json:"content"`
+	Usage   Usage  `json:"usage"`
+}
+
+// Provider sends chat completion requests to a model backend (OpenRouter in production,
+// FakeProvider in tests and offline development).
+type Provider interface {
+	Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error)
+}
+
+// ProviderError is returned when a provider responds with a non-success status.
+type ProviderError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("provider returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// routedProvider applies a project's per-agent model settings (from agents.yaml) to each
+// request and retries with the fallback models, in order, when a call fails in a way
+// another model might not.
+type routedProvider struct {
+	provider    Provider
+	fallbacks   []string
+	temperature *float64
+	maxTokens   int
+}
+
+// Complete returns the first successful response. When every candidate fails, or one
+// fails in a way no other model would fix, the error joins every attempt's error.
+func (p routedProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	if req.Temperature == nil {
+		req.Temperature = p.temperature
+	}
+	if req.MaxTokens == 0 {
+		req.MaxTokens = p.maxTokens
+	}
+
+	candidates := append([]string{req.Model}, p.fallbacks...)
+	var errs []error
+	for _, model := range candidates {
+		req.Model = model
+		resp, err := p.provider.Complete(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return CompletionResponse{}, err
+		}
+		errs = append(errs, fmt.Errorf("model %s: %w", model, err))
+		if !canFallBack(err) {
+			break
+		}
+	}
+	return CompletionResponse{}, errors.Join(errs...)
+}
+
+// canFallBack reports whether another model might succeed where this one failed:
+// transient errors and missing models. Authentication and other request errors would
+// fail the same way on any model.
+func canFallBack(err error) bool {
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) && providerErr.StatusCode == http.StatusNotFound {
+		return true
+	}
+	return isTransient(err)
+}
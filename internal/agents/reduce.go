@@ -0,0 +1,101 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// Reducer is implemented by agents that need to combine per-chunk results themselves,
+// for example to reason about events across the whole manuscript. Agents that do not
+// implement it get mergeResults.
+type Reducer interface {
+	Reduce(ctx context.Context, input Input, results []Result) (Result, error)
+}
+
+// mergeResults combines per-chunk results: usage is summed, issues are deduplicated
+// (overlapping chunks often report the same finding twice) and sorted by location.
+func mergeResults(chunks []manuscript.Chunk, results []Result) Result {
+	if len(results) == 1 {
+		merged := results[0]
+		merged.Issues = dedupeIssues(merged.Issues)
+		return merged
+	}
+
+	var merged Result
+	var summaries []string
+	var all []models.Issue
+	for i, r := range results {
+		merged.Usage.PromptTokens += r.Usage.PromptTokens
+		merged.Usage.CompletionTokens += r.Usage.CompletionTokens
+		merged.Usage.TotalTokens += r.Usage.TotalTokens
+		all = append(all, r.Issues...)
+
+		if summary := strings.TrimSpace(r.Summary); summary != "" && i < len(chunks) {
+			summaries = append(summaries, fmt.Sprintf("- %s: %s", chunks[i].Label(), summary))
+		}
+	}
+
+	merged.Issues = dedupeIssues(all)
+	merged.Summary = fmt.Sprintf("Analysed %d chunks; %d issues found.", len(results), len(merged.Issues))
+	if len(summaries) > 0 {
+		merged.Summary += "\n\n" + strings.Join(summaries, "\n")
+	}
+	return merged
+}
+
+// dedupeIssues drops repeated findings, keeping the most severe/confident copy. Issues
+// are considered the same when they share file, start line, category and either the
+// quoted excerpt or, when there is no excerpt, the message.
+func dedupeIssues(issues []models.Issue) []models.Issue {
+	index := make(map[string]int, len(issues))
+	var out []models.Issue
+	for _, issue := range issues {
+		key := issueKey(issue)
+		if i, ok := index[key]; ok {
+			if outranks(issue, out[i]) {
+				out[i] = issue
+			}
+			continue
+		}
+		index[key] = len(out)
+		out = append(out, issue)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].File != out[j].File {
+			return out[i].File < out[j].File
+		}
+		return out[i].StartLine < out[j].StartLine
+	})
+	return out
+}
+
+func issueKey(issue models.Issue) string {
+	text := normaliseText(issue.Excerpt)
+	if text == "" {
+		text = normaliseText(issue.Message)
+	}
+	return strings.Join([]string{issue.File, fmt.Sprint(issue.StartLine), issue.Category, text}, "|")
+}
+
+func normaliseText(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+var severityRank = map[models.Severity]int{
+	models.SeverityInfo:    0,
+	models.SeverityWarning: 1,
+	models.SeverityError:   2,
+}
+
+func outranks(a, b models.Issue) bool {
+	if severityRank[a.Severity] != severityRank[b.Severity] {
+		return severityRank[a.Severity] > severityRank[b.Severity]
+	}
+	return a.Confidence > b.Confidence
+}
@@ -0,0 +1,104 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+func TestMergeResultsDedupesOverlappingIssues(t *testing.T) {
+	chunks := []manuscript.Chunk{
+		{Path: "chapters/01.md", StartLine: 1, EndLine: 40},
+		{Path: "chapters/01.md", StartLine: 35, EndLine: 80},
+	}
+	shared := models.Issue{Severity: models.SeverityWarning, Category: "tense", Message: "Tense shift.", File: "chapters/01.md", StartLine: 38, Excerpt: "she walks", Confidence: 0.6}
+	stronger := shared
+	stronger.Severity = models.SeverityError
+
+	merged := mergeResults(chunks, []Result{
+		{Summary: "Tense wobbles.", Issues: []models.Issue{shared}, Usage: Usage{TotalTokens: 100}},
+		{Summary: "", Issues: []models.Issue{stronger, {Severity: models.SeverityInfo, Category: "pacing", Message: "Quick.", File: "chapters/01.md", StartLine: 2}}, Usage: Usage{TotalTokens: 50}},
+	})
+
+	if merged.Usage.TotalTokens != 150 {
+		t.Fatalf("expected summed usage, got %d", merged.Usage.TotalTokens)
+	}
+	if len(merged.Issues) != 2 {
+		t.Fatalf("expected overlapping issue to be deduplicated, got %+v", merged.Issues)
+	}
+	if merged.Issues[0].StartLine != 2 || merged.Issues[1].Severity != models.SeverityError {
+		t.Fatalf("expected sorted issues keeping the most severe copy, got %+v", merged.Issues)
+	}
+	if !strings.Contains(merged.Summary, "chapters/01.md:1-40: Tense wobbles.") {
+		t.Fatalf("expected per-chunk summary, got %q", merged.Summary)
+	}
+}
+
+func TestExecuteRunMapsChunksAndRecordsProgress(t *testing.T) {
+	root := t.TempDir()
+	scene := strings.Repeat("The harbour lights flickered in the rain.\n", 20)
+	writeFile(t, root, "chapters/01.md", scene+"\n***\n"+scene)
+	writeFile(t, root, "chapters/02.md", scene)
+
+	registry := NewRegistry()
+	agent := &reducingAgent{}
+	_ = registry.Register(agent)
+
+	store := newMockStore()
+	svc := NewService(store, t.TempDir(),
+		WithRegistry(registry),
+		WithWorkspaces(stubWorkspaces{1: root}, nil),
+		WithChunking(manuscript.ChunkOptions{MaxTokens: 250}),
+	)
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "timeline", FilesChanged: []string{"chapters/01.md", "chapters/02.md"}}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	claimed, _ := svc.claimNextRun(ctx)
+	if err := svc.executeRun(ctx, claimed); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+
+	if len(agent.chunks) != 3 {
+		t.Fatalf("expected one call per chunk, got %v", agent.chunks)
+	}
+	if agent.reduced != 3 {
+		t.Fatalf("expected Reduce to receive 3 results, got %d", agent.reduced)
+	}
+
+	run := store.runs[claimed.ID]
+	if run.Progress == nil || run.Progress.ChunksCompleted != 3 || run.Progress.ChunksTotal != 3 {
+		t.Fatalf("unexpected final progress %+v", run.Progress)
+	}
+	if len(store.progress) != 4 || store.progress[1].CurrentChunk != agent.chunks[1] {
+		t.Fatalf("expected progress before each chunk and at the end, got %+v", store.progress)
+	}
+	if run.Results.Stats.ChunksAnalyzed != 3 || run.Results.Summary != "3 chunks reduced" {
+		t.Fatalf("unexpected results %+v", run.Results)
+	}
+}
+
+type reducingAgent struct {
+	chunks  []string
+	reduced int
+}
+
+func (a *reducingAgent) Name() string           { return "timeline" }
+func (a *reducingAgent) DefaultTrigger() string { return "manual" }
+func (a *reducingAgent) ContextRequirements() ContextRequirements {
+	return ContextRequirements{ChangedFiles: true}
+}
+
+func (a *reducingAgent) Run(_ context.Context, input Input) (Result, error) {
+	a.chunks = append(a.chunks, input.Chunk.Label())
+	return Result{Summary: input.Chunk.Label()}, nil
+}
+
+func (a *reducingAgent) Reduce(_ context.Context, _ Input, results []Result) (Result, error) {
+	a.reduced = len(results)
+	return Result{Summary: "3 chunks reduced"}, nil
+}
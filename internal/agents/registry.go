@@ -0,0 +1,72 @@
+package agents
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var ErrAgentAlreadyRegistered = errors.New("agent already registered")
+
+// Registry maps agent type names to implementations.
+type Registry struct {
+	mu     sync.RWMutex
+	agents map[string]Agent
+}
+
+func NewRegistry() *Registry {
+	return &Registry{agents: make(map[string]Agent)}
+}
+
+// DefaultRegistry returns a registry containing the built-in agents without persistence.
+func DefaultRegistry() *Registry {
+	return NewBuiltinRegistry(BuiltinDeps{})
+}
+
+// NewBuiltinRegistry returns a registry containing the built-in agents wired to deps.
+func NewBuiltinRegistry(deps BuiltinDeps) *Registry {
+	r := NewRegistry()
+	for _, agent := range builtinAgents(deps) {
+		if err := r.Register(agent); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register adds an agent. Names must be unique and non-empty.
+func (r *Registry) Register(agent Agent) error {
+	name := agent.Name()
+	if name == "" {
+		return errors.New("agent name is required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.agents[name]; exists {
+		return fmt.Errorf("%w: %s", ErrAgentAlreadyRegistered, name)
+	}
+	r.agents[name] = agent
+	return nil
+}
+
+// Get returns the agent registered under name.
+func (r *Registry) Get(name string) (Agent, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	agent, ok := r.agents[name]
+	return agent, ok
+}
+
+// Names returns the registered agent names in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.agents))
+	for name := range r.agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,84 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+func TestDefaultRegistryContainsBuiltins(t *testing.T) {
+	names := DefaultRegistry().Names()
+	want := []string{"continuity", "fact", "style", "timeline"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("expected %v, got %v", want, names)
+	}
+}
+
+func TestRegistryRejectsDuplicates(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(&stubAgent{name: "pacing"}); err != nil {
+		t.Fatalf("Register returned error: %v", err)
+	}
+	if err := r.Register(&stubAgent{name: "pacing"}); !errors.Is(err, ErrAgentAlreadyRegistered) {
+		t.Fatalf("expected ErrAgentAlreadyRegistered, got %v", err)
+	}
+}
+
+func TestServiceDispatchesToRegisteredAgent(t *testing.T) {
+	registry := NewRegistry()
+	agent := &stubAgent{
+		name:   "pacing",
+		result: Result{Summary: "Chapter 3 drags.", Issues: []models.Issue{{Severity: models.SeverityWarning, Category: "pacing", Message: "Slow middle section."}}},
+	}
+	if err := registry.Register(agent); err != nil {
+		t.Fatalf("Register returned error: %v", err)
+	}
+
+	store := newMockStore()
+	svc := NewService(store, t.TempDir(), WithRegistry(registry))
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "continuity"}); !errors.Is(err, ErrInvalidAgentType) {
+		t.Fatalf("expected unregistered built-in to be rejected, got %v", err)
+	}
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "pacing", FilesChanged: []string{"chapters/03.md"}}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+
+	claimed, err := svc.claimNextRun(ctx)
+	if err != nil {
+		t.Fatalf("claimNextRun returned error: %v", err)
+	}
+	if err := svc.executeRun(ctx, claimed); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+
+	if len(agent.inputs) != 1 || agent.inputs[0].Files[0] != "chapters/03.md" {
+		t.Fatalf("expected agent to receive the changed files, got %+v", agent.inputs)
+	}
+	results := store.runs[claimed.ID].Results
+	if results.Summary != "Chapter 3 drags." || len(results.Issues) != 1 || results.Issues[0].ID == "" {
+		t.Fatalf("unexpected results %+v", results)
+	}
+}
+
+type stubAgent struct {
+	name   string
+	result Result
+	err    error
+	inputs []Input
+}
+
+func (a *stubAgent) Name() string           { return a.name }
+func (a *stubAgent) DefaultTrigger() string { return "manual" }
+func (a *stubAgent) ContextRequirements() ContextRequirements {
+	return ContextRequirements{ChangedFiles: true}
+}
+
+func (a *stubAgent) Run(_ context.Context, input Input) (Result, error) {
+	a.inputs = append(a.inputs, input)
+	return a.result, a.err
+}
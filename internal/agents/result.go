@@ -0,0 +1,128 @@
+package agents
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// buildRunResult converts an agent Result into the persisted schema, assigning issue IDs
+// and filling single-line ranges, then validates it.
+func buildRunResult(run models.AgentRun, files []string, result Result) (models.RunResult, error) {
+	issues := make([]models.Issue, 0, len(result.Issues))
+	seen := make(map[string]int, len(result.Issues))
+	for _, issue := range result.Issues {
+		if issue.StartLine > 0 && issue.EndLine == 0 {
+			issue.EndLine = issue.StartLine
+		}
+		if issue.ID == "" {
+			issue.ID = issueID(run.AgentType, issue)
+		}
+		if n := seen[issue.ID]; n > 0 {
+			seen[issue.ID] = n + 1
+			issue.ID = issue.ID + "-" + strconv.Itoa(n+1)
+		} else {
+			seen[issue.ID] = 1
+		}
+		issues = append(issues, issue)
+	}
+
+	runResult := models.RunResult{
+		SchemaVersion: models.RunResultSchemaVersion,
+		Summary:       result.Summary,
+		Issues:        issues,
+		Metrics:       result.Metrics,
+		Stats: models.RunStats{
+			FilesAnalyzed: len(files),
+			TokensUsed:    result.Usage.TotalTokens,
+		},
+	}
+	if err := runResult.Validate(); err != nil {
+		return models.RunResult{}, err
+	}
+	return runResult, nil
+}
+
+// issueID derives a short deterministic ID from the issue's anchor and content.
+func issueID(agentType string, issue models.Issue) string {
+	key := strings.Join([]string{
+		issue.File,
+		strconv.Itoa(issue.StartLine),
+		strconv.Itoa(issue.EndLine),
+		issue.Category,
+		issue.Message,
+	}, "|")
+	sum := sha256.Sum256([]byte(key))
+	return agentType + "-" + hex.EncodeToString(sum[:6])
+}
+
+// modelResult is the JSON shape agents ask models to reply with.
+type modelResult struct {
+	Summary string         `json:"summary"`
+	Issues  []models.Issue `json:"issues"`
+}
+
+// parseModelResult decodes a model reply in the modelResult shape. Replies wrapped in a
+// Markdown code fence are accepted; anything else is reported as an error. Sloppy issues
+// are repaired where possible so one of them does not fail the whole run: ranges are
+// clamped and reordered, and issues without a message are dropped.
+func parseModelResult(content string) (modelResult, error) {
+	var parsed modelResult
+	if err := json.Unmarshal([]byte(stripCodeFence(content)), &parsed); err != nil {
+		return modelResult{}, fmt.Errorf("decode model result: %w", err)
+	}
+	issues := parsed.Issues[:0]
+	for _, issue := range parsed.Issues {
+		if strings.TrimSpace(issue.Message) == "" {
+			continue
+		}
+		issue.ID = ""
+		if !issue.Severity.Valid() {
+			issue.Severity = models.SeverityWarning
+		}
+		if issue.Category == "" {
+			issue.Category = "general"
+		}
+		issue.StartLine, issue.EndLine = max(issue.StartLine, 0), max(issue.EndLine, 0)
+		issue.StartColumn, issue.EndColumn = max(issue.StartColumn, 0), max(issue.EndColumn, 0)
+		if issue.File == "" || issue.StartLine == 0 {
+			issue.StartLine, issue.EndLine, issue.StartColumn, issue.EndColumn = 0, 0, 0, 0
+		}
+		if issue.StartLine > 0 && issue.EndLine < issue.StartLine {
+			issue.EndLine = issue.StartLine
+		}
+		if issue.EndLine == issue.StartLine && issue.EndColumn != 0 && issue.EndColumn < issue.StartColumn {
+			issue.StartColumn, issue.EndColumn = issue.EndColumn, issue.StartColumn
+		}
+		if issue.Edit != nil {
+			if issue.StartLine == 0 {
+				issue.Edit = nil
+			} else {
+				// Pinned to the file when the run is recorded.
+				issue.Edit.Revision, issue.Edit.Diff = "", ""
+			}
+		}
+		if issue.Confidence < 0 || issue.Confidence > 1 {
+			issue.Confidence = 0
+		}
+		issues = append(issues, issue)
+	}
+	parsed.Issues = issues
+	return parsed, nil
+}
+
+// stripCodeFence removes a Markdown code fence wrapped around a model reply.
+func stripCodeFence(content string) string {
+	trimmed := strings.TrimSpace(content)
+	if strings.HasPrefix(trimmed, "```") {
+		trimmed = strings.TrimPrefix(trimmed, "```json")
+		trimmed = strings.TrimPrefix(trimmed, "```")
+		trimmed = strings.TrimSuffix(strings.TrimSpace(trimmed), "```")
+	}
+	return trimmed
+}
@@ -0,0 +1,150 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+func TestBuildRunResultAssignsStableIDs(t *testing.T) {
+	run := models.AgentRun{ID: 1, AgentType: "continuity"}
+	issue := models.Issue{
+		Severity:  models.SeverityError,
+		Category:  "character",
+		Message:   "Eye colour changed.",
+		File:      "chapters/05.md",
+		StartLine: 45,
+	}
+
+	first, err := buildRunResult(run, []string{"chapters/05.md"}, Result{Summary: "1 issue", Issues: []models.Issue{issue, issue}})
+	if err != nil {
+		t.Fatalf("buildRunResult returned error: %v", err)
+	}
+	second, err := buildRunResult(run, nil, Result{Issues: []models.Issue{issue}})
+	if err != nil {
+		t.Fatalf("buildRunResult returned error: %v", err)
+	}
+
+	if first.SchemaVersion != models.RunResultSchemaVersion || first.Stats.FilesAnalyzed != 1 {
+		t.Fatalf("unexpected result %+v", first)
+	}
+	if first.Issues[0].EndLine != 45 {
+		t.Fatalf("expected end line to default to start line, got %d", first.Issues[0].EndLine)
+	}
+	if first.Issues[0].ID != second.Issues[0].ID || !strings.HasPrefix(first.Issues[0].ID, "continuity-") {
+		t.Fatalf("expected deterministic ids, got %q and %q", first.Issues[0].ID, second.Issues[0].ID)
+	}
+	if first.Issues[1].ID != first.Issues[0].ID+"-2" {
+		t.Fatalf("expected duplicate issue to get a suffixed id, got %q", first.Issues[1].ID)
+	}
+}
+
+func TestBuildRunResultRejectsInvalidIssues(t *testing.T) {
+	_, err := buildRunResult(models.AgentRun{AgentType: "style"}, nil, Result{
+		Issues: []models.Issue{{Severity: "fatal", Category: "tense", Message: "Tense shift."}},
+	})
+	if !errors.Is(err, models.ErrInvalidRunResult) {
+		t.Fatalf("expected ErrInvalidRunResult, got %v", err)
+	}
+}
+
+func TestParseModelResultAcceptsFencedJSON(t *testing.T) {
+	parsed, err := parseModelResult("```json\n{\"summary\": \"One issue.\", \"issues\": [{\"id\": \"x\", \"severity\": \"critical\", \"message\": \"Tense shift.\", \"start_line\": 3}]}\n```")
+	if err != nil {
+		t.Fatalf("parseModelResult returned error: %v", err)
+	}
+
+	issue := parsed.Issues[0]
+	if parsed.Summary != "One issue." || issue.ID != "" || issue.Severity != models.SeverityWarning || issue.Category != "general" {
+		t.Fatalf("unexpected normalisation %+v", parsed)
+	}
+	if issue.StartLine != 0 {
+		t.Fatalf("expected unanchored line to be dropped, got %d", issue.StartLine)
+	}
+}
+
+func TestParseModelResultRepairsSloppyIssues(t *testing.T) {
+	tests := []struct {
+		name  string
+		issue string
+		want  models.Issue
+	}{
+		{
+			name:  "negative lines and columns",
+			issue: `{"message": "m", "file": "a.md", "start_line": 4, "end_line": -2, "start_column": -1, "end_column": 6}`,
+			want:  models.Issue{File: "a.md", StartLine: 4, EndLine: 4, EndColumn: 6},
+		},
+		{
+			name:  "negative start line",
+			issue: `{"message": "m", "file": "a.md", "start_line": -3, "end_line": 5}`,
+			want:  models.Issue{File: "a.md"},
+		},
+		{
+			name:  "range without start line",
+			issue: `{"message": "m", "file": "a.md", "start_line": 0, "end_line": 7, "start_column": 2, "end_column": 9}`,
+			want:  models.Issue{File: "a.md"},
+		},
+		{
+			name:  "inverted columns on one line",
+			issue: `{"message": "m", "file": "a.md", "start_line": 3, "end_line": 3, "start_column": 12, "end_column": 4}`,
+			want:  models.Issue{File: "a.md", StartLine: 3, EndLine: 3, StartColumn: 4, EndColumn: 12},
+		},
+		{
+			name:  "columns across lines",
+			issue: `{"message": "m", "file": "a.md", "start_line": 3, "end_line": 5, "start_column": 12, "end_column": 4}`,
+			want:  models.Issue{File: "a.md", StartLine: 3, EndLine: 5, StartColumn: 12, EndColumn: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseModelResult(`{"issues": [` + tt.issue + `]}`)
+			if err != nil {
+				t.Fatalf("parseModelResult returned error: %v", err)
+			}
+			got := parsed.Issues[0]
+			if got.File != tt.want.File || got.StartLine != tt.want.StartLine || got.EndLine != tt.want.EndLine ||
+				got.StartColumn != tt.want.StartColumn || got.EndColumn != tt.want.EndColumn {
+				t.Fatalf("expected range %+v, got %+v", tt.want, got)
+			}
+			if _, err := buildRunResult(models.AgentRun{AgentType: "style"}, nil, Result{Issues: parsed.Issues}); err != nil {
+				t.Fatalf("expected the repaired issue to validate, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseModelResultDropsIssuesWithoutMessage(t *testing.T) {
+	parsed, err := parseModelResult(`{"issues": [{"message": "  ", "file": "a.md", "start_line": 2}, {"message": "Kept."}]}`)
+	if err != nil {
+		t.Fatalf("parseModelResult returned error: %v", err)
+	}
+	if len(parsed.Issues) != 1 || parsed.Issues[0].Message != "Kept." {
+		t.Fatalf("expected the empty issue to be dropped, got %+v", parsed.Issues)
+	}
+}
+
+func TestExecuteRunFailsOnInvalidResult(t *testing.T) {
+	registry := NewRegistry()
+	_ = registry.Register(&stubAgent{
+		name:   "broken",
+		result: Result{Issues: []models.Issue{{Severity: models.SeverityWarning, Category: "x", Message: "y", StartLine: 4}}},
+	})
+	store := newMockStore()
+	svc := NewService(store, t.TempDir(), WithRegistry(registry))
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "broken"}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	claimed, _ := svc.claimNextRun(ctx)
+	if err := svc.executeRun(ctx, claimed); !errors.Is(err, models.ErrInvalidRunResult) {
+		t.Fatalf("expected ErrInvalidRunResult, got %v", err)
+	}
+	if store.runs[claimed.ID].Status != "failed" {
+		t.Fatalf("expected run to be marked failed, got %s", store.runs[claimed.ID].Status)
+	}
+}
@@ -0,0 +1,212 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// ErrRunNotDeadLettered is returned when requeueing a run that is not in the dead-letter state.
+var ErrRunNotDeadLettered = errors.New("run is not dead-lettered")
+
+// RetryPolicy controls how runs that fail on transient provider errors are retried.
+// A run is retried with exponential backoff until it has been attempted MaxAttempts
+// times, after which it is parked in the dead_letter state.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy rides out typical provider rate limits without holding a run for long.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   30 * time.Second,
+	MaxDelay:    15 * time.Minute,
+}
+
+// WithRetryPolicy overrides the retry policy for transient failures.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(s *Service) {
+		s.retry = policy
+	}
+}
+
+// backoff returns the delay before the next attempt once attempt attempts have failed.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return delay
+}
+
+// isTransient reports whether err is worth retrying: provider rate limits, provider-side
+// errors, timeouts and runs abandoned by a crashed worker. A joined error, such as one
+// from trying fallback models, is transient when any of its errors is.
+func isTransient(err error) bool {
+	for err != nil {
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			return slices.ContainsFunc(e.Unwrap(), isTransient)
+		case *ProviderError:
+			return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+		case net.Error:
+			if e.Timeout() {
+				return true
+			}
+		}
+		if err == context.DeadlineExceeded || err == ErrRunAbandoned {
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+// recordFailure schedules a retry for transient errors while attempts remain, dead-letters
+// the run once they are exhausted, and marks any other error as a plain failure. Runs that
+// are no longer running (cancelled elsewhere) are left as they are.
+func (s *Service) recordFailure(ctx context.Context, run models.AgentRun, failErr error) {
+	event := models.RunEvent{Type: models.RunEventFailed, Error: failErr.Error()}
+	var err error
+	switch {
+	case !isTransient(failErr):
+		err = s.store.MarkFailed(ctx, run.ID, failErr.Error(), s.now())
+	case run.Attempts < s.retry.MaxAttempts:
+		next := s.now().Add(s.retry.backoff(run.Attempts))
+		err = s.store.ScheduleRetry(ctx, run.ID, failErr.Error(), next)
+		event = models.RunEvent{Type: models.RunEventRetrying, Status: "queued", Error: failErr.Error(), NextAttemptAt: &next}
+	default:
+		message := fmt.Sprintf("gave up after %d attempts: %v", run.Attempts, failErr)
+		err = s.store.MarkDeadLetter(ctx, run.ID, message, s.now())
+		event = models.RunEvent{Type: models.RunEventDeadLetter, Error: message}
+	}
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		err = s.store.MarkFailed(ctx, run.ID, failErr.Error(), s.now())
+		event = models.RunEvent{Type: models.RunEventFailed, Error: failErr.Error()}
+	}
+	if errors.Is(err, models.ErrNotFound) {
+		return
+	}
+	if event.Terminal() {
+		s.settleCredits(ctx, run.ID, false)
+	}
+	s.publish(ctx, run, event)
+	if event.Terminal() {
+		s.cancelDependents(ctx, run)
+	}
+}
+
+// ListDeadLetterRuns returns a project's runs that exhausted their retries.
+func (s *Service) ListDeadLetterRuns(ctx context.Context, projectID int64) ([]models.AgentRun, error) {
+	return s.store.ListDeadLetterRuns(ctx, projectID)
+}
+
+// RequeueRun puts a dead-lettered run back on the queue with a fresh attempt budget.
+// Pipeline runs that were cancelled because it dead-lettered are requeued with it, so
+// the pipeline can still complete.
+func (s *Service) RequeueRun(ctx context.Context, projectID, runID int64) (models.AgentRun, error) {
+	run, err := s.store.GetRun(ctx, runID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.AgentRun{}, ErrRunNotFound
+		}
+		return models.AgentRun{}, err
+	}
+	if run.ProjectID != projectID {
+		return models.AgentRun{}, ErrRunNotFound
+	}
+	if run.Status != "dead_letter" {
+		return models.AgentRun{}, ErrRunNotDeadLettered
+	}
+	dependents, err := s.cancelledDependents(ctx, run)
+	if err != nil {
+		return models.AgentRun{}, err
+	}
+
+	// Reserve for the whole chain up front so a short balance leaves it untouched.
+	reserved := make([]int, 0, len(dependents)+1)
+	for _, r := range append([]models.AgentRun{run}, dependents...) {
+		credits, err := s.reserveForRequeue(ctx, r)
+		if err != nil {
+			s.releaseReserved(ctx, projectID, reserved...)
+			return models.AgentRun{}, err
+		}
+		reserved = append(reserved, credits)
+	}
+
+	requeued, err := s.store.RequeueRun(ctx, runID, reserved[0])
+	if err != nil {
+		s.releaseReserved(ctx, projectID, reserved...)
+		if errors.Is(err, models.ErrNotFound) {
+			return models.AgentRun{}, ErrRunNotDeadLettered
+		}
+		return models.AgentRun{}, fmt.Errorf("requeue run: %w", err)
+	}
+	s.publish(ctx, requeued, models.RunEvent{Type: models.RunEventQueued})
+
+	message := upstreamMessage(run)
+	for i, dep := range dependents {
+		revived, err := s.store.RequeueDependent(ctx, dep.ID, message, reserved[i+1])
+		if err != nil {
+			s.releaseReserved(ctx, projectID, reserved[i+1])
+			if !errors.Is(err, models.ErrNotFound) {
+				log.Printf("agent pipeline %s: %v", run.PipelineID, err)
+			}
+			continue
+		}
+		s.publish(ctx, revived, models.RunEvent{Type: models.RunEventQueued})
+	}
+	s.nudgeWorker()
+	return requeued, nil
+}
+
+// releaseReserved returns credits reserved for runs that were not requeued after all.
+func (s *Service) releaseReserved(ctx context.Context, projectID int64, amounts ...int) {
+	if s.credits == nil {
+		return
+	}
+	for _, amount := range amounts {
+		_ = s.credits.Release(ctx, projectID, amount)
+	}
+}
+
+// reserveForRequeue holds fresh credits for a dead-lettered run; its earlier reservation
+// was refunded when it was dead-lettered.
+func (s *Service) reserveForRequeue(ctx context.Context, run models.AgentRun) (int, error) {
+	if s.credits == nil {
+		return 0, nil
+	}
+	agent, ok := s.registry.Get(run.AgentType)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidAgentType, run.AgentType)
+	}
+	root, err := s.workspaceRoot(ctx, run.ProjectID)
+	if err != nil {
+		return 0, fmt.Errorf("resolve workspace: %w", err)
+	}
+	agentCfg, err := s.agentConfig(root, agent)
+	if err != nil {
+		return 0, err
+	}
+	est, err := s.estimate(root, run, agent, agentCfg)
+	if err != nil {
+		return 0, fmt.Errorf("estimate run: %w", err)
+	}
+	reserved := est.Credits
+	if err := s.credits.Reserve(ctx, run.ProjectID, reserved); err != nil {
+		return 0, fmt.Errorf("reserve %d credits: %w", reserved, err)
+	}
+	return reserved, nil
+}
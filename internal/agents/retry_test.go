@@ -0,0 +1,102 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, expected := range want {
+		if got := policy.backoff(i + 1); got != expected {
+			t.Fatalf("attempt %d: expected %v, got %v", i+1, expected, got)
+		}
+	}
+}
+
+func TestExecuteRunRetriesTransientErrorsThenDeadLetters(t *testing.T) {
+	store := newMockStore()
+	registry := NewRegistry()
+	_ = registry.Register(&promptAgent{name: "notes", trigger: "manual", instructions: "Summarise the changes."})
+	provider := failingProvider{failModels: map[string]bool{"test/model": true}, fake: NewFakeProvider()}
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	svc := NewService(store, t.TempDir(),
+		WithRegistry(registry),
+		WithProvider(provider, "test/model"),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Minute}),
+	)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "notes"}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	claimed, _ := svc.claimNextRun(ctx)
+	if err := svc.executeRun(ctx, claimed); err == nil {
+		t.Fatalf("expected executeRun to fail")
+	}
+
+	run := store.runs[claimed.ID]
+	if run.Status != "queued" || run.NextAttemptAt == nil || !run.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected a retry scheduled a minute out, got %+v", run)
+	}
+	if _, err := svc.claimNextRun(ctx); err == nil {
+		t.Fatalf("expected the retry to wait for its backoff")
+	}
+
+	now = now.Add(time.Minute)
+	claimed, err := svc.claimNextRun(ctx)
+	if err != nil || claimed.Attempts != 2 {
+		t.Fatalf("expected the second attempt to be claimed, got %+v, %v", claimed, err)
+	}
+	_ = svc.executeRun(ctx, claimed)
+
+	dead, _ := svc.ListDeadLetterRuns(ctx, 1)
+	if len(dead) != 1 || dead[0].Status != "dead_letter" {
+		t.Fatalf("expected the run to be dead-lettered, got %+v", store.runs[claimed.ID])
+	}
+
+	requeued, err := svc.RequeueRun(ctx, 1, claimed.ID)
+	if err != nil || requeued.Status != "queued" || requeued.Attempts != 0 {
+		t.Fatalf("expected the run back on the queue, got %+v, %v", requeued, err)
+	}
+	if _, err := svc.RequeueRun(ctx, 1, claimed.ID); !errors.Is(err, ErrRunNotDeadLettered) {
+		t.Fatalf("expected ErrRunNotDeadLettered, got %v", err)
+	}
+}
+
+func TestExecuteRunFailsPermanentErrorsImmediately(t *testing.T) {
+	store := newMockStore()
+	registry := NewRegistry()
+	_ = registry.Register(&promptAgent{name: "notes", trigger: "manual", instructions: "Summarise the changes."})
+	svc := NewService(store, t.TempDir(), WithRegistry(registry), WithProvider(NewFakeProvider(), "test/model"))
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "notes"}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	claimed, _ := svc.claimNextRun(ctx)
+	_ = svc.executeRun(ctx, claimed)
+
+	if run := store.runs[claimed.ID]; run.Status != "failed" {
+		t.Fatalf("expected a non-transient error to fail the run, got %+v", run)
+	}
+}
+
+func TestIsTransientSeesEveryFallbackAttempt(t *testing.T) {
+	limited := fmt.Errorf("model a: %w", &ProviderError{StatusCode: 429})
+	rejected := fmt.Errorf("model b: %w", &ProviderError{StatusCode: 400})
+	if !isTransient(fmt.Errorf("run agent: %w", errors.Join(rejected, limited))) {
+		t.Fatal("expected a rate-limited attempt to make the joined error transient")
+	}
+	if isTransient(errors.Join(rejected, fmt.Errorf("model c: %w", &ProviderError{StatusCode: 404}))) {
+		t.Fatal("expected request errors alone not to be transient")
+	}
+	if !isTransient(ErrRunAbandoned) {
+		t.Fatal("expected abandoned runs to be retried")
+	}
+}
@@ -7,19 +7,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/yourusername/draft-forge/internal/manuscript"
 	"github.com/yourusername/draft-forge/internal/models"
 )
 
-// Supported agent types for the PoC.
-var validAgentTypes = map[string]bool{
-	"continuity": true,
-	"style":      true,
-	"timeline":   true,
-	"fact":       true,
-}
-
 // Supported triggers.
 var validTriggers = map[string]bool{
 	"manual":    true,
@@ -33,6 +29,8 @@ var (
 	ErrInvalidTrigger   = errors.New("invalid trigger")
 	ErrProjectNotFound  = errors.New("project not found")
 	ErrRunNotFound      = errors.New("run not found")
+	// ErrRunAbandoned is recorded on a running run whose worker stopped sending heartbeats.
+	ErrRunAbandoned = errors.New("run abandoned: its worker stopped responding")
 )
 
 type RunRequest struct {
@@ -45,72 +43,233 @@ type RunRequest struct {
 // RunStore defines persistence needs for agent runs and project existence.
 type RunStore interface {
 	InsertRun(ctx context.Context, run models.AgentRun) (models.AgentRun, error)
-	MarkRunning(ctx context.Context, id int64, startedAt time.Time) error
+	InsertPipeline(ctx context.Context, runs []models.AgentRun, dependsOn [][]int) ([]models.AgentRun, error)
+	ClaimNextRun(ctx context.Context, startedAt time.Time) (models.AgentRun, error)
+	Heartbeat(ctx context.Context, id int64, at time.Time) error
+	ClaimStaleRuns(ctx context.Context, staleBefore, now time.Time) ([]models.AgentRun, error)
+	UpdateProgress(ctx context.Context, id int64, progress models.RunProgress) error
+	SetPromptHash(ctx context.Context, id int64, hash string) error
+	SetEditorialRound(ctx context.Context, id int64, round string) error
 	MarkCompleted(ctx context.Context, id int64, results json.RawMessage, completedAt time.Time) error
 	MarkFailed(ctx context.Context, id int64, message string, completedAt time.Time) error
+	ScheduleRetry(ctx context.Context, id int64, message string, nextAttemptAt time.Time) error
+	MarkDeadLetter(ctx context.Context, id int64, message string, completedAt time.Time) error
+	MarkCancelled(ctx context.Context, id int64, completedAt time.Time) error
+	MarkTimedOut(ctx context.Context, id int64, message string, completedAt time.Time) error
+	CancelDependents(ctx context.Context, runID int64, message string, completedAt time.Time) ([]models.AgentRun, error)
+	RequeueRun(ctx context.Context, id int64, creditsReserved int) (models.AgentRun, error)
+	RequeueDependent(ctx context.Context, id int64, message string, creditsReserved int) (models.AgentRun, error)
+	ListDeadLetterRuns(ctx context.Context, projectID int64) ([]models.AgentRun, error)
 	GetRun(ctx context.Context, id int64) (models.AgentRun, error)
 	ListRuns(ctx context.Context, projectID int64) ([]models.AgentRun, error)
+	ListPipelineRuns(ctx context.Context, pipelineID string) ([]models.AgentRun, error)
 	ProjectExists(ctx context.Context, projectID int64) (bool, error)
 }
 
 type Service struct {
 	store       RunStore
 	artifactDir string
+	registry    *Registry
+	workspaces  WorkspaceResolver
+	builder     *manuscript.Builder
+	chunking    manuscript.ChunkOptions
+	provider    Provider
+	model       string
+	retry       RetryPolicy
+	events      EventPublisher
+	usage       UsageStore
+	credits     CreditLedger
+	prices      PriceTable
+	cache       ResultCache
+	suggestions SuggestionStore
+	maxRuntime  time.Duration
 	now         func() time.Time
+	// wake nudges an idle Worker when a run is queued so it does not wait for the next poll.
+	wake chan struct{}
+
+	activeMu sync.Mutex
+	// active holds the cancel functions of runs executing in this process.
+	active map[int64]context.CancelCauseFunc
+}
+
+// Option configures optional Service dependencies.
+type Option func(*Service)
+
+// WithRegistry replaces the built-in agent registry.
+func WithRegistry(registry *Registry) Option {
+	return func(s *Service) {
+		s.registry = registry
+	}
+}
+
+// WithWorkspaces lets runs read the project's working tree. A nil builder uses the default budget.
+func WithWorkspaces(resolver WorkspaceResolver, builder *manuscript.Builder) Option {
+	return func(s *Service) {
+		s.workspaces = resolver
+		if builder != nil {
+			s.builder = builder
+		}
+	}
 }
 
-func NewService(store RunStore, artifactDir string) *Service {
-	return &Service{
+// WithChunking sets how changed files are split for map/reduce processing.
+func WithChunking(opts manuscript.ChunkOptions) Option {
+	return func(s *Service) {
+		s.chunking = opts
+	}
+}
+
+// WithProvider sets the model provider and the default model used for agent runs.
+func WithProvider(provider Provider, model string) Option {
+	return func(s *Service) {
+		s.provider = provider
+		if model != "" {
+			s.model = model
+		}
+	}
+}
+
+func NewService(store RunStore, artifactDir string, opts ...Option) *Service {
+	s := &Service{
 		store:       store,
 		artifactDir: artifactDir,
-		now:         time.Now,
+		registry:    DefaultRegistry(),
+		builder:     manuscript.NewBuilder(manuscript.DefaultBudget),
+		chunking: manuscript.ChunkOptions{
+			MaxTokens:     manuscript.DefaultChunkTokens,
+			OverlapTokens: manuscript.DefaultOverlapTokens,
+		},
+		model:      DefaultModel,
+		retry:      DefaultRetryPolicy,
+		maxRuntime: DefaultMaxRuntime,
+		now:        time.Now,
+		wake:       make(chan struct{}, 1),
+		active:     make(map[int64]context.CancelCauseFunc),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-// QueueRun validates input, persists a queued run, executes a stub agent, and returns the completed run.
-// For PoC the execution happens synchronously and writes a JSON artifact to the repo path.
-func (s *Service) QueueRun(ctx context.Context, req RunRequest) (models.AgentRun, error) {
-	if !validAgentTypes[req.AgentType] {
-		return models.AgentRun{}, ErrInvalidAgentType
+// runPlan is a validated run request, ready to estimate or queue.
+type runPlan struct {
+	run      models.AgentRun
+	agent    Agent
+	agentCfg AgentConfig
+	root     string
+}
+
+// planRun validates a run request: the agent, trigger and project must exist and the
+// project must not have disabled the agent.
+func (s *Service) planRun(ctx context.Context, req RunRequest) (runPlan, error) {
+	agent, ok := s.registry.Get(req.AgentType)
+	if !ok {
+		return runPlan{}, ErrInvalidAgentType
 	}
 	trigger := req.Trigger
 	if trigger == "" {
 		trigger = "manual"
 	}
 	if !validTriggers[trigger] {
-		return models.AgentRun{}, ErrInvalidTrigger
+		return runPlan{}, ErrInvalidTrigger
 	}
 
 	exists, err := s.store.ProjectExists(ctx, req.ProjectID)
 	if err != nil {
-		return models.AgentRun{}, fmt.Errorf("check project: %w", err)
+		return runPlan{}, fmt.Errorf("check project: %w", err)
 	}
 	if !exists {
-		return models.AgentRun{}, ErrProjectNotFound
+		return runPlan{}, ErrProjectNotFound
+	}
+
+	root, err := s.workspaceRoot(ctx, req.ProjectID)
+	if err != nil {
+		return runPlan{}, fmt.Errorf("resolve workspace: %w", err)
+	}
+	agentCfg, err := s.agentConfig(root, agent)
+	if err != nil {
+		return runPlan{}, err
+	}
+	if !agentCfg.IsEnabled() {
+		return runPlan{}, fmt.Errorf("%w: %s", ErrAgentDisabled, req.AgentType)
 	}
 
-	run := models.AgentRun{
-		ProjectID: req.ProjectID,
-		AgentType: req.AgentType,
-		Trigger:   trigger,
-		Status:    "queued",
-		CreatedAt: s.now(),
+	// Scheduled runs without a file list review the whole manuscript.
+	files := req.FilesChanged
+	if trigger == "scheduled" && len(files) == 0 && root != "" {
+		if files, err = manuscript.ListChapters(root); err != nil {
+			return runPlan{}, fmt.Errorf("list chapters: %w", err)
+		}
+	}
+
+	return runPlan{
+		run: models.AgentRun{
+			ProjectID:    req.ProjectID,
+			AgentType:    req.AgentType,
+			Trigger:      trigger,
+			Status:       "queued",
+			FilesChanged: files,
+			CreatedAt:    s.now(),
+		},
+		agent:    agent,
+		agentCfg: agentCfg,
+		root:     root,
+	}, nil
+}
+
+// QueueRun validates input, persists a queued run, and returns it immediately.
+// Execution happens in the background once a Worker claims the run.
+func (s *Service) QueueRun(ctx context.Context, req RunRequest) (models.AgentRun, error) {
+	plan, err := s.planRun(ctx, req)
+	if err != nil {
+		return models.AgentRun{}, err
+	}
+	run, err := s.reserveCredits(ctx, plan)
+	if err != nil {
+		return models.AgentRun{}, err
 	}
 
-	run, err = s.store.InsertRun(ctx, run)
+	inserted, err := s.store.InsertRun(ctx, run)
 	if err != nil {
+		s.releaseCredits(ctx, run)
 		return models.AgentRun{}, fmt.Errorf("insert run: %w", err)
 	}
+	run = inserted
+	s.publish(ctx, run, models.RunEvent{Type: models.RunEventQueued})
+	s.nudgeWorker()
 
-	if err := s.executeRun(ctx, run, req.FilesChanged); err != nil {
-		return models.AgentRun{}, err
+	return run, nil
+}
+
+// nudgeWorker wakes an idle Worker without blocking.
+func (s *Service) nudgeWorker() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
 	}
+}
+
+// HasAgent reports whether an agent is registered under name.
+func (s *Service) HasAgent(name string) bool {
+	_, ok := s.registry.Get(name)
+	return ok
+}
 
-	finalRun, err := s.store.GetRun(ctx, run.ID)
+// HasChapters reports whether the project has a workspace with at least one chapter.
+func (s *Service) HasChapters(ctx context.Context, projectID int64) (bool, error) {
+	root, err := s.workspaceRoot(ctx, projectID)
+	if err != nil {
+		return false, fmt.Errorf("resolve workspace: %w", err)
+	}
+	if root == "" {
+		return false, nil
+	}
+	chapters, err := manuscript.ListChapters(root)
 	if err != nil {
-		return models.AgentRun{}, fmt.Errorf("fetch run: %w", err)
+		return false, fmt.Errorf("list chapters: %w", err)
 	}
-	return finalRun, nil
+	return len(chapters) > 0, nil
 }
 
 // GetRun returns a run by ID.
@@ -122,46 +281,338 @@ func (s *Service) ListRuns(ctx context.Context, projectID int64) ([]models.Agent
 	return s.store.ListRuns(ctx, projectID)
 }
 
-func (s *Service) executeRun(ctx context.Context, run models.AgentRun, files []string) error {
-	started := s.now()
-	if err := s.store.MarkRunning(ctx, run.ID, started); err != nil {
-		return fmt.Errorf("mark running: %w", err)
+// claimNextRun marks the oldest queued run that is due as running. It returns models.ErrNotFound when the queue is empty.
+func (s *Service) claimNextRun(ctx context.Context) (models.AgentRun, error) {
+	run, err := s.store.ClaimNextRun(ctx, s.now())
+	if err != nil {
+		return models.AgentRun{}, err
 	}
+	s.publish(ctx, run, models.RunEvent{Type: models.RunEventRunning})
+	return run, nil
+}
 
-	resultsPayload := map[string]any{
-		"summary": fmt.Sprintf("%s agent completed", run.AgentType),
-		"issues": []map[string]any{
-			{
-				"severity": "info",
-				"message":  "PoC agent run completed successfully.",
-			},
-		},
-		"stats": map[string]any{
-			"files_analyzed": len(files),
-			"tokens_used":    0,
-		},
+// heartbeat tells other instances that the run is still being executed here.
+func (s *Service) heartbeat(ctx context.Context, runID int64) error {
+	return s.store.Heartbeat(ctx, runID, s.now())
+}
+
+// reclaimStaleRuns records runs left running by a worker that has sent no heartbeat for
+// staleAfter as transient failures, so they are retried (or dead-lettered once out of
+// attempts) instead of staying running forever after a crash.
+func (s *Service) reclaimStaleRuns(ctx context.Context, staleAfter time.Duration) error {
+	now := s.now()
+	runs, err := s.store.ClaimStaleRuns(ctx, now.Add(-staleAfter), now)
+	if err != nil {
+		return err
+	}
+	for _, run := range runs {
+		s.recordFailure(ctx, run, ErrRunAbandoned)
+	}
+	return nil
+}
+
+// executeRun runs the agent for a claimed run and records the outcome.
+func (s *Service) executeRun(ctx context.Context, run models.AgentRun) error {
+	agent, ok := s.registry.Get(run.AgentType)
+	if !ok {
+		failErr := fmt.Errorf("%w: %s", ErrInvalidAgentType, run.AgentType)
+		s.recordFailure(ctx, run, failErr)
+		return failErr
+	}
+
+	root, err := s.workspaceRoot(ctx, run.ProjectID)
+	if err != nil {
+		failErr := fmt.Errorf("resolve workspace: %w", err)
+		s.recordFailure(ctx, run, failErr)
+		return failErr
 	}
 
-	resultBytes, err := json.Marshal(resultsPayload)
+	a, err := s.analyse(ctx, root, run, agent, func(input Input) error {
+		return s.recordInputs(ctx, &run, input)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(a.stopped, ErrRunCancelled):
+			// CancelRun has already recorded the cancellation.
+		case errors.Is(a.stopped, ErrRunTimedOut):
+			if markErr := s.store.MarkTimedOut(ctx, run.ID, err.Error(), s.now()); errors.Is(markErr, models.ErrNotFound) {
+				return fmt.Errorf("%w: %v", ErrRunNotRunning, err)
+			}
+			s.settleCredits(ctx, run.ID, false)
+			s.publish(ctx, run, models.RunEvent{Type: models.RunEventTimedOut, Error: err.Error()})
+			s.cancelDependents(ctx, run)
+		default:
+			s.recordFailure(ctx, run, err)
+		}
+		return err
+	}
+
+	runResult := a.runResult
+	pending := prepareEdits(run, &runResult, a.input.Context.Changed)
+
+	runResult.Artifacts, err = s.writeRunArtifacts(run, a.result.Artifacts)
+	if err != nil {
+		failErr := fmt.Errorf("write artifacts: %w", err)
+		s.recordFailure(ctx, run, failErr)
+		return failErr
+	}
+
+	resultBytes, err := json.Marshal(runResult)
 	if err != nil {
 		failErr := fmt.Errorf("marshal results: %w", err)
-		_ = s.store.MarkFailed(ctx, run.ID, failErr.Error(), s.now())
+		s.recordFailure(ctx, run, failErr)
 		return failErr
 	}
 
 	if err := s.writeArtifact(run, resultBytes); err != nil {
 		failErr := fmt.Errorf("write artifact: %w", err)
-		_ = s.store.MarkFailed(ctx, run.ID, failErr.Error(), s.now())
+		s.recordFailure(ctx, run, failErr)
 		return failErr
 	}
 
 	if err := s.store.MarkCompleted(ctx, run.ID, resultBytes, s.now()); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			// Cancelled by another instance; CancelRun has settled and announced it.
+			return ErrRunNotRunning
+		}
 		return fmt.Errorf("mark completed: %w", err)
 	}
+	s.settleCredits(ctx, run.ID, true)
+	s.recordSuggestions(ctx, run, pending)
+	s.publish(ctx, run, models.RunEvent{Type: models.RunEventCompleted})
+	if run.PipelineID != "" {
+		// Later stages may now be claimable.
+		s.nudgeWorker()
+	}
+
+	if c, ok := a.result.Data.(committer); ok {
+		if err := c.commit(ctx); err != nil {
+			return fmt.Errorf("save %s state: %w", run.AgentType, err)
+		}
+	}
+
+	return nil
+}
+
+// analysis is the outcome of analyse, before anything is persisted.
+type analysis struct {
+	input  Input
+	result Result
+	// runResult is result validated into the persisted schema, with run stats.
+	runResult models.RunResult
+	// stopped is why the agent's context ended early (ErrRunCancelled or ErrRunTimedOut),
+	// or nil.
+	stopped error
+}
+
+// analyse is the part of a run shared by executeRun and Analyze: it applies agents.yaml,
+// the prompt template and EDITORIAL.md, builds the context, map/reduces the changed files
+// under the agent's max runtime and validates the result. prepared, when set, is called
+// with the assembled input before the agent runs; an error from it aborts the run.
+func (s *Service) analyse(ctx context.Context, root string, run models.AgentRun, agent Agent, prepared func(Input) error) (analysis, error) {
+	var a analysis
+	agentCfg, err := s.agentConfig(root, agent)
+	if err != nil {
+		return a, fmt.Errorf("load agent config: %w", err)
+	}
+	if a.input, err = s.agentInput(root, run, agentCfg); err != nil {
+		return a, fmt.Errorf("load prompt: %w", err)
+	}
+	if a.input.Editorial, err = loadEditorial(root); err != nil {
+		return a, fmt.Errorf("load editorial focus: %w", err)
+	}
+	if a.input.Context, err = s.buildContext(root, run, agent.ContextRequirements()); err != nil {
+		return a, fmt.Errorf("build context: %w", err)
+	}
+	if a.input.Upstream, err = s.upstreamResults(ctx, run); err != nil {
+		return a, fmt.Errorf("load upstream results: %w", err)
+	}
+	if prepared != nil {
+		if err := prepared(a.input); err != nil {
+			return a, err
+		}
+	}
+
+	runCtx, finish := s.startRun(ctx, run.ID, s.runtimeLimit(agentCfg))
+	result, stats, err := s.mapReduce(runCtx, agent, a.input)
+	stopped := context.Cause(runCtx)
+	finish()
+	if err != nil {
+		a.stopped = stopped
+		return a, fmt.Errorf("run agent: %w", err)
+	}
+
+	applyEditorialFocus(a.input.Editorial, &result)
+	a.result = result
+	if a.runResult, err = buildRunResult(run, run.FilesChanged, result); err != nil {
+		return a, fmt.Errorf("validate results: %w", err)
+	}
+	a.runResult.Stats.ChunksAnalyzed = stats.ChunksAnalyzed
+	a.runResult.Stats.CacheHits = stats.CacheHits
+	return a, nil
+}
 
+// recordInputs stores the prompt hash and editorial round a run was given, and sets them
+// on run.
+func (s *Service) recordInputs(ctx context.Context, run *models.AgentRun, input Input) error {
+	if input.Prompt != nil {
+		if err := s.store.SetPromptHash(ctx, run.ID, input.Prompt.Hash); err != nil {
+			return fmt.Errorf("record prompt: %w", err)
+		}
+		run.PromptHash = input.Prompt.Hash
+	}
+	if input.Editorial.Round != "" {
+		if err := s.store.SetEditorialRound(ctx, run.ID, input.Editorial.Round); err != nil {
+			return fmt.Errorf("record editorial round: %w", err)
+		}
+		run.EditorialRound = input.Editorial.Round
+	}
 	return nil
 }
 
+// mapReduce runs the agent once per chunk of the changed files, recording progress on the
+// run after each chunk, then reduces the per-chunk results into one. Runs without changed
+// content (or without a workspace) call the agent once with a nil Chunk. Chunks of
+// Cacheable agents are served from the ResultCache when their content is unchanged.
+func (s *Service) mapReduce(ctx context.Context, agent Agent, input Input) (Result, models.RunStats, error) {
+	var stats models.RunStats
+	chunks := manuscript.ChunkDocuments(input.Context.Changed, s.chunking)
+	if len(chunks) == 0 {
+		result, err := agent.Run(ctx, input)
+		return result, stats, err
+	}
+
+	progress := models.RunProgress{ChunksTotal: len(chunks)}
+	results := make([]Result, 0, len(chunks))
+	for i := range chunks {
+		chunk := chunks[i]
+		progress.CurrentChunk = chunk.Label()
+		if err := s.updateProgress(ctx, input.Run.ID, progress); err != nil {
+			return Result{}, stats, err
+		}
+		s.publishProgress(ctx, input.Run, progress, nil)
+
+		chunkInput := input
+		chunkInput.Chunk = &chunk
+		key, cacheable := s.cacheKey(agent, chunkInput)
+		result, hit := Result{}, false
+		if cacheable {
+			result, hit = s.loadCached(ctx, agent, key)
+		}
+		if hit {
+			stats.CacheHits++
+		} else {
+			var err error
+			result, err = agent.Run(ctx, chunkInput)
+			if err != nil {
+				return Result{}, stats, fmt.Errorf("chunk %s: %w", chunk.Label(), err)
+			}
+			if cacheable {
+				s.storeCached(ctx, key, result)
+			}
+		}
+		results = append(results, result)
+		progress.ChunksCompleted++
+		s.publishProgress(ctx, input.Run, progress, result.Issues)
+	}
+	stats.ChunksAnalyzed = len(chunks)
+
+	progress.CurrentChunk = ""
+	if err := s.updateProgress(ctx, input.Run.ID, progress); err != nil {
+		return Result{}, stats, err
+	}
+
+	if reducer, ok := agent.(Reducer); ok {
+		result, err := reducer.Reduce(ctx, input, results)
+		if err != nil {
+			return Result{}, stats, fmt.Errorf("reduce: %w", err)
+		}
+		return result, stats, nil
+	}
+	return mergeResults(chunks, results), stats, nil
+}
+
+// updateProgress records chunk progress on the run. Services without a RunStore (see
+// Analyze) have no run to update.
+func (s *Service) updateProgress(ctx context.Context, id int64, progress models.RunProgress) error {
+	if s.store == nil {
+		return nil
+	}
+	if err := s.store.UpdateProgress(ctx, id, progress); err != nil {
+		return fmt.Errorf("update progress: %w", err)
+	}
+	return nil
+}
+
+// workspaceRoot returns the project's working tree, or "" when no resolver is configured.
+func (s *Service) workspaceRoot(ctx context.Context, projectID int64) (string, error) {
+	if s.workspaces == nil {
+		return "", nil
+	}
+	return s.workspaces.WorkspacePath(ctx, projectID)
+}
+
+// agentConfig returns the agent's settings from the project's agents.yaml.
+func (s *Service) agentConfig(root string, agent Agent) (AgentConfig, error) {
+	if root == "" {
+		return AgentConfig{}, nil
+	}
+	cfg, err := LoadProjectConfig(root)
+	if err != nil {
+		return AgentConfig{}, err
+	}
+	return cfg.Agent(agent.Name()), nil
+}
+
+// agentInput applies the agent's agents.yaml settings: model selection, fallbacks,
+// sampling limits and the prompt template. Provider calls are metered when a UsageStore
+// is configured.
+func (s *Service) agentInput(root string, run models.AgentRun, agentCfg AgentConfig) (Input, error) {
+	input := Input{
+		Run:      run,
+		Files:    run.FilesChanged,
+		Provider: s.provider,
+		Model:    s.model,
+	}
+	if agentCfg.Model != "" {
+		input.Model = agentCfg.Model
+	}
+	var err error
+	if input.Prompt, err = loadPrompt(root, run.AgentType, agentCfg); err != nil {
+		return Input{}, err
+	}
+	if s.provider != nil {
+		var provider Provider = s.provider
+		if s.usage != nil {
+			provider = meteredProvider{provider: s.provider, store: s.usage, prices: s.prices, run: run}
+		}
+		input.Provider = routedProvider{
+			provider:    provider,
+			fallbacks:   agentCfg.FallbackModels,
+			temperature: agentCfg.Temperature,
+			maxTokens:   agentCfg.MaxTokens,
+		}
+	}
+	return input, nil
+}
+
+// buildContext loads the documents an agent asked for from the project's working tree.
+func (s *Service) buildContext(root string, run models.AgentRun, reqs ContextRequirements) (manuscript.Context, error) {
+	if root == "" {
+		return manuscript.Context{}, nil
+	}
+
+	req := manuscript.Request{
+		StoryBible: reqs.StoryBible,
+		Editorial:  reqs.Editorial,
+		Sources:    reqs.Sources,
+	}
+	if reqs.ChangedFiles {
+		req.ChangedFiles = run.FilesChanged
+	}
+	return s.builder.Build(root, req)
+}
+
 func (s *Service) writeArtifact(run models.AgentRun, results []byte) error {
 	if s.artifactDir == "" {
 		return errors.New("artifact directory not configured")
@@ -194,3 +645,38 @@ func (s *Service) writeArtifact(run models.AgentRun, results []byte) error {
 
 	return nil
 }
+
+// writeRunArtifacts writes an agent's extra files to <artifactDir>/run-<id>/ and returns
+// their paths relative to the artifact directory.
+func (s *Service) writeRunArtifacts(run models.AgentRun, artifacts map[string][]byte) ([]string, error) {
+	if len(artifacts) == 0 {
+		return nil, nil
+	}
+	if s.artifactDir == "" {
+		return nil, errors.New("artifact directory not configured")
+	}
+
+	dirName := fmt.Sprintf("run-%d", run.ID)
+	if err := os.MkdirAll(filepath.Join(s.artifactDir, dirName), 0o755); err != nil {
+		return nil, fmt.Errorf("create run artifact dir: %w", err)
+	}
+
+	names := make([]string, 0, len(artifacts))
+	for name := range artifacts {
+		if name == "" || filepath.Base(name) != name || strings.HasPrefix(name, ".") {
+			return nil, fmt.Errorf("invalid artifact name %q", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		rel := filepath.Join(dirName, name)
+		if err := os.WriteFile(filepath.Join(s.artifactDir, rel), artifacts[name], 0o644); err != nil {
+			return nil, fmt.Errorf("write %s: %w", name, err)
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	return paths, nil
+}
@@ -5,29 +5,75 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/yourusername/draft-forge/internal/models"
 )
 
-func TestQueueRunWritesArtifactAndCompletes(t *testing.T) {
+func TestQueueRunReturnsQueuedRun(t *testing.T) {
+	store := newMockStore()
+	svc := NewService(store, t.TempDir())
+
+	run, err := svc.QueueRun(context.Background(), RunRequest{
+		ProjectID:    1,
+		AgentType:    "style",
+		FilesChanged: []string{"chapters/01.md"},
+	})
+	if err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+
+	if run.Status != "queued" || run.Trigger != "manual" {
+		t.Fatalf("expected queued manual run, got %+v", run)
+	}
+	if run.StartedAt != nil || run.Results != nil {
+		t.Fatalf("expected run not to be executed yet, got %+v", run)
+	}
+	if len(store.runs[run.ID].FilesChanged) != 1 {
+		t.Fatalf("expected files changed to be persisted, got %+v", store.runs[run.ID])
+	}
+
+	select {
+	case <-svc.wake:
+	default:
+		t.Fatal("expected QueueRun to wake the worker")
+	}
+}
+
+func TestExecuteRunWritesArtifactAndCompletes(t *testing.T) {
 	tempDir := t.TempDir()
 	store := newMockStore()
 	svc := NewService(store, tempDir)
 	fixedTime := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
 	svc.now = func() time.Time { return fixedTime }
 
-	run, err := svc.QueueRun(context.Background(), RunRequest{
+	ctx := context.Background()
+	if _, err := svc.QueueRun(ctx, RunRequest{
 		ProjectID:    1,
 		AgentType:    "continuity",
 		Trigger:      "pr",
 		FilesChanged: []string{"chapters/01.md"},
-	})
-	if err != nil {
+	}); err != nil {
 		t.Fatalf("QueueRun returned error: %v", err)
 	}
 
+	claimed, err := svc.claimNextRun(ctx)
+	if err != nil {
+		t.Fatalf("claimNextRun returned error: %v", err)
+	}
+	if err := svc.executeRun(ctx, claimed); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+
+	run, err := svc.GetRun(ctx, claimed.ID)
+	if err != nil {
+		t.Fatalf("GetRun returned error: %v", err)
+	}
+
 	if run.Status != "completed" {
 		t.Fatalf("expected status %s, got %s", "completed", run.Status)
 	}
@@ -62,6 +108,70 @@ func TestQueueRunWritesArtifactAndCompletes(t *testing.T) {
 	}
 }
 
+func TestExecuteRunRecordsProviderUsage(t *testing.T) {
+	store := newMockStore()
+	provider := NewFakeProvider(CompletionResponse{
+		Content: "No claims need checking.",
+		Usage:   Usage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120},
+	})
+	registry := NewRegistry()
+	_ = registry.Register(&promptAgent{name: "notes", trigger: "manual", instructions: "Summarise the changes."})
+	svc := NewService(store, t.TempDir(), WithRegistry(registry), WithProvider(provider, "test/model"))
+
+	ctx := context.Background()
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "notes"}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	claimed, err := svc.claimNextRun(ctx)
+	if err != nil {
+		t.Fatalf("claimNextRun returned error: %v", err)
+	}
+	if err := svc.executeRun(ctx, claimed); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+
+	results := store.runs[claimed.ID].Results
+	if results.Summary != "No claims need checking." || results.Stats.TokensUsed != 120 {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if reqs := provider.Requests(); len(reqs) != 1 || reqs[0].Model != "test/model" {
+		t.Fatalf("expected one request for test/model, got %+v", reqs)
+	}
+}
+
+func TestExecuteRunBuildsContextFromWorkspace(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "chapters/01.md", "Alice opened the door.\n")
+	writeFile(t, root, "CREATIVE.md", "Tone: hopeful.\n")
+
+	registry := NewRegistry()
+	agent := &stubAgent{name: "continuity", result: Result{Summary: "ok"}}
+	_ = registry.Register(agent)
+
+	store := newMockStore()
+	svc := NewService(store, t.TempDir(),
+		WithRegistry(registry),
+		WithWorkspaces(stubWorkspaces{1: root}, nil),
+	)
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "continuity", FilesChanged: []string{"chapters/01.md"}}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	claimed, _ := svc.claimNextRun(ctx)
+	if err := svc.executeRun(ctx, claimed); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+
+	input := agent.inputs[0]
+	if len(input.Context.Documents) != 0 {
+		t.Fatalf("expected no reference documents for ChangedFiles requirements, got %+v", input.Context.Documents)
+	}
+	if input.Chunk == nil || input.Chunk.Path != "chapters/01.md" || input.Chunk.Content != "Alice opened the door." {
+		t.Fatalf("expected the changed chapter as the chunk under review, got %+v", input.Chunk)
+	}
+}
+
 func TestQueueRunRejectsInvalidAgent(t *testing.T) {
 	store := newMockStore()
 	svc := NewService(store, t.TempDir())
@@ -75,25 +185,88 @@ func TestQueueRunRejectsInvalidAgent(t *testing.T) {
 	}
 }
 
+func TestQueueRunScheduledCoversAllChapters(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "chapters/02.md", "Two.\n")
+	writeFile(t, root, "chapters/01.md", "One.\n")
+	writeFile(t, root, "chapters/.draft.md", "Hidden.\n")
+	writeFile(t, root, "docs/world/city.md", "Not a chapter.\n")
+	svc := NewService(newMockStore(), t.TempDir(), WithWorkspaces(stubWorkspaces{1: root}, nil))
+
+	run, err := svc.QueueRun(context.Background(), RunRequest{ProjectID: 1, AgentType: "timeline", Trigger: "scheduled"})
+	if err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	if len(run.FilesChanged) != 2 || run.FilesChanged[0] != "chapters/01.md" || run.FilesChanged[1] != "chapters/02.md" {
+		t.Fatalf("expected every chapter in order, got %v", run.FilesChanged)
+	}
+}
+
+func TestHasChapters(t *testing.T) {
+	withChapters := t.TempDir()
+	writeFile(t, withChapters, "chapters/01.md", "One.\n")
+	svc := NewService(newMockStore(), t.TempDir(), WithWorkspaces(stubWorkspaces{1: withChapters, 2: t.TempDir()}, nil))
+	ctx := context.Background()
+
+	if ok, err := svc.HasChapters(ctx, 1); err != nil || !ok {
+		t.Fatalf("expected project 1 to have chapters, got %v %v", ok, err)
+	}
+	if ok, err := svc.HasChapters(ctx, 2); err != nil || ok {
+		t.Fatalf("expected an empty workspace to have no chapters, got %v %v", ok, err)
+	}
+	if ok, err := NewService(newMockStore(), t.TempDir()).HasChapters(ctx, 1); err != nil || ok {
+		t.Fatalf("expected no chapters without a workspace, got %v %v", ok, err)
+	}
+}
+
+type stubWorkspaces map[int64]string
+
+func (w stubWorkspaces) WorkspacePath(_ context.Context, projectID int64) (string, error) {
+	root, ok := w[projectID]
+	if !ok {
+		return "", ErrProjectNotFound
+	}
+	return root, nil
+}
+
+func writeFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	target := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", rel, err)
+	}
+}
+
 type mockStore struct {
-	nextID   int64
-	runs     map[int64]models.AgentRun
-	projects map[int64]bool
+	mu         sync.Mutex
+	nextID     int64
+	runs       map[int64]models.AgentRun
+	heartbeats map[int64]time.Time
+	projects   map[int64]bool
+	progress   []models.RunProgress
 }
 
 func newMockStore() *mockStore {
 	return &mockStore{
-		nextID:   1,
-		runs:     make(map[int64]models.AgentRun),
-		projects: map[int64]bool{1: true},
+		nextID:     1,
+		runs:       make(map[int64]models.AgentRun),
+		heartbeats: make(map[int64]time.Time),
+		projects:   map[int64]bool{1: true},
 	}
 }
 
 func (m *mockStore) ProjectExists(_ context.Context, projectID int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	return m.projects[projectID], nil
 }
 
 func (m *mockStore) InsertRun(_ context.Context, run models.AgentRun) (models.AgentRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	run.ID = m.nextID
 	m.nextID++
 	run.CreatedAt = time.Now()
@@ -101,25 +274,167 @@ func (m *mockStore) InsertRun(_ context.Context, run models.AgentRun) (models.Ag
 	return run, nil
 }
 
-func (m *mockStore) MarkRunning(_ context.Context, id int64, startedAt time.Time) error {
+func (m *mockStore) ClaimNextRun(_ context.Context, startedAt time.Time) (models.AgentRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.sortedIDs() {
+		run := m.runs[id]
+		if run.Status != "queued" || (run.NextAttemptAt != nil && run.NextAttemptAt.After(startedAt)) || !m.dependenciesCompleted(run) {
+			continue
+		}
+		run.Status = "running"
+		run.StartedAt = &startedAt
+		run.Attempts++
+		m.runs[id] = run
+		return run, nil
+	}
+	return models.AgentRun{}, models.ErrNotFound
+}
+
+func (m *mockStore) Heartbeat(_ context.Context, id int64, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.runs[id].Status == "running" {
+		m.heartbeats[id] = at
+	}
+	return nil
+}
+
+func (m *mockStore) ClaimStaleRuns(_ context.Context, staleBefore, now time.Time) ([]models.AgentRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var stale []models.AgentRun
+	for _, id := range m.sortedIDs() {
+		run := m.runs[id]
+		if run.Status != "running" || run.StartedAt == nil {
+			continue
+		}
+		last := *run.StartedAt
+		if beat, ok := m.heartbeats[id]; ok && beat.After(last) {
+			last = beat
+		}
+		if last.Before(staleBefore) {
+			m.heartbeats[id] = now
+			stale = append(stale, run)
+		}
+	}
+	return stale, nil
+}
+
+func (m *mockStore) dependenciesCompleted(run models.AgentRun) bool {
+	for _, dep := range run.DependsOn {
+		if m.runs[dep].Status != "completed" {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *mockStore) InsertPipeline(_ context.Context, runs []models.AgentRun, dependsOn [][]int) ([]models.AgentRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inserted := make([]models.AgentRun, 0, len(runs))
+	for i, run := range runs {
+		run.ID = m.nextID
+		m.nextID++
+		run.CreatedAt = time.Now()
+		for _, dep := range dependsOn[i] {
+			run.DependsOn = append(run.DependsOn, inserted[dep].ID)
+		}
+		m.runs[run.ID] = run
+		inserted = append(inserted, run)
+	}
+	return inserted, nil
+}
+
+func (m *mockStore) CancelDependents(_ context.Context, runID int64, message string, completedAt time.Time) ([]models.AgentRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var cancelled []models.AgentRun
+	upstream := map[int64]bool{runID: true}
+	for _, id := range m.sortedIDs() {
+		run := m.runs[id]
+		if !slices.ContainsFunc(run.DependsOn, func(dep int64) bool { return upstream[dep] }) {
+			continue
+		}
+		upstream[id] = true
+		if run.Status != "queued" {
+			continue
+		}
+		run.Status = "cancelled"
+		run.Error = message
+		run.CompletedAt = &completedAt
+		m.runs[id] = run
+		cancelled = append(cancelled, run)
+	}
+	return cancelled, nil
+}
+
+func (m *mockStore) ListPipelineRuns(_ context.Context, pipelineID string) ([]models.AgentRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var runs []models.AgentRun
+	for _, id := range m.sortedIDs() {
+		if m.runs[id].PipelineID == pipelineID {
+			runs = append(runs, m.runs[id])
+		}
+	}
+	return runs, nil
+}
+
+func (m *mockStore) SetPromptHash(_ context.Context, id int64, hash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run := m.runs[id]
+	run.PromptHash = hash
+	m.runs[id] = run
+	return nil
+}
+
+func (m *mockStore) SetEditorialRound(_ context.Context, id int64, round string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run := m.runs[id]
+	run.EditorialRound = round
+	m.runs[id] = run
+	return nil
+}
+
+func (m *mockStore) UpdateProgress(_ context.Context, id int64, progress models.RunProgress) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	run := m.runs[id]
-	run.Status = "running"
-	run.StartedAt = &startedAt
+	run.Progress = &progress
 	m.runs[id] = run
+	m.progress = append(m.progress, progress)
 	return nil
 }
 
 func (m *mockStore) MarkCompleted(_ context.Context, id int64, results json.RawMessage, completedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	run := m.runs[id]
+	if run.Status != "running" {
+		return models.ErrNotFound
+	}
+	var parsed models.RunResult
+	if err := json.Unmarshal(results, &parsed); err != nil {
+		return err
+	}
 	run.Status = "completed"
-	run.Results = results
+	run.Results = &parsed
 	run.CompletedAt = &completedAt
 	m.runs[id] = run
 	return nil
 }
 
 func (m *mockStore) MarkFailed(_ context.Context, id int64, message string, completedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	run := m.runs[id]
+	if run.Status != "running" {
+		return models.ErrNotFound
+	}
 	run.Status = "failed"
 	run.Error = message
 	run.CompletedAt = &completedAt
@@ -127,10 +442,137 @@ func (m *mockStore) MarkFailed(_ context.Context, id int64, message string, comp
 	return nil
 }
 
+func (m *mockStore) ScheduleRetry(_ context.Context, id int64, message string, nextAttemptAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run := m.runs[id]
+	if run.Status != "running" {
+		return models.ErrNotFound
+	}
+	run.Status = "queued"
+	run.Error = message
+	run.NextAttemptAt = &nextAttemptAt
+	run.StartedAt = nil
+	m.runs[id] = run
+	return nil
+}
+
+func (m *mockStore) MarkDeadLetter(_ context.Context, id int64, message string, completedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run := m.runs[id]
+	if run.Status != "running" {
+		return models.ErrNotFound
+	}
+	run.Status = "dead_letter"
+	run.Error = message
+	run.NextAttemptAt = nil
+	run.CompletedAt = &completedAt
+	m.runs[id] = run
+	return nil
+}
+
+func (m *mockStore) MarkCancelled(_ context.Context, id int64, completedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run, ok := m.runs[id]
+	if !ok || (run.Status != "queued" && run.Status != "running") {
+		return models.ErrNotFound
+	}
+	run.Status = "cancelled"
+	run.NextAttemptAt = nil
+	run.CompletedAt = &completedAt
+	m.runs[id] = run
+	return nil
+}
+
+func (m *mockStore) MarkTimedOut(_ context.Context, id int64, message string, completedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run := m.runs[id]
+	if run.Status != "running" {
+		return models.ErrNotFound
+	}
+	run.Status = "timed_out"
+	run.Error = message
+	run.CompletedAt = &completedAt
+	m.runs[id] = run
+	return nil
+}
+
+func (m *mockStore) RequeueRun(_ context.Context, id int64, creditsReserved int) (models.AgentRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run, ok := m.runs[id]
+	if !ok || run.Status != "dead_letter" {
+		return models.AgentRun{}, models.ErrNotFound
+	}
+	run.Status = "queued"
+	run.Attempts = 0
+	run.CreditsReserved = creditsReserved
+	run.Error = ""
+	run.StartedAt = nil
+	run.CompletedAt = nil
+	m.runs[id] = run
+	return run, nil
+}
+
+func (m *mockStore) RequeueDependent(_ context.Context, id int64, message string, creditsReserved int) (models.AgentRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run, ok := m.runs[id]
+	if !ok || run.Status != "cancelled" || run.Error != message {
+		return models.AgentRun{}, models.ErrNotFound
+	}
+	run.Status = "queued"
+	run.Attempts = 0
+	run.CreditsReserved = creditsReserved
+	run.Error = ""
+	run.StartedAt = nil
+	run.CompletedAt = nil
+	m.runs[id] = run
+	return run, nil
+}
+
+func (m *mockStore) ListDeadLetterRuns(_ context.Context, projectID int64) ([]models.AgentRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var runs []models.AgentRun
+	for _, id := range m.sortedIDs() {
+		if run := m.runs[id]; run.ProjectID == projectID && run.Status == "dead_letter" {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
 func (m *mockStore) GetRun(_ context.Context, id int64) (models.AgentRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	run, ok := m.runs[id]
 	if !ok {
 		return models.AgentRun{}, ErrRunNotFound
 	}
 	return run, nil
 }
+
+func (m *mockStore) ListRuns(_ context.Context, projectID int64) ([]models.AgentRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var runs []models.AgentRun
+	for _, id := range m.sortedIDs() {
+		if m.runs[id].ProjectID == projectID {
+			runs = append(runs, m.runs[id])
+		}
+	}
+	return runs, nil
+}
+
+func (m *mockStore) sortedIDs() []int64 {
+	ids := make([]int64, 0, len(m.runs))
+	for id := range m.runs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
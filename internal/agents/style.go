@@ -0,0 +1,230 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+var styleInstructions = mustBuiltinPrompt("style")
+
+// styleAgent (StyleBot) computes deterministic readability and voice metrics locally and,
+// when a provider is configured, adds a model pass for issues metrics cannot catch.
+type styleAgent struct{}
+
+func (styleAgent) Name() string           { return "style" }
+func (styleAgent) DefaultTrigger() string { return "commit" }
+func (styleAgent) ContextRequirements() ContextRequirements {
+	return ContextRequirements{ChangedFiles: true, Editorial: true}
+}
+
+func (styleAgent) CacheVersion() string { return "style/1" }
+
+func (styleAgent) DecodeData(raw json.RawMessage) (any, error) { return decodeData[styleCounts](raw) }
+
+func (a styleAgent) Run(ctx context.Context, input Input) (Result, error) {
+	if input.Chunk == nil {
+		return Result{Summary: "No prose to analyse."}, nil
+	}
+
+	counts, issues := analyseStyle(extractParagraphs(*input.Chunk))
+	result := Result{Issues: issues, Data: counts}
+
+	if input.Provider != nil {
+		grade, _ := fleschKincaid(counts.Words, counts.Sentences, counts.Syllables)
+		notes := fmt.Sprintf("Words: %d. Sentences: %d. Flesch-Kincaid grade: %.1f. Passive constructions: %d.",
+			counts.Words, counts.Sentences, grade, counts.Passive)
+		instructions, err := input.instructions(styleInstructions)
+		if err != nil {
+			return Result{}, err
+		}
+		parsed, usage, err := askModel(ctx, input, a.Name(), instructions, notes)
+		if err != nil {
+			return Result{}, err
+		}
+		result.Summary = parsed.Summary
+		result.Issues = append(result.Issues, parsed.Issues...)
+		result.Usage = usage
+	}
+	return result, nil
+}
+
+// Reduce sums the per-chunk counts, derives manuscript-wide metrics and flags paragraphs
+// whose narration departs from the dominant tense.
+func (a styleAgent) Reduce(_ context.Context, _ Input, results []Result) (Result, error) {
+	var counts styleCounts
+	var merged Result
+	var modelSummaries []string
+	for _, r := range results {
+		if c, ok := r.Data.(styleCounts); ok {
+			counts.add(c)
+		}
+		merged.Issues = append(merged.Issues, r.Issues...)
+		merged.Usage.PromptTokens += r.Usage.PromptTokens
+		merged.Usage.CompletionTokens += r.Usage.CompletionTokens
+		merged.Usage.TotalTokens += r.Usage.TotalTokens
+		if s := strings.TrimSpace(r.Summary); s != "" {
+			modelSummaries = append(modelSummaries, s)
+		}
+	}
+
+	dominant, shifts := tenseShifts(counts.Paragraphs)
+	merged.Issues = dedupeIssues(append(merged.Issues, shifts...))
+	merged.Metrics = styleMetrics(counts, dominant, len(shifts))
+
+	grade, _ := fleschKincaid(counts.Words, counts.Sentences, counts.Syllables)
+	merged.Summary = fmt.Sprintf("Flesch-Kincaid grade %.1f across %d words; average sentence %.1f words; dialogue %.0f%%; %d issues.",
+		grade, counts.Words, ratio(counts.Words, counts.Sentences), ratio(counts.DialogueRunes, counts.TotalRunes)*100, len(merged.Issues))
+	if len(modelSummaries) > 0 {
+		merged.Summary += "\n\n" + strings.Join(modelSummaries, "\n\n")
+	}
+	return merged, nil
+}
+
+// analyseStyle counts metrics for the paragraphs and reports sentence-level issues.
+func analyseStyle(paragraphs []proseParagraph) (styleCounts, []models.Issue) {
+	var counts styleCounts
+	var issues []models.Issue
+
+	for _, p := range paragraphs {
+		narration, dialogue := stripDialogue(p.text)
+		counts.DialogueRunes += dialogue
+		counts.TotalRunes += len([]rune(p.text))
+
+		paragraphWords, paragraphAdverbs := 0, 0
+		for _, s := range splitSentences(p.text) {
+			n := len(s.words)
+			counts.Sentences++
+			counts.Words += n
+			counts.SentenceLengths = append(counts.SentenceLengths, n)
+			paragraphWords += n
+			for _, w := range s.words {
+				counts.Syllables += countSyllables(w)
+				if isAdverb(w) {
+					counts.Adverbs++
+					paragraphAdverbs++
+				}
+			}
+
+			if n > longSentenceWords {
+				line, col := p.position(s.start)
+				endLine, _ := p.position(s.end)
+				issues = append(issues, models.Issue{
+					Severity:    models.SeverityWarning,
+					Category:    "sentence-length",
+					Message:     fmt.Sprintf("Sentence is %d words long; consider splitting it.", n),
+					File:        p.file,
+					StartLine:   line,
+					EndLine:     endLine,
+					StartColumn: col,
+					Excerpt:     excerpt(p.text[s.start:s.end]),
+					Confidence:  1,
+				})
+			}
+		}
+
+		for _, loc := range passivePattern.FindAllStringSubmatchIndex(narration, -1) {
+			participle := strings.ToLower(narration[loc[4]:loc[5]])
+			if notParticiples[participle] {
+				continue
+			}
+			counts.Passive++
+			line, col := p.position(loc[0])
+			endLine, endCol := p.position(loc[1])
+			issues = append(issues, models.Issue{
+				Severity:    models.SeverityInfo,
+				Category:    "passive-voice",
+				Message:     "Possible passive construction.",
+				File:        p.file,
+				StartLine:   line,
+				EndLine:     endLine,
+				StartColumn: col,
+				EndColumn:   endCol - 1,
+				Excerpt:     p.text[loc[0]:loc[1]],
+				Suggestion:  "Consider naming who performs the action.",
+				Confidence:  0.6,
+			})
+		}
+
+		if paragraphAdverbs >= adverbParagraphMinimum && ratio(paragraphAdverbs, paragraphWords) > adverbParagraphDensity {
+			issues = append(issues, models.Issue{
+				Severity:   models.SeverityInfo,
+				Category:   "adverbs",
+				Message:    fmt.Sprintf("Paragraph uses %d -ly adverbs in %d words.", paragraphAdverbs, paragraphWords),
+				File:       p.file,
+				StartLine:  p.startLine,
+				EndLine:    p.endLine(),
+				Excerpt:    excerpt(p.text),
+				Suggestion: "Prefer stronger verbs over adverb modifiers.",
+				Confidence: 0.7,
+			})
+		}
+
+		past, present := 0, 0
+		for _, w := range wordPattern.FindAllString(narration, -1) {
+			switch {
+			case presentMarkers[strings.ToLower(w)]:
+				present++
+			case isPastMarker(w):
+				past++
+			}
+		}
+		counts.Paragraphs = append(counts.Paragraphs, paragraphTense{
+			File:      p.file,
+			StartLine: p.startLine,
+			EndLine:   p.endLine(),
+			Past:      past,
+			Present:   present,
+			Excerpt:   excerpt(p.text),
+		})
+	}
+	return counts, issues
+}
+
+// tenseShifts finds the dominant narrative tense and reports paragraphs written in the other one.
+func tenseShifts(paragraphs []paragraphTense) (string, []models.Issue) {
+	votes := map[string]int{}
+	for _, p := range paragraphs {
+		votes[p.tense()]++
+	}
+	dominant, other := "past", "present"
+	if votes["present"] > votes["past"] {
+		dominant, other = "present", "past"
+	}
+	if votes[dominant] == 0 {
+		return "", nil
+	}
+
+	var issues []models.Issue
+	for _, p := range paragraphs {
+		if p.tense() != other {
+			continue
+		}
+		issues = append(issues, models.Issue{
+			Severity:   models.SeverityWarning,
+			Category:   "tense",
+			Message:    fmt.Sprintf("Paragraph is narrated in the %s tense; most of the text uses the %s tense.", other, dominant),
+			File:       p.File,
+			StartLine:  p.StartLine,
+			EndLine:    p.EndLine,
+			Excerpt:    p.Excerpt,
+			Confidence: 0.5,
+		})
+	}
+	return dominant, issues
+}
+
+// excerpt returns the first line of text, shortened for display.
+func excerpt(text string) string {
+	text = strings.TrimSpace(text)
+	if i := strings.Index(text, "\n"); i >= 0 {
+		text = text[:i]
+	}
+	if r := []rune(text); len(r) > 120 {
+		text = string(r[:117]) + "..."
+	}
+	return text
+}
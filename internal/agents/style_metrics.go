@@ -0,0 +1,318 @@
+package agents
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+)
+
+const (
+	longSentenceWords      = 40
+	adverbParagraphDensity = 0.06
+	adverbParagraphMinimum = 3
+	tenseMinimumMarkers    = 3
+)
+
+var (
+	wordPattern     = regexp.MustCompile(`\p{L}[\p{L}'’-]*`)
+	sentenceEnd     = regexp.MustCompile(`[.!?…]+["'”’)\]]*(\s+|$)`)
+	dialoguePattern = regexp.MustCompile(`"[^"\n]*"|“[^”]*”`)
+	passivePattern  = regexp.MustCompile(`(?i)\b(am|is|are|was|were|be|been|being)\s+(?:\w+ly\s+)?(\w+ed|\w+en|born|built|caught|done|found|given|held|kept|known|left|made|paid|seen|sent|shown|sold|taken|taught|told|thought|won|written)\b`)
+)
+
+// notParticiples are -ed/-en words that follow "to be" without forming the passive.
+var notParticiples = map[string]bool{
+	"even": true, "open": true, "often": true, "then": true, "when": true, "ten": true,
+	"seven": true, "heaven": true, "garden": true, "children": true, "golden": true,
+	"wooden": true, "sudden": true, "naked": true, "wicked": true, "sacred": true,
+	"hundred": true, "need": true, "seed": true, "indeed": true, "bed": true, "red": true,
+}
+
+// notAdverbs end in -ly but are nouns, adjectives or verbs.
+var notAdverbs = map[string]bool{
+	"only": true, "family": true, "early": true, "reply": true, "supply": true, "belly": true,
+	"holy": true, "ugly": true, "fly": true, "july": true, "italy": true, "lily": true,
+	"ally": true, "rely": true, "apply": true, "imply": true, "bully": true, "jelly": true,
+	"silly": true, "lonely": true, "lovely": true, "friendly": true, "likely": true,
+	"daily": true, "elderly": true, "curly": true, "hilly": true, "chilly": true, "sly": true,
+	"emily": true, "molly": true, "sally": true, "kelly": true, "holly": true, "billy": true,
+}
+
+var pastMarkers = map[string]bool{
+	"was": true, "were": true, "had": true, "did": true, "said": true, "went": true,
+	"came": true, "saw": true, "thought": true, "knew": true, "took": true, "felt": true,
+	"made": true, "got": true, "stood": true, "ran": true, "told": true, "left": true,
+}
+
+var presentMarkers = map[string]bool{
+	"is": true, "are": true, "am": true, "has": true, "does": true, "says": true,
+	"goes": true, "comes": true, "sees": true, "thinks": true, "knows": true, "takes": true,
+	"feels": true, "makes": true, "gets": true, "stands": true, "runs": true, "tells": true,
+	"looks": true, "seems": true, "turns": true, "walks": true,
+}
+
+// proseParagraph is a paragraph of narrative text with its source location.
+type proseParagraph struct {
+	file      string
+	startLine int
+	text      string // lines joined with \n so offsets map back to lines
+}
+
+func (p proseParagraph) endLine() int {
+	return p.startLine + strings.Count(p.text, "\n")
+}
+
+// position converts a byte offset in the paragraph to a 1-based line and rune column.
+func (p proseParagraph) position(offset int) (int, int) {
+	before := p.text[:offset]
+	lineStart := strings.LastIndex(before, "\n") + 1
+	return p.startLine + strings.Count(before, "\n"), utf8.RuneCountInString(before[lineStart:]) + 1
+}
+
+// extractParagraphs returns the prose paragraphs of a chunk, skipping overlap lines
+// (already counted by the previous chunk), YAML frontmatter, headings, scene breaks,
+// fenced code and HTML comments.
+func extractParagraphs(chunk manuscript.Chunk) []proseParagraph {
+	lines := strings.Split(chunk.Content, "\n")
+	var paragraphs []proseParagraph
+	var current []string
+	start := 0
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, proseParagraph{file: chunk.Path, startLine: start, text: strings.Join(current, "\n")})
+			current = nil
+		}
+	}
+
+	inFrontmatter := chunk.StartLine == 1 && len(lines) > 0 && strings.TrimSpace(lines[0]) == "---"
+	inFence := false
+	for i, raw := range lines {
+		number := chunk.StartLine + i
+		trimmed := strings.TrimSpace(raw)
+
+		switch {
+		case inFrontmatter:
+			if i > 0 && trimmed == "---" {
+				inFrontmatter = false
+			}
+			continue
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			inFence = !inFence
+			continue
+		case inFence, i < chunk.OverlapLines:
+			continue
+		case trimmed == "", strings.HasPrefix(trimmed, "#"), strings.HasPrefix(trimmed, "<!--"), isSceneBreakLine(trimmed):
+			flush()
+			continue
+		}
+
+		if len(current) == 0 {
+			start = number
+		}
+		current = append(current, raw)
+	}
+	flush()
+	return paragraphs
+}
+
+func isSceneBreakLine(trimmed string) bool {
+	compact := strings.ReplaceAll(trimmed, " ", "")
+	return compact == "⁂" || (len(compact) >= 3 && (strings.Trim(compact, "*") == "" || strings.Trim(compact, "-") == ""))
+}
+
+// paragraphTense records the tense signal of one paragraph's narration.
+type paragraphTense struct {
+	File      string
+	StartLine int
+	EndLine   int
+	Past      int
+	Present   int
+	Excerpt   string
+}
+
+func (p paragraphTense) tense() string {
+	if p.Past+p.Present < tenseMinimumMarkers {
+		return ""
+	}
+	switch {
+	case p.Past >= 2*p.Present:
+		return "past"
+	case p.Present >= 2*p.Past:
+		return "present"
+	}
+	return "mixed"
+}
+
+// styleCounts are additive counts for one chunk; metrics are derived after summing.
+type styleCounts struct {
+	Sentences       int
+	Words           int
+	Syllables       int
+	Adverbs         int
+	Passive         int
+	DialogueRunes   int
+	TotalRunes      int
+	SentenceLengths []int
+	Paragraphs      []paragraphTense
+}
+
+func (c *styleCounts) add(other styleCounts) {
+	c.Sentences += other.Sentences
+	c.Words += other.Words
+	c.Syllables += other.Syllables
+	c.Adverbs += other.Adverbs
+	c.Passive += other.Passive
+	c.DialogueRunes += other.DialogueRunes
+	c.TotalRunes += other.TotalRunes
+	c.SentenceLengths = append(c.SentenceLengths, other.SentenceLengths...)
+	c.Paragraphs = append(c.Paragraphs, other.Paragraphs...)
+}
+
+// sentenceSpan is a sentence located by byte offsets within its paragraph.
+type sentenceSpan struct {
+	start, end int
+	words      []string
+}
+
+func splitSentences(text string) []sentenceSpan {
+	var spans []sentenceSpan
+	start := 0
+	add := func(end int) {
+		if words := wordPattern.FindAllString(text[start:end], -1); len(words) > 0 {
+			spans = append(spans, sentenceSpan{start: start, end: end, words: words})
+		}
+	}
+	for _, loc := range sentenceEnd.FindAllStringIndex(text, -1) {
+		add(loc[1])
+		start = loc[1]
+	}
+	if start < len(text) {
+		add(len(text))
+	}
+	return spans
+}
+
+func countSyllables(word string) int {
+	w := strings.ToLower(strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) }))
+	if utf8.RuneCountInString(w) <= 3 {
+		return 1
+	}
+	count := 0
+	prevVowel := false
+	for _, r := range w {
+		vowel := strings.ContainsRune("aeiouy", r)
+		if vowel && !prevVowel {
+			count++
+		}
+		prevVowel = vowel
+	}
+	if strings.HasSuffix(w, "e") && !strings.HasSuffix(w, "le") && count > 1 {
+		count--
+	}
+	if count == 0 {
+		count = 1
+	}
+	return count
+}
+
+func isAdverb(word string) bool {
+	w := strings.ToLower(word)
+	return len(w) > 4 && strings.HasSuffix(w, "ly") && !notAdverbs[w]
+}
+
+func isPastMarker(word string) bool {
+	w := strings.ToLower(word)
+	return pastMarkers[w] || (len(w) >= 5 && strings.HasSuffix(w, "ed") && !notParticiples[w])
+}
+
+func stripDialogue(text string) (string, int) {
+	dialogue := 0
+	narration := dialoguePattern.ReplaceAllStringFunc(text, func(m string) string {
+		dialogue += utf8.RuneCountInString(m)
+		return strings.Repeat(" ", len(m))
+	})
+	return narration, dialogue
+}
+
+// fleschKincaid returns the grade level and reading ease for the given counts.
+func fleschKincaid(words, sentences, syllables int) (float64, float64) {
+	if words == 0 || sentences == 0 {
+		return 0, 0
+	}
+	wps := float64(words) / float64(sentences)
+	spw := float64(syllables) / float64(words)
+	return 0.39*wps + 11.8*spw - 15.59, 206.835 - 1.015*wps - 84.6*spw
+}
+
+var sentenceBuckets = []struct {
+	label string
+	max   int
+}{
+	{"1-10", 10}, {"11-20", 20}, {"21-30", 30}, {"31-40", 40}, {"41+", int(^uint(0) >> 1)},
+}
+
+// styleMetrics derives the reported metrics from summed counts.
+func styleMetrics(c styleCounts, dominantTense string, tenseShifts int) map[string]any {
+	grade, ease := fleschKincaid(c.Words, c.Sentences, c.Syllables)
+
+	distribution := make(map[string]int, len(sentenceBuckets))
+	for _, b := range sentenceBuckets {
+		distribution[b.label] = 0
+	}
+	for _, n := range c.SentenceLengths {
+		for _, b := range sentenceBuckets {
+			if n <= b.max {
+				distribution[b.label]++
+				break
+			}
+		}
+	}
+
+	return map[string]any{
+		"words":                        c.Words,
+		"sentences":                    c.Sentences,
+		"avg_sentence_length":          round2(ratio(c.Words, c.Sentences)),
+		"median_sentence_length":       median(c.SentenceLengths),
+		"sentence_length_distribution": distribution,
+		"flesch_kincaid_grade":         round2(grade),
+		"flesch_reading_ease":          round2(ease),
+		"adverb_density":               round2(ratio(c.Adverbs, c.Words)),
+		"passive_voice_count":          c.Passive,
+		"passive_per_1000_words":       round2(ratio(c.Passive*1000, c.Words)),
+		"dialogue_ratio":               round2(ratio(c.DialogueRunes, c.TotalRunes)),
+		"dominant_tense":               dominantTense,
+		"tense_shift_paragraphs":       tenseShifts,
+	}
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+func round2(f float64) float64 {
+	if f < 0 {
+		return -round2(-f)
+	}
+	return float64(int64(f*100+0.5)) / 100
+}
+
+func median(values []int) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return float64(sorted[mid])
+	}
+	return float64(sorted[mid-1]+sorted[mid]) / 2
+}
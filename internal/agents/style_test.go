@@ -0,0 +1,114 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+const styleFixture = `---
+title: Chapter One
+---
+# The Harbour
+
+Mara walked to the harbour. The boats were tied along the quay. She watched the gulls and waited for the ferry.
+
+"Are you coming?" Tom asked.
+
+The letter was written by her brother. She quickly and quietly and carefully folded it.
+
+Mara walks to the end of the pier. She is cold and the wind is sharp. The ferry comes and she takes a seat.
+
+She waited and watched and wondered whether the ferry that had been promised for the morning would ever arrive at the harbour before the storm that everyone in the village had been talking about since dawn finally broke over the hills and soaked them all.
+`
+
+func runStyle(t *testing.T, input Input) Result {
+	t.Helper()
+	agent := styleAgent{}
+	chunks := manuscript.ChunkDocuments([]manuscript.Document{{Path: "chapters/01.md", Content: styleFixture}}, manuscript.ChunkOptions{})
+	var results []Result
+	for i := range chunks {
+		input.Chunk = &chunks[i]
+		r, err := agent.Run(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+		results = append(results, r)
+	}
+	merged, err := agent.Reduce(context.Background(), input, results)
+	if err != nil {
+		t.Fatalf("Reduce returned error: %v", err)
+	}
+	return merged
+}
+
+func issuesByCategory(issues []models.Issue) map[string][]models.Issue {
+	out := map[string][]models.Issue{}
+	for _, issue := range issues {
+		out[issue.Category] = append(out[issue.Category], issue)
+	}
+	return out
+}
+
+func TestStyleAgentDeterministicMetrics(t *testing.T) {
+	result := runStyle(t, Input{})
+
+	if result.Usage.TotalTokens != 0 {
+		t.Fatalf("expected no model usage without a provider, got %+v", result.Usage)
+	}
+	if result.Metrics["dominant_tense"] != "past" {
+		t.Fatalf("expected past dominant tense, got %v", result.Metrics["dominant_tense"])
+	}
+	if grade, ok := result.Metrics["flesch_kincaid_grade"].(float64); !ok || grade <= 0 {
+		t.Fatalf("expected a positive grade, got %v", result.Metrics["flesch_kincaid_grade"])
+	}
+	if ratio := result.Metrics["dialogue_ratio"].(float64); ratio <= 0 || ratio >= 0.5 {
+		t.Fatalf("expected some dialogue, got %v", ratio)
+	}
+	if dist := result.Metrics["sentence_length_distribution"].(map[string]int); dist["41+"] != 1 {
+		t.Fatalf("expected one sentence over 40 words, got %v", dist)
+	}
+
+	byCategory := issuesByCategory(result.Issues)
+	long := byCategory["sentence-length"]
+	if len(long) != 1 || long[0].StartLine != 14 || long[0].StartColumn != 1 {
+		t.Fatalf("expected long sentence anchored at line 14, got %+v", long)
+	}
+	passive := byCategory["passive-voice"]
+	if len(passive) < 1 || passive[0].Excerpt != "were tied" || passive[0].StartLine != 6 {
+		t.Fatalf("expected passive construction on line 6, got %+v", passive)
+	}
+	if adverbs := byCategory["adverbs"]; len(adverbs) != 1 || adverbs[0].StartLine != 10 {
+		t.Fatalf("expected adverb-heavy paragraph on line 10, got %+v", adverbs)
+	}
+	tense := byCategory["tense"]
+	if len(tense) != 1 || tense[0].StartLine != 12 || !strings.Contains(tense[0].Message, "present") {
+		t.Fatalf("expected present-tense paragraph on line 12, got %+v", tense)
+	}
+}
+
+func TestStyleAgentAugmentsWithModel(t *testing.T) {
+	provider := NewFakeProvider(CompletionResponse{
+		Content: `{"summary": "Voice is consistent.", "issues": [{"severity": "info", "category": "voice", "message": "Tom sounds formal.", "file": "chapters/01.md", "start_line": 8, "confidence": 0.4}]}`,
+		Usage:   Usage{TotalTokens: 300},
+	})
+
+	result := runStyle(t, Input{Provider: provider, Model: "test/model"})
+
+	if result.Usage.TotalTokens != 300 {
+		t.Fatalf("expected model usage to be recorded, got %+v", result.Usage)
+	}
+	if len(issuesByCategory(result.Issues)["voice"]) != 1 {
+		t.Fatalf("expected model issue to be merged, got %+v", result.Issues)
+	}
+	if !strings.Contains(result.Summary, "Voice is consistent.") {
+		t.Fatalf("expected model summary, got %q", result.Summary)
+	}
+	reqs := provider.Requests()
+	if len(reqs) != 1 || !strings.Contains(reqs[0].Messages[1].Content, "Flesch-Kincaid grade") {
+		t.Fatalf("expected metrics in the prompt notes, got %+v", reqs)
+	}
+}
@@ -0,0 +1,76 @@
+package agents
+
+import (
+	"context"
+	"log"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+	"github.com/yourusername/draft-forge/internal/suggestions"
+)
+
+// SuggestionStore records the edits a completed run proposes for review.
+type SuggestionStore interface {
+	InsertSuggestions(ctx context.Context, suggestions []models.Suggestion) error
+}
+
+// WithSuggestions stores the edits attached to a completed run's issues as open
+// suggestions.
+func WithSuggestions(store SuggestionStore) Option {
+	return func(s *Service) {
+		s.suggestions = store
+	}
+}
+
+// prepareEdits pins each issue's edit to the changed file the agent saw, filling in
+// its revision and diff, and returns the matching suggestions. Edits that do not fit
+// the file or change nothing are dropped.
+func prepareEdits(run models.AgentRun, result *models.RunResult, changed []manuscript.Document) []models.Suggestion {
+	files := make(map[string]string, len(changed))
+	for _, doc := range changed {
+		files[doc.Path] = doc.Content
+	}
+
+	var pending []models.Suggestion
+	for i := range result.Issues {
+		issue := &result.Issues[i]
+		if issue.Edit == nil {
+			continue
+		}
+		content, ok := files[issue.File]
+		original, inRange := suggestions.Target(content, issue.StartLine, issue.EndLine)
+		if !ok || !inRange || original == issue.Edit.Replacement {
+			issue.Edit = nil
+			continue
+		}
+		issue.Edit.Revision = suggestions.Revision(content)
+		issue.Edit.Diff = suggestions.Hunk(content, issue.StartLine, issue.EndLine, issue.Edit.Replacement)
+		pending = append(pending, models.Suggestion{
+			ProjectID:   run.ProjectID,
+			RunID:       run.ID,
+			IssueID:     issue.ID,
+			AgentType:   run.AgentType,
+			File:        issue.File,
+			Revision:    issue.Edit.Revision,
+			StartLine:   issue.StartLine,
+			EndLine:     issue.EndLine,
+			Original:    original,
+			Replacement: issue.Edit.Replacement,
+			Diff:        issue.Edit.Diff,
+			Message:     issue.Message,
+			State:       models.SuggestionOpen,
+		})
+	}
+	return pending
+}
+
+// recordSuggestions stores a completed run's suggestions. The run's results already
+// carry the edits, so a failure here is logged rather than failing the run.
+func (s *Service) recordSuggestions(ctx context.Context, run models.AgentRun, pending []models.Suggestion) {
+	if s.suggestions == nil || len(pending) == 0 {
+		return
+	}
+	if err := s.suggestions.InsertSuggestions(ctx, pending); err != nil {
+		log.Printf("agent run %d: record suggestions: %v", run.ID, err)
+	}
+}
@@ -0,0 +1,71 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+type memSuggestions struct {
+	inserted []models.Suggestion
+}
+
+func (m *memSuggestions) InsertSuggestions(_ context.Context, suggestions []models.Suggestion) error {
+	m.inserted = append(m.inserted, suggestions...)
+	return nil
+}
+
+func TestExecuteRunRecordsSuggestedEdits(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "chapters/01.md", "# One\n\nMara's blue eyes caught the light.\n")
+
+	provider := NewFakeProvider(CompletionResponse{Content: `{"summary": "One fix.", "issues": [
+		{"severity": "warning", "category": "continuity", "message": "Mara's eyes are green.", "file": "chapters/01.md", "start_line": 3, "edit": {"replacement": "Mara's green eyes caught the light.", "diff": "ignored"}},
+		{"severity": "info", "category": "style", "message": "Unchanged.", "file": "chapters/01.md", "start_line": 1, "edit": {"replacement": "# One"}},
+		{"severity": "info", "category": "style", "message": "Out of range.", "file": "chapters/01.md", "start_line": 9, "edit": {"replacement": "x"}},
+		{"severity": "info", "category": "style", "message": "Unanchored.", "edit": {"replacement": "x"}}
+	]}`})
+	registry := NewRegistry()
+	_ = registry.Register(&promptAgent{name: "notes", trigger: "manual", requirements: ContextRequirements{ChangedFiles: true}, instructions: "Check the chapter."})
+	store := newMockStore()
+	recorded := &memSuggestions{}
+	svc := NewService(store, t.TempDir(),
+		WithRegistry(registry),
+		WithProvider(provider, "test/model"),
+		WithWorkspaces(stubWorkspaces{1: root}, nil),
+		WithSuggestions(recorded),
+	)
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "notes", FilesChanged: []string{"chapters/01.md"}}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	claimed, _ := svc.claimNextRun(ctx)
+	if err := svc.executeRun(ctx, claimed); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+
+	run := store.runs[claimed.ID]
+	var edits []*models.TextEdit
+	var issueID string
+	for _, issue := range run.Results.Issues {
+		if issue.Edit != nil {
+			edits = append(edits, issue.Edit)
+			issueID = issue.ID
+		}
+	}
+	if len(edits) != 1 || len(edits[0].Revision) != 64 || !strings.Contains(edits[0].Diff, "+Mara's green eyes caught the light.") {
+		t.Fatalf("expected one pinned edit, got %+v", edits)
+	}
+
+	if len(recorded.inserted) != 1 {
+		t.Fatalf("expected one suggestion, got %+v", recorded.inserted)
+	}
+	got := recorded.inserted[0]
+	if got.RunID != claimed.ID || got.IssueID != issueID || got.Original != "Mara's blue eyes caught the light." ||
+		got.State != models.SuggestionOpen || got.Diff != edits[0].Diff {
+		t.Fatalf("unexpected suggestion %+v", got)
+	}
+}
@@ -0,0 +1,558 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// TimelineArtifact is the file name of the event graph written alongside timeline runs.
+const TimelineArtifact = "timeline.json"
+
+var timelineInstructions = mustBuiltinPrompt("timeline")
+
+const timelineFormatInstructions = `Reply with a single JSON object and nothing else:
+{"summary": "<one paragraph>", "events": [{"line": <int>, "description": "<what happens>", "date": "<YYYY-MM-DD if stated>", "day": <story day number if stated>, "offset": {"amount": <number>, "unit": "hour|day|week|month|year", "direction": "after|before"}}], "issues": [{"severity": "info|warning|error", "category": "<short category>", "message": "<what is wrong>", "file": "<repo-relative path>", "start_line": <int>, "end_line": <int>, "excerpt": "<exact quoted text>", "suggestion": "<how to fix>", "confidence": <0..1>}]}
+Only include "offset" when the text places the event relative to the previous one.`
+
+// unitHours converts offset units to hours. Months and years are averaged.
+var unitHours = map[string]float64{
+	"hour":  1,
+	"day":   24,
+	"week":  24 * 7,
+	"month": 24 * 30,
+	"year":  24 * 365,
+}
+
+var numberWords = map[string]float64{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+}
+
+var (
+	offsetPattern   = regexp.MustCompile(`(?i)\b(a|an|one|two|three|four|five|six|seven|eight|nine|ten|eleven|twelve|\d+)\s+(hour|day|week|month|year)s?\s+(later|afterwards|after|earlier|before)\b`)
+	nextPattern     = regexp.MustCompile(`(?i)\bthe\s+(?:next|following)\s+(morning|day|evening|night|week|month|year)\b`)
+	sameDayPattern  = regexp.MustCompile(`(?i)\b(?:later that (?:day|morning|evening|night)|that (?:afternoon|evening|night))\b`)
+	dayPattern      = regexp.MustCompile(`(?i)^(?:#+\s*)?day\s+(\d+)\b`)
+	isoDatePattern  = regexp.MustCompile(`\b(\d{4}-\d{2}-\d{2})\b`)
+	longDatePattern = regexp.MustCompile(`\b(January|February|March|April|May|June|July|August|September|October|November|December)\s+(\d{1,2})(?:st|nd|rd|th)?,\s*(\d{4})\b`)
+	dmyDatePattern  = regexp.MustCompile(`\b(\d{1,2})(?:st|nd|rd|th)?\s+(January|February|March|April|May|June|July|August|September|October|November|December),?\s+(\d{4})\b`)
+)
+
+// timelineOffset places an event relative to the event narrated before it.
+type timelineOffset struct {
+	Amount    float64 `json:"amount"`
+	Unit      string  `json:"unit"`
+	Direction string  `json:"direction"`
+}
+
+func (o timelineOffset) hours() float64 {
+	h := o.Amount * unitHours[o.Unit]
+	if o.Direction == "before" {
+		return -h
+	}
+	return h
+}
+
+func (o timelineOffset) String() string {
+	unit := o.Unit
+	if o.Amount != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%s %s %s", strconv.FormatFloat(o.Amount, 'f', -1, 64), unit, o.Direction)
+}
+
+// timelineEvent is a story event with whatever time information the text gives for it.
+type timelineEvent struct {
+	ID          string          `json:"id"`
+	File        string          `json:"file"`
+	Line        int             `json:"line"`
+	Description string          `json:"description"`
+	Date        string          `json:"date,omitempty"`
+	EndDate     string          `json:"end_date,omitempty"`
+	Day         int             `json:"day,omitempty"`
+	Offset      *timelineOffset `json:"offset,omitempty"`
+	// Source is where the event came from: frontmatter, text or model.
+	Source string `json:"source"`
+}
+
+// position returns the event's explicit point in time on the date axis (hours since the
+// Unix epoch) or the story-day axis (hours since day 1).
+func (e timelineEvent) position() (string, float64, bool) {
+	if t, err := time.Parse(time.DateOnly, e.Date); err == nil {
+		return "date", float64(t.Unix()) / 3600, true
+	}
+	if e.Day > 0 {
+		return "day", float64(e.Day-1) * 24, true
+	}
+	return "", 0, false
+}
+
+// timelineNode is an event in the artifact with its resolved place in the chronology.
+type timelineNode struct {
+	timelineEvent
+	Axis     string `json:"axis,omitempty"`
+	Resolved string `json:"resolved,omitempty"`
+	Inferred bool   `json:"inferred,omitempty"`
+}
+
+// timelineEdge links consecutive events in the graph. Relative edges carry the stated
+// offset and, when both ends are placed in time, the elapsed time between them.
+type timelineEdge struct {
+	From         string   `json:"from"`
+	To           string   `json:"to"`
+	Relation     string   `json:"relation"`
+	StatedHours  *float64 `json:"stated_hours,omitempty"`
+	ElapsedHours *float64 `json:"elapsed_hours,omitempty"`
+	Conflict     string   `json:"conflict,omitempty"`
+}
+
+type timelineGraph struct {
+	RunID  int64          `json:"run_id"`
+	Events []timelineNode `json:"events"`
+	Edges  []timelineEdge `json:"edges"`
+}
+
+// timelineAgent (TimelineBot) extracts story events from chapter frontmatter, explicit
+// dates and relative time phrases (plus a model pass when a provider is configured),
+// links them into an event graph and flags ordering contradictions and durations that do
+// not fit the stated dates.
+type timelineAgent struct{}
+
+func (timelineAgent) Name() string           { return "timeline" }
+func (timelineAgent) DefaultTrigger() string { return "scheduled" }
+func (timelineAgent) ContextRequirements() ContextRequirements {
+	return ContextRequirements{ChangedFiles: true, StoryBible: true}
+}
+
+func (timelineAgent) CacheVersion() string { return "timeline/1" }
+
+func (timelineAgent) DecodeData(raw json.RawMessage) (any, error) {
+	return decodeData[[]timelineEvent](raw)
+}
+
+func (a timelineAgent) Run(ctx context.Context, input Input) (Result, error) {
+	if input.Chunk == nil {
+		return Result{Summary: "No chapters to analyse."}, nil
+	}
+
+	events, issues := extractEvents(*input.Chunk)
+	result := Result{Issues: issues}
+
+	if input.Provider != nil {
+		instructions, err := input.instructions(timelineInstructions)
+		if err != nil {
+			return Result{}, err
+		}
+		content, usage, err := completeWithContext(ctx, input, a.Name(), instructions+"\n\n"+timelineFormatInstructions, "")
+		if err != nil {
+			return Result{}, err
+		}
+		result.Usage = usage
+		if parsed, err := parseModelResult(content); err == nil {
+			result.Summary = parsed.Summary
+			result.Issues = append(result.Issues, parsed.Issues...)
+			events = mergeModelEvents(events, parseModelEvents(content, *input.Chunk))
+		} else {
+			result.Summary = strings.TrimSpace(content)
+		}
+	}
+
+	result.Data = events
+	return result, nil
+}
+
+// Reduce orders the events from every chunk, resolves them against each other and
+// writes the event graph as the timeline artifact.
+func (a timelineAgent) Reduce(_ context.Context, input Input, results []Result) (Result, error) {
+	var merged Result
+	var events []timelineEvent
+	var modelSummaries []string
+	for _, r := range results {
+		if e, ok := r.Data.([]timelineEvent); ok {
+			events = append(events, e...)
+		}
+		merged.Issues = append(merged.Issues, r.Issues...)
+		merged.Usage.PromptTokens += r.Usage.PromptTokens
+		merged.Usage.CompletionTokens += r.Usage.CompletionTokens
+		merged.Usage.TotalTokens += r.Usage.TotalTokens
+		if s := strings.TrimSpace(r.Summary); s != "" {
+			modelSummaries = append(modelSummaries, s)
+		}
+	}
+
+	events = orderEvents(events)
+	graph, issues := resolveTimeline(events)
+	graph.RunID = input.Run.ID
+	merged.Issues = dedupeIssues(append(merged.Issues, issues...))
+
+	data, err := json.MarshalIndent(graph, "", "  ")
+	if err != nil {
+		return Result{}, fmt.Errorf("marshal timeline: %w", err)
+	}
+	merged.Artifacts = map[string][]byte{TimelineArtifact: data}
+
+	dated := 0
+	for _, node := range graph.Events {
+		if node.Resolved != "" {
+			dated++
+		}
+	}
+	merged.Metrics = map[string]any{
+		"events":         len(graph.Events),
+		"placed_events":  dated,
+		"contradictions": len(issues),
+	}
+	merged.Summary = fmt.Sprintf("Extracted %d events (%d placed in time); %d chronology issues.", len(graph.Events), dated, len(issues))
+	if len(modelSummaries) > 0 {
+		merged.Summary += "\n\n" + strings.Join(modelSummaries, "\n\n")
+	}
+	return merged, nil
+}
+
+// extractEvents finds time markers in a chunk: frontmatter date/day hints, "Day N"
+// headings, explicit dates and relative phrases in narration. Lines repeated from the
+// previous chunk's overlap are skipped. Invalid frontmatter is reported, not fatal.
+//
+// When a chapter opens with a relative phrase ("Two days later, ..."), the frontmatter
+// date is folded into that event so the phrase is checked against the previous chapter.
+func extractEvents(chunk manuscript.Chunk) ([]timelineEvent, []models.Issue) {
+	var events []timelineEvent
+	var issues []models.Issue
+	lines := strings.Split(chunk.Content, "\n")
+
+	skip := 0
+	if chunk.StartLine == 1 {
+		fields, end, err := manuscript.ParseFrontmatter(chunk.Content)
+		switch {
+		case err != nil:
+			issues = append(issues, models.Issue{
+				Severity:   models.SeverityWarning,
+				Category:   "frontmatter",
+				Message:    fmt.Sprintf("Could not read chapter frontmatter: %v", err),
+				File:       chunk.Path,
+				StartLine:  1,
+				Confidence: 1,
+			})
+		case fields != nil:
+			skip = end
+			if event, ok := frontmatterEvent(chunk.Path, fields); ok {
+				events = append(events, event)
+			}
+		}
+	}
+
+	inFence, opening := false, len(events) == 1
+	for i, raw := range lines {
+		trimmed := strings.TrimSpace(raw)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || i < skip || i < chunk.OverlapLines || trimmed == "" || strings.HasPrefix(trimmed, "<!--") {
+			continue
+		}
+
+		event := timelineEvent{File: chunk.Path, Line: chunk.StartLine + i, Description: excerpt(trimmed), Source: "text"}
+		if m := dayPattern.FindStringSubmatch(trimmed); m != nil {
+			event.Day, _ = strconv.Atoi(m[1])
+		}
+		narration := trimmed
+		if !strings.HasPrefix(trimmed, "#") {
+			narration, _ = stripDialogue(trimmed)
+		}
+		event.Date = findDate(narration)
+		event.Offset = findOffset(narration)
+
+		if opening && !strings.HasPrefix(trimmed, "#") {
+			opening = false
+			if _, _, explicit := event.position(); event.Offset != nil && !explicit && events[0].EndDate == "" {
+				event.Date, event.Day = events[0].Date, events[0].Day
+				events = events[:0]
+			}
+		}
+
+		if event.Day > 0 || event.Date != "" || event.Offset != nil {
+			events = append(events, event)
+		}
+	}
+	return events, issues
+}
+
+// frontmatterEvent reads date, end_date and day hints from chapter frontmatter. They may
+// sit at the top level or under a "timeline" key.
+func frontmatterEvent(path string, fields map[string]any) (timelineEvent, bool) {
+	if nested, ok := fields["timeline"].(map[string]any); ok {
+		fields = nested
+	}
+	event := timelineEvent{File: path, Line: 1, Description: "Chapter frontmatter", Source: "frontmatter"}
+	if title, ok := fields["title"].(string); ok && title != "" {
+		event.Description = title
+	}
+	event.Date = frontmatterDate(fields["date"])
+	event.EndDate = frontmatterDate(fields["end_date"])
+	if day, ok := fields["day"].(int); ok && day > 0 {
+		event.Day = day
+	}
+	return event, event.Date != "" || event.Day > 0
+}
+
+func frontmatterDate(value any) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.DateOnly)
+	case string:
+		if _, err := time.Parse(time.DateOnly, v); err == nil {
+			return v
+		}
+	}
+	return ""
+}
+
+// findDate returns the first fully specified date in text as YYYY-MM-DD.
+func findDate(text string) string {
+	if m := isoDatePattern.FindStringSubmatch(text); m != nil {
+		if _, err := time.Parse(time.DateOnly, m[1]); err == nil {
+			return m[1]
+		}
+	}
+	for _, candidate := range []struct {
+		pattern *regexp.Regexp
+		layout  func(m []string) string
+	}{
+		{longDatePattern, func(m []string) string { return m[1] + " " + m[2] + " " + m[3] }},
+		{dmyDatePattern, func(m []string) string { return m[2] + " " + m[1] + " " + m[3] }},
+	} {
+		if m := candidate.pattern.FindStringSubmatch(text); m != nil {
+			if t, err := time.Parse("January 2 2006", candidate.layout(m)); err == nil {
+				return t.Format(time.DateOnly)
+			}
+		}
+	}
+	return ""
+}
+
+// findOffset returns the first relative time phrase in text.
+func findOffset(text string) *timelineOffset {
+	if m := offsetPattern.FindStringSubmatch(text); m != nil {
+		amount, ok := numberWords[strings.ToLower(m[1])]
+		if !ok {
+			amount, _ = strconv.ParseFloat(m[1], 64)
+		}
+		direction := "after"
+		switch strings.ToLower(m[3]) {
+		case "earlier", "before":
+			direction = "before"
+		}
+		return &timelineOffset{Amount: amount, Unit: strings.ToLower(m[2]), Direction: direction}
+	}
+	if m := nextPattern.FindStringSubmatch(text); m != nil {
+		unit := strings.ToLower(m[1])
+		switch unit {
+		case "morning", "evening", "night":
+			unit = "day"
+		}
+		return &timelineOffset{Amount: 1, Unit: unit, Direction: "after"}
+	}
+	if sameDayPattern.MatchString(text) {
+		return &timelineOffset{Amount: 0, Unit: "day", Direction: "after"}
+	}
+	return nil
+}
+
+// parseModelEvents reads the events list from a model reply, keeping only events that
+// fall on non-overlap lines of the chunk and normalising their time fields.
+func parseModelEvents(content string, chunk manuscript.Chunk) []timelineEvent {
+	var reply struct {
+		Events []timelineEvent `json:"events"`
+	}
+	if err := json.Unmarshal([]byte(stripCodeFence(content)), &reply); err != nil {
+		return nil
+	}
+
+	var events []timelineEvent
+	for _, e := range reply.Events {
+		if e.Line < chunk.StartLine+chunk.OverlapLines || e.Line > chunk.EndLine {
+			continue
+		}
+		e.ID, e.File, e.Source, e.EndDate = "", chunk.Path, "model", ""
+		e.Description = excerpt(e.Description)
+		if _, err := time.Parse(time.DateOnly, e.Date); err != nil {
+			e.Date = ""
+		}
+		if e.Day < 0 {
+			e.Day = 0
+		}
+		if e.Offset != nil {
+			e.Offset.Unit = strings.TrimSuffix(strings.ToLower(e.Offset.Unit), "s")
+			if _, ok := unitHours[e.Offset.Unit]; !ok || e.Offset.Amount < 0 {
+				e.Offset = nil
+			} else if e.Offset.Direction != "before" {
+				e.Offset.Direction = "after"
+			}
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+// mergeModelEvents adds model events on lines the deterministic pass found nothing on.
+func mergeModelEvents(events, modelEvents []timelineEvent) []timelineEvent {
+	seen := map[int]bool{}
+	for _, e := range events {
+		seen[e.Line] = true
+	}
+	for _, e := range modelEvents {
+		if !seen[e.Line] {
+			seen[e.Line] = true
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// orderEvents sorts events into narrative order and numbers them. Chapters are ordered by
+// path, as manuscript.ListChapters lists them, never by the order a run request named its
+// files in.
+func orderEvents(events []timelineEvent) []timelineEvent {
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+
+	for i := range events {
+		events[i].ID = fmt.Sprintf("e%d", i+1)
+	}
+	return events
+}
+
+// resolveTimeline places each event in time and links it to the event before it. An
+// event's time is explicit (a date or story day) or inferred from its relative offset to
+// the last placed event. Explicit times are checked against the stated offset: moving the
+// wrong way is an ordering contradiction, a gap that does not match is a duration issue.
+func resolveTimeline(events []timelineEvent) (timelineGraph, []models.Issue) {
+	graph := timelineGraph{Events: make([]timelineNode, 0, len(events)), Edges: []timelineEdge{}}
+	var issues []models.Issue
+
+	anchorIndex, anchorHours := -1, 0.0
+	for i, e := range events {
+		var anchor *timelineNode
+		if anchorIndex >= 0 {
+			anchor = &graph.Events[anchorIndex]
+		}
+		node := timelineNode{timelineEvent: e}
+		axis, hours, explicit := e.position()
+
+		if e.EndDate != "" && e.Date != "" && e.EndDate < e.Date {
+			issues = append(issues, timelineIssue(e, models.SeverityError, "duration",
+				fmt.Sprintf("Chapter ends on %s, before it starts on %s.", e.EndDate, e.Date), 1))
+		}
+
+		if i > 0 {
+			edge := timelineEdge{From: graph.Events[i-1].ID, To: e.ID, Relation: "sequence"}
+			if e.Offset != nil && anchor != nil {
+				stated := e.Offset.hours()
+				edge.From, edge.Relation, edge.StatedHours = anchor.ID, e.Offset.Direction, &stated
+				if explicit && axis == anchor.Axis {
+					elapsed := hours - anchorHours
+					edge.ElapsedHours = &elapsed
+					if conflict, issue, ok := checkOffset(e, *anchor, stated, elapsed); ok {
+						edge.Conflict = conflict
+						issues = append(issues, issue)
+					}
+				}
+			} else if explicit && anchor != nil && axis == anchor.Axis && hours < anchorHours {
+				edge.Conflict = "backwards"
+				issues = append(issues, timelineIssue(e, models.SeverityInfo, "chronology",
+					fmt.Sprintf("Timeline moves back from %s to %s with no transition; check this is an intended flashback.", anchor.Resolved, describePosition(axis, hours)), 0.3))
+			}
+			graph.Edges = append(graph.Edges, edge)
+		}
+
+		switch {
+		case explicit:
+			node.Axis, node.Resolved = axis, describePosition(axis, hours)
+		case e.Offset != nil && anchor != nil:
+			axis, hours = anchor.Axis, anchorHours+e.Offset.hours()
+			node.Axis, node.Resolved, node.Inferred = axis, describePosition(axis, hours), true
+		}
+
+		graph.Events = append(graph.Events, node)
+		if node.Axis != "" {
+			anchorIndex, anchorHours = len(graph.Events)-1, hours
+		}
+	}
+	return graph, issues
+}
+
+// checkOffset compares a stated offset with the elapsed time between two placed events.
+// Dates are day-granular, so gaps within a day (or a quarter of the stated offset) pass.
+func checkOffset(e timelineEvent, anchor timelineNode, stated, elapsed float64) (string, models.Issue, bool) {
+	if (stated > 0 && elapsed < 0) || (stated < 0 && elapsed > 0) {
+		return "order", timelineIssue(e, models.SeverityError, "chronology",
+			fmt.Sprintf("Text says %s, but %s falls %s (%s).", e.Offset, describeAxisPosition(e), directionFrom(elapsed, anchor.Resolved), anchor.Description), 0.8), true
+	}
+	tolerance := max(24, 0.25*abs(stated))
+	if abs(elapsed-stated) > tolerance {
+		return "duration", timelineIssue(e, models.SeverityWarning, "duration",
+			fmt.Sprintf("Text says %s, but %s has passed since %s (%s).", e.Offset, formatHours(abs(elapsed)), anchor.Resolved, anchor.Description), 0.7), true
+	}
+	return "", models.Issue{}, false
+}
+
+func timelineIssue(e timelineEvent, severity models.Severity, category, message string, confidence float64) models.Issue {
+	return models.Issue{
+		Severity:   severity,
+		Category:   category,
+		Message:    message,
+		File:       e.File,
+		StartLine:  e.Line,
+		EndLine:    e.Line,
+		Excerpt:    e.Description,
+		Confidence: confidence,
+	}
+}
+
+func describePosition(axis string, hours float64) string {
+	if axis == "date" {
+		return time.Unix(int64(hours*3600), 0).UTC().Format(time.DateOnly)
+	}
+	return fmt.Sprintf("day %s", strconv.FormatFloat(hours/24+1, 'f', -1, 64))
+}
+
+func describeAxisPosition(e timelineEvent) string {
+	axis, hours, _ := e.position()
+	return describePosition(axis, hours)
+}
+
+func directionFrom(elapsed float64, anchor string) string {
+	if elapsed < 0 {
+		return "before " + anchor
+	}
+	return "after " + anchor
+}
+
+func formatHours(h float64) string {
+	if h < 24 {
+		return fmt.Sprintf("%s hours", strconv.FormatFloat(h, 'f', -1, 64))
+	}
+	return fmt.Sprintf("%s days", strconv.FormatFloat(h/24, 'f', -1, 64))
+}
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,171 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/manuscript"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+const timelineChapterOne = `---
+title: Arrival
+date: 2024-03-05
+---
+Mara reached the harbour at dusk.
+
+The next morning she boarded the ferry.
+`
+
+const timelineChapterTwo = `---
+date: 2024-03-04
+---
+Two days later, the ferry docked at Ilse.
+
+On March 20, 2024, Mara wrote home.
+
+Three days later, on 2024-04-10, the letter arrived.
+`
+
+func runTimeline(t *testing.T, input Input) Result {
+	t.Helper()
+	return runTimelineDocs(t, input,
+		manuscript.Document{Path: "chapters/01.md", Content: timelineChapterOne},
+		manuscript.Document{Path: "chapters/02.md", Content: timelineChapterTwo},
+	)
+}
+
+// runTimelineDocs runs the agent over docs, naming them on the run in the given order.
+func runTimelineDocs(t *testing.T, input Input, docs ...manuscript.Document) Result {
+	t.Helper()
+	agent := timelineAgent{}
+	input.Files = nil
+	for _, doc := range docs {
+		input.Files = append(input.Files, doc.Path)
+	}
+	chunks := manuscript.ChunkDocuments(docs, manuscript.ChunkOptions{})
+	var results []Result
+	for i := range chunks {
+		input.Chunk = &chunks[i]
+		r, err := agent.Run(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+		results = append(results, r)
+	}
+	merged, err := agent.Reduce(context.Background(), input, results)
+	if err != nil {
+		t.Fatalf("Reduce returned error: %v", err)
+	}
+	return merged
+}
+
+func TestTimelineAgentFlagsContradictions(t *testing.T) {
+	result := runTimeline(t, Input{})
+
+	byCategory := issuesByCategory(result.Issues)
+	order := byCategory["chronology"]
+	if len(order) != 1 || order[0].File != "chapters/02.md" || order[0].StartLine != 4 || order[0].Severity != models.SeverityError {
+		t.Fatalf("expected ordering contradiction at chapters/02.md:4, got %+v", order)
+	}
+	duration := byCategory["duration"]
+	if len(duration) != 1 || duration[0].StartLine != 8 || !strings.Contains(duration[0].Message, "21 days") {
+		t.Fatalf("expected duration mismatch at line 8, got %+v", duration)
+	}
+
+	var graph timelineGraph
+	if err := json.Unmarshal(result.Artifacts[TimelineArtifact], &graph); err != nil {
+		t.Fatalf("decode timeline artifact: %v", err)
+	}
+	if len(graph.Events) != 5 || len(graph.Edges) != 4 {
+		t.Fatalf("expected 5 events and 4 edges, got %+v", graph)
+	}
+	ferry := graph.Events[1]
+	if ferry.Resolved != "2024-03-06" || !ferry.Inferred {
+		t.Fatalf("expected the next morning to be inferred as 2024-03-06, got %+v", ferry)
+	}
+	if docked := graph.Events[2]; docked.Date != "2024-03-04" || docked.Source != "text" {
+		t.Fatalf("expected chapter date folded into its opening phrase, got %+v", docked)
+	}
+	if edge := graph.Edges[1]; edge.Conflict != "order" || edge.From != "e2" || edge.To != "e3" {
+		t.Fatalf("expected conflicting edge e2->e3, got %+v", edge)
+	}
+	if result.Metrics["events"] != 5 {
+		t.Fatalf("expected event metrics, got %v", result.Metrics)
+	}
+}
+
+func TestTimelineAgentOrdersChaptersByPath(t *testing.T) {
+	inOrder := runTimeline(t, Input{})
+	reversed := runTimelineDocs(t, Input{},
+		manuscript.Document{Path: "chapters/02.md", Content: timelineChapterTwo},
+		manuscript.Document{Path: "chapters/01.md", Content: timelineChapterOne},
+	)
+
+	if len(reversed.Issues) != len(inOrder.Issues) {
+		t.Fatalf("expected the request's file order not to matter, got %+v", reversed.Issues)
+	}
+	for i := range inOrder.Issues {
+		if reversed.Issues[i].File != inOrder.Issues[i].File || reversed.Issues[i].StartLine != inOrder.Issues[i].StartLine || reversed.Issues[i].Category != inOrder.Issues[i].Category {
+			t.Fatalf("expected the same issues, got %+v and %+v", reversed.Issues[i], inOrder.Issues[i])
+		}
+	}
+	var graph timelineGraph
+	if err := json.Unmarshal(reversed.Artifacts[TimelineArtifact], &graph); err != nil {
+		t.Fatalf("decode timeline artifact: %v", err)
+	}
+	if graph.Events[0].File != "chapters/01.md" {
+		t.Fatalf("expected chapter one's events first, got %+v", graph.Events[0])
+	}
+}
+
+func TestTimelineAgentMergesModelEvents(t *testing.T) {
+	provider := NewFakeProvider(
+		CompletionResponse{Content: `{"summary": "Arrival.", "events": [{"line": 5, "description": "Mara arrives", "day": 1}, {"line": 7, "description": "Boards ferry"}], "issues": []}`},
+		CompletionResponse{Content: `not json`},
+	)
+
+	result := runTimeline(t, Input{Provider: provider, Model: "test/model"})
+
+	var graph timelineGraph
+	if err := json.Unmarshal(result.Artifacts[TimelineArtifact], &graph); err != nil {
+		t.Fatalf("decode timeline artifact: %v", err)
+	}
+	if len(graph.Events) != 6 || graph.Events[1].Source != "model" || graph.Events[2].Source != "text" {
+		t.Fatalf("expected model event on line 5 and text event kept on line 7, got %+v", graph.Events)
+	}
+	if !strings.Contains(result.Summary, "Arrival.") || !strings.Contains(result.Summary, "not json") {
+		t.Fatalf("expected model summaries, got %q", result.Summary)
+	}
+}
+
+func TestExecuteRunWritesTimelineArtifact(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "chapters/01.md", timelineChapterOne)
+	writeFile(t, root, "chapters/02.md", timelineChapterTwo)
+
+	artifactDir := t.TempDir()
+	store := newMockStore()
+	svc := NewService(store, artifactDir, WithWorkspaces(stubWorkspaces{1: root}, nil))
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "timeline", FilesChanged: []string{"chapters/01.md", "chapters/02.md"}}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	claimed, _ := svc.claimNextRun(ctx)
+	if err := svc.executeRun(ctx, claimed); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+
+	run, _ := store.GetRun(ctx, claimed.ID)
+	if run.Results == nil || len(run.Results.Artifacts) != 1 || run.Results.Artifacts[0] != "run-1/timeline.json" {
+		t.Fatalf("expected timeline artifact to be listed on the run, got %+v", run.Results)
+	}
+	if _, err := os.Stat(filepath.Join(artifactDir, "run-1", TimelineArtifact)); err != nil {
+		t.Fatalf("expected timeline artifact on disk: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// UsageStore records every model call and aggregates usage for reporting.
+type UsageStore interface {
+	RecordUsage(ctx context.Context, entry models.UsageEntry) error
+	UsageReport(ctx context.Context, filter models.UsageFilter) (models.UsageReport, error)
+}
+
+// ModelPrice is a model's price in US dollars per million tokens.
+type ModelPrice struct {
+	Prompt     float64 `json:"prompt"`
+	Completion float64 `json:"completion"`
+}
+
+// PriceTable maps OpenRouter model IDs to their prices.
+type PriceTable map[string]ModelPrice
+
+// DefaultPriceTable covers the models the built-in configuration uses. Override it with
+// LoadPriceTable when prices change or other models are routed.
+var DefaultPriceTable = PriceTable{
+	"openai/gpt-4o-mini":          {Prompt: 0.15, Completion: 0.60},
+	"openai/gpt-4o":               {Prompt: 2.50, Completion: 10.00},
+	"anthropic/claude-3.5-sonnet": {Prompt: 3.00, Completion: 15.00},
+	"anthropic/claude-3-haiku":    {Prompt: 0.25, Completion: 1.25},
+}
+
+// LoadPriceTable reads a JSON object of model ID to {"prompt": x, "completion": y}
+// prices in dollars per million tokens.
+func LoadPriceTable(path string) (PriceTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read price table: %w", err)
+	}
+	var table PriceTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("decode price table: %w", err)
+	}
+	return table, nil
+}
+
+// CostCents prices a call's usage; models missing from the table cost nothing.
+func (t PriceTable) CostCents(model string, usage Usage) float64 {
+	price, ok := t[model]
+	if !ok {
+		return 0
+	}
+	dollars := (float64(usage.PromptTokens)*price.Prompt + float64(usage.CompletionTokens)*price.Completion) / 1_000_000
+	return dollars * 100
+}
+
+// WithUsage records every provider call, priced with prices, in store.
+func WithUsage(store UsageStore, prices PriceTable) Option {
+	return func(s *Service) {
+		s.usage = store
+		s.prices = prices
+	}
+}
+
+// UsageReport aggregates a user's model usage per project and per agent.
+func (s *Service) UsageReport(ctx context.Context, filter models.UsageFilter) (models.UsageReport, error) {
+	if s.usage == nil {
+		return models.UsageReport{ByProject: []models.UsageGroup{}, ByAgent: []models.UsageGroup{}}, nil
+	}
+	return s.usage.UsageReport(ctx, filter)
+}
+
+// meteredProvider records each call it forwards, successful or not, against the run.
+type meteredProvider struct {
+	provider Provider
+	store    UsageStore
+	prices   PriceTable
+	run      models.AgentRun
+}
+
+func (p meteredProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	resp, err := p.provider.Complete(ctx, req)
+
+	entry := models.UsageEntry{
+		RunID:            p.run.ID,
+		ProjectID:        p.run.ProjectID,
+		AgentType:        p.run.AgentType,
+		Model:            req.Model,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		CostCents:        p.prices.CostCents(req.Model, resp.Usage),
+		Status:           models.UsageSuccess,
+	}
+	if err != nil {
+		entry.Status = models.UsageFailed
+		entry.Error = err.Error()
+	}
+	// Record even when the run was cancelled mid-call.
+	if recordErr := p.store.RecordUsage(context.WithoutCancel(ctx), entry); recordErr != nil {
+		log.Printf("agent usage: record call for run %d: %v", p.run.ID, recordErr)
+	}
+	return resp, err
+}
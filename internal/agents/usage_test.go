@@ -0,0 +1,75 @@
+package agents
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+type memUsage struct {
+	mu      sync.Mutex
+	entries []models.UsageEntry
+}
+
+func (m *memUsage) RecordUsage(_ context.Context, entry models.UsageEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *memUsage) UsageReport(_ context.Context, _ models.UsageFilter) (models.UsageReport, error) {
+	return models.UsageReport{}, nil
+}
+
+func TestPriceTableCostCents(t *testing.T) {
+	cost := DefaultPriceTable.CostCents("openai/gpt-4o-mini", Usage{PromptTokens: 1_000_000, CompletionTokens: 100_000})
+	if math.Abs(cost-21) > 1e-9 {
+		t.Fatalf("expected 21 cents, got %v", cost)
+	}
+	if cost := DefaultPriceTable.CostCents("unknown/model", Usage{PromptTokens: 1000}); cost != 0 {
+		t.Fatalf("expected unknown models to be free, got %v", cost)
+	}
+}
+
+func TestExecuteRunRecordsEveryModelCall(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, AgentsConfigPath, "agents:\n  notes:\n    model: primary/model\n    fallback_models: [openai/gpt-4o-mini]\n")
+
+	fake := NewFakeProvider(CompletionResponse{
+		Content: "Nothing to report.",
+		Usage:   Usage{PromptTokens: 1000, CompletionTokens: 200, TotalTokens: 1200},
+	})
+	registry := NewRegistry()
+	_ = registry.Register(&promptAgent{name: "notes", trigger: "manual", instructions: "Summarise the changes."})
+	usage := &memUsage{}
+	svc := NewService(newMockStore(), t.TempDir(),
+		WithRegistry(registry),
+		WithProvider(failingProvider{failModels: map[string]bool{"primary/model": true}, fake: fake}, "test/model"),
+		WithWorkspaces(stubWorkspaces{1: root}, nil),
+		WithUsage(usage, DefaultPriceTable),
+	)
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "notes"}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	claimed, _ := svc.claimNextRun(ctx)
+	if err := svc.executeRun(ctx, claimed); err != nil {
+		t.Fatalf("executeRun returned error: %v", err)
+	}
+
+	if len(usage.entries) != 2 {
+		t.Fatalf("expected the failed and fallback calls to be recorded, got %+v", usage.entries)
+	}
+	failed, ok := usage.entries[0], usage.entries[1]
+	if failed.Status != models.UsageFailed || failed.Model != "primary/model" || failed.Error == "" {
+		t.Fatalf("unexpected failed entry %+v", failed)
+	}
+	if ok.Status != models.UsageSuccess || ok.RunID != claimed.ID || ok.AgentType != "notes" || ok.PromptTokens != 1000 || math.Abs(ok.CostCents-0.027) > 1e-9 {
+		t.Fatalf("unexpected success entry %+v", ok)
+	}
+}
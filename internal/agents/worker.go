@@ -0,0 +1,143 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+const (
+	defaultWorkerConcurrency  = 2
+	defaultWorkerPollInterval = 2 * time.Second
+	// A run whose heartbeat is older than staleRunTimeout is assumed orphaned by a
+	// crashed instance and is reclaimed.
+	heartbeatInterval = 30 * time.Second
+	staleRunTimeout   = 3 * time.Minute
+)
+
+// Worker claims queued agent runs and executes them in the background with a bounded concurrency.
+// Several workers (in one or many API instances) can share the same queue safely because claiming
+// is an atomic store operation.
+type Worker struct {
+	service      *Service
+	concurrency  int
+	pollInterval time.Duration
+	heartbeat    time.Duration
+	staleAfter   time.Duration
+}
+
+// NewWorker builds a worker; non-positive values fall back to defaults.
+func NewWorker(service *Service, concurrency int, pollInterval time.Duration) *Worker {
+	if concurrency <= 0 {
+		concurrency = defaultWorkerConcurrency
+	}
+	if pollInterval <= 0 {
+		pollInterval = defaultWorkerPollInterval
+	}
+	return &Worker{
+		service:      service,
+		concurrency:  concurrency,
+		pollInterval: pollInterval,
+		heartbeat:    heartbeatInterval,
+		staleAfter:   staleRunTimeout,
+	}
+}
+
+// Run processes queued runs until ctx is cancelled, then waits for in-flight runs to finish.
+// In-flight runs are detached from ctx so a shutdown never leaves a run half recorded.
+// Runs orphaned by another instance are reclaimed at start and then periodically.
+func (w *Worker) Run(ctx context.Context) {
+	slots := make(chan struct{}, w.concurrency)
+	released := make(chan struct{}, w.concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	sweep := time.NewTicker(w.heartbeat)
+	defer sweep.Stop()
+
+	w.reclaim(ctx)
+	for {
+		w.fill(ctx, slots, released, &wg)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-sweep.C:
+			w.reclaim(ctx)
+		case <-w.service.wake:
+		case <-released:
+		}
+	}
+}
+
+// reclaim fails runs whose worker stopped sending heartbeats.
+func (w *Worker) reclaim(ctx context.Context) {
+	if err := w.service.reclaimStaleRuns(ctx, w.staleAfter); err != nil && ctx.Err() == nil {
+		log.Printf("agent worker: reclaim stale runs: %v", err)
+	}
+}
+
+// keepAlive sends heartbeats for a run until the returned stop function is called.
+func (w *Worker) keepAlive(ctx context.Context, runID int64) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(w.heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := w.service.heartbeat(ctx, runID); err != nil {
+					log.Printf("agent worker: heartbeat run %d: %v", runID, err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// fill claims runs until every slot is busy or the queue is empty.
+func (w *Worker) fill(ctx context.Context, slots, released chan struct{}, wg *sync.WaitGroup) {
+	for ctx.Err() == nil {
+		select {
+		case slots <- struct{}{}:
+		default:
+			return
+		}
+
+		run, err := w.service.claimNextRun(ctx)
+		if err != nil {
+			<-slots
+			if !errors.Is(err, models.ErrNotFound) && ctx.Err() == nil {
+				log.Printf("agent worker: claim run: %v", err)
+			}
+			return
+		}
+
+		wg.Add(1)
+		go func(run models.AgentRun) {
+			defer wg.Done()
+			defer func() {
+				<-slots
+				select {
+				case released <- struct{}{}:
+				default:
+				}
+			}()
+
+			runCtx := context.WithoutCancel(ctx)
+			defer w.keepAlive(runCtx, run.ID)()
+			if err := w.service.executeRun(runCtx, run); err != nil {
+				log.Printf("agent worker: run %d (%s): %v", run.ID, run.AgentType, err)
+			}
+		}(run)
+	}
+}
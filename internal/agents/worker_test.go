@@ -0,0 +1,113 @@
+package agents
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWorkerProcessesQueuedRuns(t *testing.T) {
+	store := newMockStore()
+	svc := NewService(store, t.TempDir())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, agentType := range []string{"continuity", "style", "timeline"} {
+		if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: agentType}); err != nil {
+			t.Fatalf("QueueRun returned error: %v", err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		NewWorker(svc, 2, 10*time.Millisecond).Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.After(2 * time.Second)
+	for {
+		runs, _ := store.ListRuns(ctx, 1)
+		completed := 0
+		for _, run := range runs {
+			if run.Status == "completed" {
+				completed++
+			}
+		}
+		if completed == len(runs) {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("timed out waiting for runs to complete: %+v", runs)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("worker did not stop after context cancellation")
+	}
+}
+
+func TestNewWorkerDefaults(t *testing.T) {
+	w := NewWorker(NewService(newMockStore(), t.TempDir()), 0, 0)
+	if w.concurrency != defaultWorkerConcurrency || w.pollInterval != defaultWorkerPollInterval {
+		t.Fatalf("expected defaults, got concurrency=%d poll=%s", w.concurrency, w.pollInterval)
+	}
+}
+
+func TestReclaimStaleRunsRetriesAbandonedRuns(t *testing.T) {
+	store := newMockStore()
+	svc := NewService(store, t.TempDir())
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for _, agentType := range []string{"continuity", "style"} {
+		if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: agentType}); err != nil {
+			t.Fatalf("QueueRun returned error: %v", err)
+		}
+	}
+	abandoned, _ := svc.claimNextRun(ctx)
+	live, _ := svc.claimNextRun(ctx)
+
+	now = now.Add(staleRunTimeout)
+	if err := svc.heartbeat(ctx, live.ID); err != nil {
+		t.Fatalf("heartbeat returned error: %v", err)
+	}
+	now = now.Add(time.Minute)
+	if err := svc.reclaimStaleRuns(ctx, staleRunTimeout); err != nil {
+		t.Fatalf("reclaimStaleRuns returned error: %v", err)
+	}
+
+	if run, _ := store.GetRun(ctx, abandoned.ID); run.Status != "queued" || run.Error != ErrRunAbandoned.Error() || run.NextAttemptAt == nil {
+		t.Fatalf("expected the abandoned run to be scheduled for a retry, got %+v", run)
+	}
+	if run, _ := store.GetRun(ctx, live.ID); run.Status != "running" {
+		t.Fatalf("expected the heartbeating run to be left running, got %+v", run)
+	}
+}
+
+func TestReclaimStaleRunsDeadLettersExhaustedRuns(t *testing.T) {
+	store := newMockStore()
+	svc := NewService(store, t.TempDir(), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := svc.QueueRun(ctx, RunRequest{ProjectID: 1, AgentType: "style"}); err != nil {
+		t.Fatalf("QueueRun returned error: %v", err)
+	}
+	abandoned, _ := svc.claimNextRun(ctx)
+	now = now.Add(time.Hour)
+	if err := svc.reclaimStaleRuns(ctx, staleRunTimeout); err != nil {
+		t.Fatalf("reclaimStaleRuns returned error: %v", err)
+	}
+
+	if run, _ := store.GetRun(ctx, abandoned.ID); run.Status != "dead_letter" {
+		t.Fatalf("expected the abandoned run to be dead-lettered, got %+v", run)
+	}
+}
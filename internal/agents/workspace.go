@@ -0,0 +1,38 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// WorkspaceResolver locates the working tree (local scaffold or checked-out repo) of a project.
+type WorkspaceResolver interface {
+	WorkspacePath(ctx context.Context, projectID int64) (string, error)
+}
+
+// ProjectLookup fetches a project by ID.
+type ProjectLookup interface {
+	GetProject(ctx context.Context, id int64) (models.Project, error)
+}
+
+// LocalWorkspaces resolves projects to Root/<slug>, the layout used by the local
+// scaffolder and by repo checkouts synced onto the API host.
+type LocalWorkspaces struct {
+	Root     string
+	Projects ProjectLookup
+}
+
+func NewLocalWorkspaces(root string, projects ProjectLookup) *LocalWorkspaces {
+	return &LocalWorkspaces{Root: root, Projects: projects}
+}
+
+func (w *LocalWorkspaces) WorkspacePath(ctx context.Context, projectID int64) (string, error) {
+	project, err := w.Projects.GetProject(ctx, projectID)
+	if err != nil {
+		return "", fmt.Errorf("get project: %w", err)
+	}
+	return filepath.Join(w.Root, project.Slug), nil
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourusername/draft-forge/internal/agents"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+const sseHeartbeatInterval = 15 * time.Second
+
+// RunEventSource provides the live run events streamed to clients.
+type RunEventSource interface {
+	Subscribe(filter func(models.RunEvent) bool) (<-chan models.RunEvent, func())
+}
+
+// AgentEventsHandler streams agent run lifecycle events over Server-Sent Events.
+type AgentEventsHandler struct {
+	service AgentService
+	events  RunEventSource
+}
+
+func NewAgentEventsHandler(service AgentService, events RunEventSource) *AgentEventsHandler {
+	return &AgentEventsHandler{service: service, events: events}
+}
+
+func (h *AgentEventsHandler) Register(app fiber.Router) {
+	app.Get("/projects/:projectID/agents/events", h.streamProject)
+	app.Get("/projects/:projectID/agents/runs/:runID/events", h.streamRun)
+}
+
+// streamProject streams events for every run of the project until the client disconnects.
+func (h *AgentEventsHandler) streamProject(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseInt(c.Params("projectID"), 10, 64)
+	if err != nil || projectID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid project id")
+	}
+
+	events, unsubscribe := h.events.Subscribe(func(event models.RunEvent) bool {
+		return event.ProjectID == projectID
+	})
+	return h.stream(c, events, unsubscribe, nil, false)
+}
+
+// streamRun sends a snapshot of the run followed by its events, ending once the run
+// reaches a terminal state.
+func (h *AgentEventsHandler) streamRun(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseInt(c.Params("projectID"), 10, 64)
+	if err != nil || projectID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid project id")
+	}
+
+	runID, err := strconv.ParseInt(c.Params("runID"), 10, 64)
+	if err != nil || runID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid run id")
+	}
+
+	// Subscribe before reading the snapshot so no event falls between the two.
+	events, unsubscribe := h.events.Subscribe(func(event models.RunEvent) bool {
+		return event.RunID == runID
+	})
+
+	run, err := h.service.GetRun(c.Context(), runID)
+	if err != nil {
+		unsubscribe()
+		if errors.Is(err, agents.ErrProjectNotFound) || errors.Is(err, agents.ErrRunNotFound) || errors.Is(err, models.ErrNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "run not found")
+		}
+		return err
+	}
+	if run.ProjectID != projectID {
+		unsubscribe()
+		return fiber.NewError(fiber.StatusNotFound, "run not found")
+	}
+
+	return h.stream(c, events, unsubscribe, &run, true)
+}
+
+func (h *AgentEventsHandler) stream(c *fiber.Ctx, events <-chan models.RunEvent, unsubscribe func(), snapshot *models.AgentRun, untilTerminal bool) error {
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		if snapshot != nil {
+			if err := writeSSE(w, "snapshot", snapshot); err != nil {
+				return
+			}
+			if untilTerminal && terminalRunStatus(snapshot.Status) {
+				return
+			}
+		}
+
+		heartbeat := time.NewTicker(sseHeartbeatInterval)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if err := writeSSE(w, event.Type, event); err != nil {
+					return
+				}
+				if untilTerminal && event.Terminal() {
+					return
+				}
+			case <-heartbeat.C:
+				// A failed write means the client has gone away.
+				if _, err := w.WriteString(": ping\n\n"); err != nil {
+					return
+				}
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		}
+	})
+	return nil
+}
+
+func writeSSE(w *bufio.Writer, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func terminalRunStatus(status string) bool {
+	return models.RunEvent{Type: status}.Terminal()
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// stubEventSource replays events then closes the subscription.
+type stubEventSource struct {
+	events []models.RunEvent
+}
+
+func (s stubEventSource) Subscribe(filter func(models.RunEvent) bool) (<-chan models.RunEvent, func()) {
+	ch := make(chan models.RunEvent, len(s.events))
+	for _, event := range s.events {
+		if filter(event) {
+			ch <- event
+		}
+	}
+	close(ch)
+	return ch, func() {}
+}
+
+func TestStreamRunEvents(t *testing.T) {
+	app := fiber.New()
+	service := &stubAgentService{
+		getFunc: func(ctx context.Context, id int64) (models.AgentRun, error) {
+			return models.AgentRun{ID: id, ProjectID: 1, Status: "running"}, nil
+		},
+	}
+	NewAgentEventsHandler(service, stubEventSource{events: []models.RunEvent{
+		{Type: models.RunEventProgress, RunID: 5, ProjectID: 1, Progress: &models.RunProgress{ChunksTotal: 2, ChunksCompleted: 1}},
+		{Type: models.RunEventProgress, RunID: 6, ProjectID: 1},
+		{Type: models.RunEventCompleted, RunID: 5, ProjectID: 1},
+		{Type: models.RunEventQueued, RunID: 5, ProjectID: 1},
+	}}).Register(app)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/projects/1/agents/runs/5/events", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %q", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	stream := string(body)
+	if strings.Count(stream, "event: ") != 3 || !strings.HasPrefix(stream, "event: snapshot\n") {
+		t.Fatalf("expected snapshot, progress and completed events, got %q", stream)
+	}
+	if !strings.Contains(stream, `"chunks_completed":1`) || strings.LastIndex(stream, "event: ") != strings.Index(stream, "event: completed") {
+		t.Fatalf("expected the stream to end at the completed event, got %q", stream)
+	}
+}
+
+func TestStreamRunEventsFinishedRun(t *testing.T) {
+	app := fiber.New()
+	service := &stubAgentService{
+		getFunc: func(ctx context.Context, id int64) (models.AgentRun, error) {
+			return models.AgentRun{ID: id, ProjectID: 1, Status: "completed"}, nil
+		},
+	}
+	NewAgentEventsHandler(service, stubEventSource{}).Register(app)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/projects/2/agents/runs/5/events", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected status %d for another project's run, got %d", http.StatusNotFound, resp.StatusCode)
+	}
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/projects/1/agents/runs/5/events", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if strings.Count(string(body), "event: ") != 1 {
+		t.Fatalf("expected only a snapshot for a finished run, got %q", body)
+	}
+}
+
+func TestStreamProjectEvents(t *testing.T) {
+	app := fiber.New()
+	NewAgentEventsHandler(&stubAgentService{}, stubEventSource{events: []models.RunEvent{
+		{Type: models.RunEventQueued, RunID: 5, ProjectID: 1},
+		{Type: models.RunEventQueued, RunID: 6, ProjectID: 2},
+		{Type: models.RunEventCompleted, RunID: 5, ProjectID: 1},
+		{Type: models.RunEventRunning, RunID: 7, ProjectID: 1},
+	}}).Register(app)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/projects/1/agents/events", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if strings.Count(string(body), "event: ") != 3 || strings.Contains(string(body), `"run_id":6`) {
+		t.Fatalf("expected the project's three events, got %q", body)
+	}
+}
@@ -8,13 +8,19 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/yourusername/draft-forge/internal/agents"
+	"github.com/yourusername/draft-forge/internal/credits"
 	"github.com/yourusername/draft-forge/internal/models"
 )
 
 type AgentService interface {
 	QueueRun(ctx context.Context, req agents.RunRequest) (models.AgentRun, error)
+	EstimateRun(ctx context.Context, req agents.RunRequest) (agents.Estimate, error)
 	GetRun(ctx context.Context, id int64) (models.AgentRun, error)
 	ListRuns(ctx context.Context, projectID int64) ([]models.AgentRun, error)
+	ListDeadLetterRuns(ctx context.Context, projectID int64) ([]models.AgentRun, error)
+	RequeueRun(ctx context.Context, projectID, runID int64) (models.AgentRun, error)
+	CancelRun(ctx context.Context, projectID, runID int64) (models.AgentRun, error)
+	CompareRuns(ctx context.Context, projectID, baseID, headID int64) (models.RunComparison, error)
 }
 
 type AgentHandler struct {
@@ -27,14 +33,19 @@ func NewAgentHandler(service AgentService) *AgentHandler {
 
 func (h *AgentHandler) Register(app fiber.Router) {
 	app.Post("/projects/:projectID/agents/run", h.queueRun)
+	app.Get("/projects/:projectID/agents/runs/compare", h.compareRuns)
 	app.Get("/projects/:projectID/agents/runs/:runID", h.getRun)
 	app.Get("/projects/:projectID/agents/runs", h.listRuns)
+	app.Get("/projects/:projectID/agents/dead-letter", h.listDeadLetterRuns)
+	app.Post("/projects/:projectID/agents/runs/:runID/requeue", h.requeueRun)
+	app.Post("/projects/:projectID/agents/runs/:runID/cancel", h.cancelRun)
 }
 
 type queueRunRequest struct {
 	AgentType    string   `json:"agent_type"`
 	Trigger      string   `json:"trigger"`
 	FilesChanged []string `json:"files_changed"`
+	DryRun       bool     `json:"dry_run"`
 }
 
 func (h *AgentHandler) queueRun(c *fiber.Ctx) error {
@@ -48,21 +59,30 @@ func (h *AgentHandler) queueRun(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
 	}
 
-	run, err := h.service.QueueRun(c.Context(), agents.RunRequest{
+	runReq := agents.RunRequest{
 		ProjectID:    projectID,
 		AgentType:    req.AgentType,
 		Trigger:      req.Trigger,
 		FilesChanged: req.FilesChanged,
-	})
-	if err != nil {
-		switch {
-		case errors.Is(err, agents.ErrInvalidAgentType), errors.Is(err, agents.ErrInvalidTrigger):
-			return fiber.NewError(fiber.StatusBadRequest, err.Error())
-		case errors.Is(err, agents.ErrProjectNotFound):
-			return fiber.NewError(fiber.StatusNotFound, err.Error())
-		default:
-			return err
+	}
+
+	if req.DryRun {
+		est, err := h.service.EstimateRun(c.Context(), runReq)
+		if err != nil {
+			return queueRunError(err)
 		}
+		return c.JSON(fiber.Map{
+			"data": est,
+			"meta": fiber.Map{
+				"dry_run": true,
+				"message": "Estimate only; no agent run was queued",
+			},
+		})
+	}
+
+	run, err := h.service.QueueRun(c.Context(), runReq)
+	if err != nil {
+		return queueRunError(err)
 	}
 
 	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
@@ -73,6 +93,22 @@ func (h *AgentHandler) queueRun(c *fiber.Ctx) error {
 	})
 }
 
+// queueRunError maps run validation and reservation failures to HTTP errors.
+func queueRunError(err error) error {
+	switch {
+	case errors.Is(err, agents.ErrInvalidAgentType), errors.Is(err, agents.ErrInvalidTrigger):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, agents.ErrProjectNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, agents.ErrAgentDisabled), errors.Is(err, agents.ErrInvalidAgentConfig):
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, credits.ErrInsufficientCredits):
+		return fiber.NewError(fiber.StatusPaymentRequired, err.Error())
+	default:
+		return err
+	}
+}
+
 func (h *AgentHandler) getRun(c *fiber.Ctx) error {
 	projectID, err := strconv.ParseInt(c.Params("projectID"), 10, 64)
 	if err != nil || projectID <= 0 {
@@ -99,6 +135,45 @@ func (h *AgentHandler) getRun(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"data": run})
 }
 
+func (h *AgentHandler) compareRuns(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseInt(c.Params("projectID"), 10, 64)
+	if err != nil || projectID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid project id")
+	}
+
+	baseID, err := strconv.ParseInt(c.Query("base"), 10, 64)
+	if err != nil || baseID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid base run id")
+	}
+	headID, err := strconv.ParseInt(c.Query("head"), 10, 64)
+	if err != nil || headID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid head run id")
+	}
+
+	comparison, err := h.service.CompareRuns(c.Context(), projectID, baseID, headID)
+	if err != nil {
+		switch {
+		case errors.Is(err, agents.ErrRunNotFound):
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		case errors.Is(err, agents.ErrRunsNotComparable):
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		case errors.Is(err, agents.ErrRunNotCompleted):
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		default:
+			return err
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"data": comparison,
+		"meta": fiber.Map{
+			"resolved":  len(comparison.Resolved),
+			"new":       len(comparison.New),
+			"unchanged": len(comparison.Unchanged),
+		},
+	})
+}
+
 func (h *AgentHandler) listRuns(c *fiber.Ctx) error {
 	projectID, err := strconv.ParseInt(c.Params("projectID"), 10, 64)
 	if err != nil || projectID <= 0 {
@@ -110,8 +185,100 @@ func (h *AgentHandler) listRuns(c *fiber.Ctx) error {
 		return err
 	}
 
+	// ?editorial_round= narrows the list to the runs made during one EDITORIAL.md round.
+	if round := c.Query("editorial_round"); round != "" {
+		filtered := make([]models.AgentRun, 0, len(runs))
+		for _, run := range runs {
+			if run.EditorialRound == round {
+				filtered = append(filtered, run)
+			}
+		}
+		runs = filtered
+	}
+
 	return c.JSON(fiber.Map{
 		"data": runs,
 		"meta": fiber.Map{"count": len(runs)},
 	})
 }
+
+func (h *AgentHandler) listDeadLetterRuns(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseInt(c.Params("projectID"), 10, 64)
+	if err != nil || projectID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid project id")
+	}
+
+	runs, err := h.service.ListDeadLetterRuns(c.Context(), projectID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data": runs,
+		"meta": fiber.Map{"count": len(runs)},
+	})
+}
+
+func (h *AgentHandler) requeueRun(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseInt(c.Params("projectID"), 10, 64)
+	if err != nil || projectID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid project id")
+	}
+
+	runID, err := strconv.ParseInt(c.Params("runID"), 10, 64)
+	if err != nil || runID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid run id")
+	}
+
+	run, err := h.service.RequeueRun(c.Context(), projectID, runID)
+	if err != nil {
+		switch {
+		case errors.Is(err, agents.ErrRunNotFound):
+			return fiber.NewError(fiber.StatusNotFound, "run not found")
+		case errors.Is(err, agents.ErrRunNotDeadLettered):
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		case errors.Is(err, credits.ErrInsufficientCredits):
+			return fiber.NewError(fiber.StatusPaymentRequired, err.Error())
+		default:
+			return err
+		}
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"data": run,
+		"meta": fiber.Map{
+			"message": "Agent run requeued",
+		},
+	})
+}
+
+func (h *AgentHandler) cancelRun(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseInt(c.Params("projectID"), 10, 64)
+	if err != nil || projectID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid project id")
+	}
+
+	runID, err := strconv.ParseInt(c.Params("runID"), 10, 64)
+	if err != nil || runID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid run id")
+	}
+
+	run, err := h.service.CancelRun(c.Context(), projectID, runID)
+	if err != nil {
+		switch {
+		case errors.Is(err, agents.ErrRunNotFound):
+			return fiber.NewError(fiber.StatusNotFound, "run not found")
+		case errors.Is(err, agents.ErrRunNotActive):
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		default:
+			return err
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"data": run,
+		"meta": fiber.Map{
+			"message": "Agent run cancelled",
+		},
+	})
+}
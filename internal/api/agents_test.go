@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/yourusername/draft-forge/internal/agents"
+	"github.com/yourusername/draft-forge/internal/credits"
 	"github.com/yourusername/draft-forge/internal/models"
 )
 
@@ -131,10 +133,250 @@ func TestGetRunHandlerSuccess(t *testing.T) {
 	}
 }
 
+func TestQueueRunHandlerDisabledAgent(t *testing.T) {
+	app := fiber.New()
+	handler := NewAgentHandler(&stubAgentService{
+		queueFunc: func(ctx context.Context, req agents.RunRequest) (models.AgentRun, error) {
+			return models.AgentRun{}, fmt.Errorf("%w: style", agents.ErrAgentDisabled)
+		},
+	})
+	handler.Register(app)
+
+	body := []byte(`{"agent_type":"style"}`)
+	req := httptest.NewRequest(http.MethodPost, "/projects/1/agents/run", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, resp.StatusCode)
+	}
+}
+
+func TestQueueRunHandlerInsufficientCredits(t *testing.T) {
+	app := fiber.New()
+	handler := NewAgentHandler(&stubAgentService{
+		queueFunc: func(ctx context.Context, req agents.RunRequest) (models.AgentRun, error) {
+			return models.AgentRun{}, fmt.Errorf("reserve 12 credits: %w", credits.ErrInsufficientCredits)
+		},
+	})
+	handler.Register(app)
+
+	body := []byte(`{"agent_type":"style"}`)
+	req := httptest.NewRequest(http.MethodPost, "/projects/1/agents/run", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPaymentRequired {
+		t.Fatalf("expected status %d, got %d", http.StatusPaymentRequired, resp.StatusCode)
+	}
+}
+
+func TestQueueRunHandlerDryRun(t *testing.T) {
+	app := fiber.New()
+	handler := NewAgentHandler(&stubAgentService{
+		queueFunc: func(ctx context.Context, req agents.RunRequest) (models.AgentRun, error) {
+			t.Fatal("dry run must not queue a run")
+			return models.AgentRun{}, nil
+		},
+		estimateFunc: func(ctx context.Context, req agents.RunRequest) (agents.Estimate, error) {
+			return agents.Estimate{AgentType: req.AgentType, Model: "test/model", ChunkCount: 3, TotalTokens: 4200, Credits: 2}, nil
+		},
+	})
+	handler.Register(app)
+
+	body := []byte(`{"agent_type":"continuity","dry_run":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/projects/1/agents/run", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	var payload struct {
+		Data agents.Estimate `json:"data"`
+		Meta struct {
+			DryRun bool `json:"dry_run"`
+		} `json:"meta"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !payload.Meta.DryRun || payload.Data.ChunkCount != 3 || payload.Data.Credits != 2 || payload.Data.Model != "test/model" {
+		t.Fatalf("unexpected dry run payload: %+v", payload)
+	}
+}
+
+func TestListRunsHandlerFiltersByEditorialRound(t *testing.T) {
+	app := fiber.New()
+	handler := NewAgentHandler(&stubAgentService{
+		listFunc: func(ctx context.Context, projectID int64) ([]models.AgentRun, error) {
+			return []models.AgentRun{
+				{ID: 1, ProjectID: projectID, EditorialRound: "2024-03-01-pacing-pass"},
+				{ID: 2, ProjectID: projectID, EditorialRound: "2024-04-02-dialogue"},
+				{ID: 3, ProjectID: projectID},
+			}, nil
+		},
+	})
+	handler.Register(app)
+
+	req := httptest.NewRequest(http.MethodGet, "/projects/1/agents/runs?editorial_round=2024-03-01-pacing-pass", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Data []models.AgentRun `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload.Data) != 1 || payload.Data[0].ID != 1 {
+		t.Fatalf("expected only the pacing pass run, got %+v", payload.Data)
+	}
+}
+
+func TestListDeadLetterRunsHandler(t *testing.T) {
+	app := fiber.New()
+	handler := NewAgentHandler(&stubAgentService{
+		deadFunc: func(ctx context.Context, projectID int64) ([]models.AgentRun, error) {
+			return []models.AgentRun{{ID: 3, ProjectID: projectID, Status: "dead_letter", Attempts: 5}}, nil
+		},
+	})
+	handler.Register(app)
+
+	req := httptest.NewRequest(http.MethodGet, "/projects/1/agents/dead-letter", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Data []models.AgentRun `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload.Data) != 1 || payload.Data[0].Attempts != 5 {
+		t.Fatalf("unexpected dead letter payload: %+v", payload.Data)
+	}
+}
+
+func TestRequeueRunHandlerConflict(t *testing.T) {
+	app := fiber.New()
+	handler := NewAgentHandler(&stubAgentService{
+		requeueFunc: func(ctx context.Context, projectID, runID int64) (models.AgentRun, error) {
+			return models.AgentRun{}, agents.ErrRunNotDeadLettered
+		},
+	})
+	handler.Register(app)
+
+	req := httptest.NewRequest(http.MethodPost, "/projects/1/agents/runs/3/requeue", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, resp.StatusCode)
+	}
+}
+
+func TestCancelRunHandler(t *testing.T) {
+	app := fiber.New()
+	handler := NewAgentHandler(&stubAgentService{
+		cancelFunc: func(ctx context.Context, projectID, runID int64) (models.AgentRun, error) {
+			if runID == 4 {
+				return models.AgentRun{}, agents.ErrRunNotActive
+			}
+			return models.AgentRun{ID: runID, ProjectID: projectID, Status: "cancelled"}, nil
+		},
+	})
+	handler.Register(app)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/projects/1/agents/runs/3/cancel", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/projects/1/agents/runs/4/cancel", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, resp.StatusCode)
+	}
+}
+
+func TestCompareRunsHandler(t *testing.T) {
+	app := fiber.New()
+	handler := NewAgentHandler(&stubAgentService{
+		compareFunc: func(ctx context.Context, projectID, baseID, headID int64) (models.RunComparison, error) {
+			switch headID {
+			case 5:
+				return models.RunComparison{}, agents.ErrRunNotCompleted
+			case 6:
+				return models.RunComparison{}, agents.ErrRunNotFound
+			}
+			return models.RunComparison{BaseRunID: baseID, HeadRunID: headID, New: []models.Issue{{ID: "continuity-1"}}}, nil
+		},
+	})
+	handler.Register(app)
+
+	cases := []struct {
+		path   string
+		status int
+	}{
+		{"/projects/1/agents/runs/compare?base=3&head=4", http.StatusOK},
+		{"/projects/1/agents/runs/compare?base=3&head=5", http.StatusConflict},
+		{"/projects/1/agents/runs/compare?base=3&head=6", http.StatusNotFound},
+		{"/projects/1/agents/runs/compare?base=3", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if err != nil {
+			t.Fatalf("app.Test error: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.status {
+			t.Errorf("%s: expected status %d, got %d", tc.path, tc.status, resp.StatusCode)
+		}
+	}
+}
+
 type stubAgentService struct {
-	queueFunc func(ctx context.Context, req agents.RunRequest) (models.AgentRun, error)
-	getFunc   func(ctx context.Context, id int64) (models.AgentRun, error)
-	listFunc  func(ctx context.Context, projectID int64) ([]models.AgentRun, error)
+	queueFunc    func(ctx context.Context, req agents.RunRequest) (models.AgentRun, error)
+	estimateFunc func(ctx context.Context, req agents.RunRequest) (agents.Estimate, error)
+	getFunc      func(ctx context.Context, id int64) (models.AgentRun, error)
+	listFunc     func(ctx context.Context, projectID int64) ([]models.AgentRun, error)
+	deadFunc     func(ctx context.Context, projectID int64) ([]models.AgentRun, error)
+	requeueFunc  func(ctx context.Context, projectID, runID int64) (models.AgentRun, error)
+	cancelFunc   func(ctx context.Context, projectID, runID int64) (models.AgentRun, error)
+	compareFunc  func(ctx context.Context, projectID, baseID, headID int64) (models.RunComparison, error)
 }
 
 func (s *stubAgentService) QueueRun(ctx context.Context, req agents.RunRequest) (models.AgentRun, error) {
@@ -144,6 +386,13 @@ func (s *stubAgentService) QueueRun(ctx context.Context, req agents.RunRequest)
 	return s.queueFunc(ctx, req)
 }
 
+func (s *stubAgentService) EstimateRun(ctx context.Context, req agents.RunRequest) (agents.Estimate, error) {
+	if s.estimateFunc == nil {
+		return agents.Estimate{}, nil
+	}
+	return s.estimateFunc(ctx, req)
+}
+
 func (s *stubAgentService) GetRun(ctx context.Context, id int64) (models.AgentRun, error) {
 	if s.getFunc == nil {
 		return models.AgentRun{}, nil
@@ -157,3 +406,31 @@ func (s *stubAgentService) ListRuns(ctx context.Context, projectID int64) ([]mod
 	}
 	return s.listFunc(ctx, projectID)
 }
+
+func (s *stubAgentService) ListDeadLetterRuns(ctx context.Context, projectID int64) ([]models.AgentRun, error) {
+	if s.deadFunc == nil {
+		return nil, nil
+	}
+	return s.deadFunc(ctx, projectID)
+}
+
+func (s *stubAgentService) RequeueRun(ctx context.Context, projectID, runID int64) (models.AgentRun, error) {
+	if s.requeueFunc == nil {
+		return models.AgentRun{}, nil
+	}
+	return s.requeueFunc(ctx, projectID, runID)
+}
+
+func (s *stubAgentService) CancelRun(ctx context.Context, projectID, runID int64) (models.AgentRun, error) {
+	if s.cancelFunc == nil {
+		return models.AgentRun{}, nil
+	}
+	return s.cancelFunc(ctx, projectID, runID)
+}
+
+func (s *stubAgentService) CompareRuns(ctx context.Context, projectID, baseID, headID int64) (models.RunComparison, error) {
+	if s.compareFunc == nil {
+		return models.RunComparison{}, nil
+	}
+	return s.compareFunc(ctx, projectID, baseID, headID)
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+type CreditService interface {
+	Balance(ctx context.Context, userID int64) (models.CreditBalance, error)
+}
+
+type CreditHandler struct {
+	service CreditService
+}
+
+func NewCreditHandler(service CreditService) *CreditHandler {
+	return &CreditHandler{service: service}
+}
+
+func (h *CreditHandler) Register(app fiber.Router) {
+	app.Get("/credits", h.balance)
+}
+
+func (h *CreditHandler) balance(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return err
+	}
+
+	balance, err := h.service.Balance(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"data": balance})
+}
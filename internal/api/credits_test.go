@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+type stubCreditService struct{}
+
+func (stubCreditService) Balance(_ context.Context, userID int64) (models.CreditBalance, error) {
+	return models.CreditBalance{CreditsRemaining: 420, CreditsTotal: 500, SubscriptionTier: "free"}, nil
+}
+
+func TestCreditBalanceHandler(t *testing.T) {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", int64(4))
+		return c.Next()
+	})
+	NewCreditHandler(stubCreditService{}).Register(app)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/credits", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Data models.CreditBalance `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Data.CreditsRemaining != 420 || payload.Data.SubscriptionTier != "free" {
+		t.Fatalf("unexpected balance %+v", payload.Data)
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourusername/draft-forge/internal/agents"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+type PipelineService interface {
+	QueuePipeline(ctx context.Context, req agents.PipelineRequest) (models.Pipeline, error)
+	GetPipeline(ctx context.Context, projectID int64, pipelineID string) (models.Pipeline, error)
+}
+
+type PipelineHandler struct {
+	service PipelineService
+}
+
+func NewPipelineHandler(service PipelineService) *PipelineHandler {
+	return &PipelineHandler{service: service}
+}
+
+func (h *PipelineHandler) Register(app fiber.Router) {
+	app.Post("/projects/:projectID/pipelines", h.queuePipeline)
+	app.Get("/projects/:projectID/pipelines/:pipelineID", h.getPipeline)
+}
+
+type pipelineStageRequest struct {
+	AgentType string   `json:"agent_type"`
+	DependsOn []string `json:"depends_on"`
+}
+
+type pipelineRequest struct {
+	Trigger      string                 `json:"trigger"`
+	FilesChanged []string               `json:"files_changed"`
+	Stages       []pipelineStageRequest `json:"stages"`
+}
+
+func (h *PipelineHandler) queuePipeline(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseInt(c.Params("projectID"), 10, 64)
+	if err != nil || projectID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid project id")
+	}
+
+	var req pipelineRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	pipelineReq := agents.PipelineRequest{
+		ProjectID:    projectID,
+		Trigger:      req.Trigger,
+		FilesChanged: req.FilesChanged,
+	}
+	for _, stage := range req.Stages {
+		pipelineReq.Stages = append(pipelineReq.Stages, agents.PipelineStage{AgentType: stage.AgentType, DependsOn: stage.DependsOn})
+	}
+
+	pipeline, err := h.service.QueuePipeline(c.Context(), pipelineReq)
+	if err != nil {
+		return pipelineError(err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"data": pipeline,
+		"meta": fiber.Map{
+			"message": "Agent pipeline queued successfully",
+		},
+	})
+}
+
+func (h *PipelineHandler) getPipeline(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseInt(c.Params("projectID"), 10, 64)
+	if err != nil || projectID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid project id")
+	}
+
+	pipeline, err := h.service.GetPipeline(c.Context(), projectID, c.Params("pipelineID"))
+	if err != nil {
+		return pipelineError(err)
+	}
+
+	return c.JSON(fiber.Map{"data": pipeline})
+}
+
+// pipelineError maps pipeline validation and lookup failures to HTTP errors, falling
+// back to the single-run mapping for failures raised while planning each stage.
+func pipelineError(err error) error {
+	switch {
+	case errors.Is(err, agents.ErrInvalidPipeline):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, agents.ErrPipelineNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	default:
+		return queueRunError(err)
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourusername/draft-forge/internal/agents"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+type stubPipelineService struct {
+	queued agents.PipelineRequest
+}
+
+func (s *stubPipelineService) QueuePipeline(_ context.Context, req agents.PipelineRequest) (models.Pipeline, error) {
+	for _, stage := range req.Stages {
+		if stage.AgentType == "unknown" {
+			return models.Pipeline{}, fmt.Errorf("%w: %s", agents.ErrInvalidAgentType, stage.AgentType)
+		}
+		for _, dep := range stage.DependsOn {
+			if dep == stage.AgentType {
+				return models.Pipeline{}, fmt.Errorf("%w: %s depends on itself", agents.ErrInvalidPipeline, dep)
+			}
+		}
+	}
+	s.queued = req
+	return models.Pipeline{ID: "p1", ProjectID: req.ProjectID, Status: "queued"}, nil
+}
+
+func (s *stubPipelineService) GetPipeline(_ context.Context, projectID int64, pipelineID string) (models.Pipeline, error) {
+	if pipelineID != "p1" {
+		return models.Pipeline{}, agents.ErrPipelineNotFound
+	}
+	return models.Pipeline{ID: pipelineID, ProjectID: projectID, Status: "running"}, nil
+}
+
+func TestPipelineHandlers(t *testing.T) {
+	app := fiber.New()
+	service := &stubPipelineService{}
+	NewPipelineHandler(service).Register(app)
+
+	cases := []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodPost, "/projects/1/pipelines", `{"files_changed":["chapters/01.md"],"stages":[{"agent_type":"continuity"},{"agent_type":"style","depends_on":["continuity"]}]}`, http.StatusAccepted},
+		{http.MethodPost, "/projects/1/pipelines", `{"stages":[{"agent_type":"style","depends_on":["style"]}]}`, http.StatusBadRequest},
+		{http.MethodPost, "/projects/1/pipelines", `{"stages":[{"agent_type":"unknown"}]}`, http.StatusBadRequest},
+		{http.MethodPost, "/projects/x/pipelines", `{}`, http.StatusBadRequest},
+		{http.MethodGet, "/projects/1/pipelines/p1", "", http.StatusOK},
+		{http.MethodGet, "/projects/1/pipelines/p2", "", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, bytes.NewReader([]byte(tc.body)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test error: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.status {
+			t.Errorf("%s %s: expected status %d, got %d", tc.method, tc.path, tc.status, resp.StatusCode)
+		}
+	}
+	if len(service.queued.Stages) != 2 || service.queued.Stages[1].DependsOn[0] != "continuity" || service.queued.FilesChanged[0] != "chapters/01.md" {
+		t.Fatalf("expected stages to be passed through, got %+v", service.queued)
+	}
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourusername/draft-forge/internal/agents"
+	"github.com/yourusername/draft-forge/internal/models"
+	"github.com/yourusername/draft-forge/internal/scheduler"
+)
+
+type ScheduleService interface {
+	List(ctx context.Context, projectID int64) ([]models.AgentSchedule, error)
+	Create(ctx context.Context, projectID int64, input scheduler.ScheduleInput) (models.AgentSchedule, error)
+	Update(ctx context.Context, projectID, scheduleID int64, input scheduler.ScheduleInput) (models.AgentSchedule, error)
+	Delete(ctx context.Context, projectID, scheduleID int64) error
+}
+
+type ScheduleHandler struct {
+	service ScheduleService
+}
+
+func NewScheduleHandler(service ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{service: service}
+}
+
+func (h *ScheduleHandler) Register(app fiber.Router) {
+	app.Get("/projects/:projectID/schedules", h.listSchedules)
+	app.Post("/projects/:projectID/schedules", h.createSchedule)
+	app.Put("/projects/:projectID/schedules/:scheduleID", h.updateSchedule)
+	app.Delete("/projects/:projectID/schedules/:scheduleID", h.deleteSchedule)
+}
+
+type scheduleRequest struct {
+	AgentType string   `json:"agent_type"`
+	Cron      string   `json:"cron"`
+	Files     []string `json:"files"`
+	Enabled   *bool    `json:"enabled"`
+}
+
+func (r scheduleRequest) input() scheduler.ScheduleInput {
+	return scheduler.ScheduleInput{AgentType: r.AgentType, Cron: r.Cron, Files: r.Files, Enabled: r.Enabled}
+}
+
+func (h *ScheduleHandler) listSchedules(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseInt(c.Params("projectID"), 10, 64)
+	if err != nil || projectID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid project id")
+	}
+
+	schedules, err := h.service.List(c.Context(), projectID)
+	if err != nil {
+		return scheduleError(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": schedules,
+		"meta": fiber.Map{"count": len(schedules)},
+	})
+}
+
+func (h *ScheduleHandler) createSchedule(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseInt(c.Params("projectID"), 10, 64)
+	if err != nil || projectID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid project id")
+	}
+
+	var req scheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	schedule, err := h.service.Create(c.Context(), projectID, req.input())
+	if err != nil {
+		return scheduleError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"data": schedule})
+}
+
+func (h *ScheduleHandler) updateSchedule(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseInt(c.Params("projectID"), 10, 64)
+	if err != nil || projectID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid project id")
+	}
+
+	scheduleID, err := strconv.ParseInt(c.Params("scheduleID"), 10, 64)
+	if err != nil || scheduleID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid schedule id")
+	}
+
+	var req scheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	schedule, err := h.service.Update(c.Context(), projectID, scheduleID, req.input())
+	if err != nil {
+		return scheduleError(err)
+	}
+
+	return c.JSON(fiber.Map{"data": schedule})
+}
+
+func (h *ScheduleHandler) deleteSchedule(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseInt(c.Params("projectID"), 10, 64)
+	if err != nil || projectID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid project id")
+	}
+
+	scheduleID, err := strconv.ParseInt(c.Params("scheduleID"), 10, 64)
+	if err != nil || scheduleID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid schedule id")
+	}
+
+	if err := h.service.Delete(c.Context(), projectID, scheduleID); err != nil {
+		return scheduleError(err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// scheduleError maps schedule validation and lookup failures to HTTP errors.
+func scheduleError(err error) error {
+	switch {
+	case errors.Is(err, agents.ErrInvalidAgentType), errors.Is(err, scheduler.ErrInvalidCron):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, models.ErrNotFound):
+		return fiber.NewError(fiber.StatusNotFound, "project not found")
+	case errors.Is(err, scheduler.ErrScheduleNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourusername/draft-forge/internal/models"
+	"github.com/yourusername/draft-forge/internal/scheduler"
+)
+
+type stubScheduleService struct {
+	created scheduler.ScheduleInput
+}
+
+func (s *stubScheduleService) List(_ context.Context, projectID int64) ([]models.AgentSchedule, error) {
+	if projectID != 1 {
+		return nil, models.ErrNotFound
+	}
+	return []models.AgentSchedule{{ID: 1, ProjectID: 1, AgentType: "timeline", Cron: "0 6 * * 1", Enabled: true}}, nil
+}
+
+func (s *stubScheduleService) Create(_ context.Context, projectID int64, input scheduler.ScheduleInput) (models.AgentSchedule, error) {
+	if _, err := scheduler.Parse(input.Cron); err != nil {
+		return models.AgentSchedule{}, err
+	}
+	s.created = input
+	return models.AgentSchedule{ID: 2, ProjectID: projectID, AgentType: input.AgentType, Cron: input.Cron, Enabled: true}, nil
+}
+
+func (s *stubScheduleService) Update(_ context.Context, projectID, scheduleID int64, input scheduler.ScheduleInput) (models.AgentSchedule, error) {
+	return models.AgentSchedule{ID: scheduleID, ProjectID: projectID, AgentType: input.AgentType, Cron: input.Cron}, nil
+}
+
+func (s *stubScheduleService) Delete(_ context.Context, _, scheduleID int64) error {
+	if scheduleID != 1 {
+		return fmt.Errorf("delete: %w", scheduler.ErrScheduleNotFound)
+	}
+	return nil
+}
+
+func TestScheduleHandlers(t *testing.T) {
+	app := fiber.New()
+	service := &stubScheduleService{}
+	NewScheduleHandler(service).Register(app)
+
+	cases := []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodGet, "/projects/1/schedules", "", http.StatusOK},
+		{http.MethodGet, "/projects/2/schedules", "", http.StatusNotFound},
+		{http.MethodPost, "/projects/1/schedules", `{"agent_type":"style","cron":"@daily","files":["chapters/01.md"]}`, http.StatusCreated},
+		{http.MethodPost, "/projects/1/schedules", `{"agent_type":"style","cron":"daily"}`, http.StatusBadRequest},
+		{http.MethodPut, "/projects/1/schedules/2", `{"agent_type":"style","cron":"@hourly","enabled":false}`, http.StatusOK},
+		{http.MethodDelete, "/projects/1/schedules/1", "", http.StatusNoContent},
+		{http.MethodDelete, "/projects/1/schedules/5", "", http.StatusNotFound},
+		{http.MethodDelete, "/projects/1/schedules/x", "", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, bytes.NewReader([]byte(tc.body)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test error: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.status {
+			t.Errorf("%s %s: expected status %d, got %d", tc.method, tc.path, tc.status, resp.StatusCode)
+		}
+	}
+	if service.created.AgentType != "style" || len(service.created.Files) != 1 {
+		t.Fatalf("expected the request body to reach the service, got %+v", service.created)
+	}
+}
+
+func TestListSchedulesHandlerPayload(t *testing.T) {
+	app := fiber.New()
+	NewScheduleHandler(&stubScheduleService{}).Register(app)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/projects/1/schedules", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Data []models.AgentSchedule `json:"data"`
+		Meta struct {
+			Count int `json:"count"`
+		} `json:"meta"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Meta.Count != 1 || payload.Data[0].AgentType != "timeline" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+}
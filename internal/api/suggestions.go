@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourusername/draft-forge/internal/models"
+	"github.com/yourusername/draft-forge/internal/suggestions"
+)
+
+type SuggestionService interface {
+	List(ctx context.Context, projectID int64, state string) ([]models.Suggestion, error)
+	Get(ctx context.Context, projectID, id int64) (models.Suggestion, error)
+	Accept(ctx context.Context, projectID, id int64) (models.Suggestion, error)
+	Reject(ctx context.Context, projectID, id int64, reason string) (models.Suggestion, error)
+}
+
+type SuggestionHandler struct {
+	service SuggestionService
+}
+
+func NewSuggestionHandler(service SuggestionService) *SuggestionHandler {
+	return &SuggestionHandler{service: service}
+}
+
+func (h *SuggestionHandler) Register(app fiber.Router) {
+	app.Get("/projects/:projectID/suggestions", h.listSuggestions)
+	app.Get("/projects/:projectID/suggestions/:suggestionID", h.getSuggestion)
+	app.Post("/projects/:projectID/suggestions/:suggestionID/accept", h.acceptSuggestion)
+	app.Post("/projects/:projectID/suggestions/:suggestionID/reject", h.rejectSuggestion)
+}
+
+type rejectSuggestionRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (h *SuggestionHandler) listSuggestions(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseInt(c.Params("projectID"), 10, 64)
+	if err != nil || projectID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid project id")
+	}
+
+	list, err := h.service.List(c.Context(), projectID, c.Query("state"))
+	if err != nil {
+		return suggestionError(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": list,
+		"meta": fiber.Map{"count": len(list)},
+	})
+}
+
+func (h *SuggestionHandler) getSuggestion(c *fiber.Ctx) error {
+	projectID, suggestionID, err := suggestionParams(c)
+	if err != nil {
+		return err
+	}
+
+	suggestion, err := h.service.Get(c.Context(), projectID, suggestionID)
+	if err != nil {
+		return suggestionError(err)
+	}
+
+	return c.JSON(fiber.Map{"data": suggestion})
+}
+
+func (h *SuggestionHandler) acceptSuggestion(c *fiber.Ctx) error {
+	projectID, suggestionID, err := suggestionParams(c)
+	if err != nil {
+		return err
+	}
+
+	suggestion, err := h.service.Accept(c.Context(), projectID, suggestionID)
+	if err != nil {
+		return suggestionError(err)
+	}
+
+	return c.JSON(fiber.Map{"data": suggestion})
+}
+
+func (h *SuggestionHandler) rejectSuggestion(c *fiber.Ctx) error {
+	projectID, suggestionID, err := suggestionParams(c)
+	if err != nil {
+		return err
+	}
+
+	var req rejectSuggestionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	suggestion, err := h.service.Reject(c.Context(), projectID, suggestionID, req.Reason)
+	if err != nil {
+		return suggestionError(err)
+	}
+
+	return c.JSON(fiber.Map{"data": suggestion})
+}
+
+func suggestionParams(c *fiber.Ctx) (int64, int64, error) {
+	projectID, err := strconv.ParseInt(c.Params("projectID"), 10, 64)
+	if err != nil || projectID <= 0 {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid project id")
+	}
+
+	suggestionID, err := strconv.ParseInt(c.Params("suggestionID"), 10, 64)
+	if err != nil || suggestionID <= 0 {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid suggestion id")
+	}
+	return projectID, suggestionID, nil
+}
+
+// suggestionError maps suggestion review failures to HTTP errors.
+func suggestionError(err error) error {
+	switch {
+	case errors.Is(err, suggestions.ErrInvalidState), errors.Is(err, suggestions.ErrReasonRequired):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, suggestions.ErrSuggestionNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, suggestions.ErrSuggestionNotOpen), errors.Is(err, suggestions.ErrSuggestionStale):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourusername/draft-forge/internal/models"
+	"github.com/yourusername/draft-forge/internal/suggestions"
+)
+
+type stubSuggestionService struct {
+	rejectReason string
+}
+
+func (s *stubSuggestionService) List(_ context.Context, projectID int64, state string) ([]models.Suggestion, error) {
+	if state == "merged" {
+		return nil, suggestions.ErrInvalidState
+	}
+	return []models.Suggestion{{ID: 1, ProjectID: projectID, State: models.SuggestionOpen}}, nil
+}
+
+func (s *stubSuggestionService) Get(_ context.Context, projectID, id int64) (models.Suggestion, error) {
+	if id != 1 {
+		return models.Suggestion{}, suggestions.ErrSuggestionNotFound
+	}
+	return models.Suggestion{ID: id, ProjectID: projectID, State: models.SuggestionOpen}, nil
+}
+
+func (s *stubSuggestionService) Accept(_ context.Context, projectID, id int64) (models.Suggestion, error) {
+	if id == 2 {
+		return models.Suggestion{}, fmt.Errorf("%w: chapters/01.md changed", suggestions.ErrSuggestionStale)
+	}
+	return models.Suggestion{ID: id, ProjectID: projectID, State: models.SuggestionAccepted, Branch: "draftforge/suggestion-1"}, nil
+}
+
+func (s *stubSuggestionService) Reject(_ context.Context, projectID, id int64, reason string) (models.Suggestion, error) {
+	if reason == "" {
+		return models.Suggestion{}, suggestions.ErrReasonRequired
+	}
+	s.rejectReason = reason
+	return models.Suggestion{ID: id, ProjectID: projectID, State: models.SuggestionRejected, Reason: reason}, nil
+}
+
+func TestSuggestionHandlers(t *testing.T) {
+	app := fiber.New()
+	service := &stubSuggestionService{}
+	NewSuggestionHandler(service).Register(app)
+
+	cases := []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodGet, "/projects/1/suggestions?state=open", "", http.StatusOK},
+		{http.MethodGet, "/projects/1/suggestions?state=merged", "", http.StatusBadRequest},
+		{http.MethodGet, "/projects/1/suggestions/1", "", http.StatusOK},
+		{http.MethodGet, "/projects/1/suggestions/3", "", http.StatusNotFound},
+		{http.MethodPost, "/projects/1/suggestions/1/accept", "", http.StatusOK},
+		{http.MethodPost, "/projects/1/suggestions/2/accept", "", http.StatusConflict},
+		{http.MethodPost, "/projects/1/suggestions/1/reject", `{"reason":"Intentional."}`, http.StatusOK},
+		{http.MethodPost, "/projects/1/suggestions/1/reject", `{}`, http.StatusBadRequest},
+		{http.MethodPost, "/projects/1/suggestions/x/reject", `{}`, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, bytes.NewReader([]byte(tc.body)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test error: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.status {
+			t.Errorf("%s %s: expected status %d, got %d", tc.method, tc.path, tc.status, resp.StatusCode)
+		}
+	}
+	if service.rejectReason != "Intentional." {
+		t.Fatalf("expected the reason to be passed through, got %q", service.rejectReason)
+	}
+}
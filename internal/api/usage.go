@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+type UsageService interface {
+	UsageReport(ctx context.Context, filter models.UsageFilter) (models.UsageReport, error)
+}
+
+type UsageHandler struct {
+	service UsageService
+}
+
+func NewUsageHandler(service UsageService) *UsageHandler {
+	return &UsageHandler{service: service}
+}
+
+func (h *UsageHandler) Register(app fiber.Router) {
+	app.Get("/usage", h.report)
+}
+
+// report returns the caller's model usage, optionally narrowed with project_id, since
+// and until (RFC 3339 timestamps or YYYY-MM-DD dates).
+func (h *UsageHandler) report(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return err
+	}
+
+	filter := models.UsageFilter{UserID: userID}
+	if raw := c.Query("project_id"); raw != "" {
+		projectID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || projectID <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid project_id")
+		}
+		filter.ProjectID = projectID
+	}
+	if filter.Since, err = parseTimeQuery(c, "since"); err != nil {
+		return err
+	}
+	if filter.Until, err = parseTimeQuery(c, "until"); err != nil {
+		return err
+	}
+
+	report, err := h.service.UsageReport(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"data": report})
+}
+
+func parseTimeQuery(c *fiber.Ctx, key string) (*time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fiber.NewError(fiber.StatusBadRequest, "invalid "+key)
+}
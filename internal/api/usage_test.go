@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+type stubUsageService struct {
+	filter models.UsageFilter
+}
+
+func (s *stubUsageService) UsageReport(_ context.Context, filter models.UsageFilter) (models.UsageReport, error) {
+	s.filter = filter
+	return models.UsageReport{
+		Totals:  models.UsageTotals{Calls: 2, TotalTokens: 900},
+		ByAgent: []models.UsageGroup{{AgentType: "style", UsageTotals: models.UsageTotals{Calls: 2, TotalTokens: 900}}},
+	}, nil
+}
+
+func newUsageApp(service UsageService) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", int64(4))
+		return c.Next()
+	})
+	NewUsageHandler(service).Register(app)
+	return app
+}
+
+func TestUsageReportHandler(t *testing.T) {
+	service := &stubUsageService{}
+	app := newUsageApp(service)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/usage?project_id=1&since=2024-03-01", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Data models.UsageReport `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Data.Totals.TotalTokens != 900 || len(payload.Data.ByAgent) != 1 {
+		t.Fatalf("unexpected usage payload: %+v", payload.Data)
+	}
+	if service.filter.UserID != 4 || service.filter.ProjectID != 1 || service.filter.Since == nil || service.filter.Since.Day() != 1 {
+		t.Fatalf("unexpected filter %+v", service.filter)
+	}
+}
+
+func TestUsageReportHandlerInvalidSince(t *testing.T) {
+	app := newUsageApp(&stubUsageService{})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/usage?since=yesterday", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+}
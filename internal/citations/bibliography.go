@@ -0,0 +1,293 @@
+// Package citations parses project-local bibliographies (BibTeX and CSL-JSON) and the
+// citation markers used in Markdown manuscripts (Pandoc [@key] citations and footnotes).
+package citations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrMalformedBibliography = errors.New("malformed bibliography")
+
+// Entry is a bibliography record reduced to the fields used for citation checks.
+type Entry struct {
+	Key   string `json:"key"`
+	Type  string `json:"type,omitempty"`
+	Title string `json:"title,omitempty"`
+	// Authors holds family names, in order.
+	Authors   []string `json:"authors,omitempty"`
+	Year      int      `json:"year,omitempty"`
+	Container string   `json:"container,omitempty"`
+	URL       string   `json:"url,omitempty"`
+	DOI       string   `json:"doi,omitempty"`
+	// Source is the bibliography file the entry came from.
+	Source string `json:"source,omitempty"`
+}
+
+// Library is a set of entries keyed by citation key.
+type Library map[string]Entry
+
+// Add inserts entries, keeping the first entry seen for a duplicate key.
+func (l Library) Add(entries ...Entry) {
+	for _, e := range entries {
+		if _, exists := l[e.Key]; !exists {
+			l[e.Key] = e
+		}
+	}
+}
+
+// Keys returns the citation keys in sorted order.
+func (l Library) Keys() []string {
+	keys := make([]string, 0, len(l))
+	for k := range l {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// HasAuthor reports whether family name is one of the entry's authors (case-insensitive).
+func (e Entry) HasAuthor(family string) bool {
+	for _, a := range e.Authors {
+		if strings.EqualFold(a, family) {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats the entry as a short reference: "key: Smith & Jones (2019). Title."
+func (e Entry) String() string {
+	var b strings.Builder
+	b.WriteString(e.Key + ":")
+	if len(e.Authors) > 0 {
+		b.WriteString(" " + strings.Join(e.Authors, " & "))
+	}
+	if e.Year > 0 {
+		fmt.Fprintf(&b, " (%d)", e.Year)
+	}
+	if e.Title != "" {
+		b.WriteString(". " + e.Title + ".")
+	}
+	return b.String()
+}
+
+var (
+	bibEntryStart = regexp.MustCompile(`@([A-Za-z]+)\s*[{(]`)
+	bibFieldName  = regexp.MustCompile(`^\s*,?\s*([A-Za-z][A-Za-z0-9_-]*)\s*=\s*`)
+	yearPattern   = regexp.MustCompile(`\b(\d{4})\b`)
+)
+
+// ParseBibTeX parses the entries of a BibTeX file. @comment, @string and @preamble
+// blocks are skipped; string macros are not expanded.
+func ParseBibTeX(content string) ([]Entry, error) {
+	var entries []Entry
+	rest := content
+	for {
+		loc := bibEntryStart.FindStringSubmatchIndex(rest)
+		if loc == nil {
+			return entries, nil
+		}
+		entryType := strings.ToLower(rest[loc[2]:loc[3]])
+		body, next, err := balanced(rest, loc[1]-1)
+		if err != nil {
+			return nil, fmt.Errorf("%w: @%s entry: %v", ErrMalformedBibliography, entryType, err)
+		}
+		rest = rest[next:]
+
+		switch entryType {
+		case "comment", "string", "preamble":
+			continue
+		}
+		entry, err := parseBibEntry(entryType, body)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
+func parseBibEntry(entryType, body string) (Entry, error) {
+	comma := strings.Index(body, ",")
+	if comma < 0 {
+		return Entry{}, fmt.Errorf("%w: @%s entry without fields", ErrMalformedBibliography, entryType)
+	}
+	entry := Entry{Key: strings.TrimSpace(body[:comma]), Type: entryType}
+	if entry.Key == "" {
+		return Entry{}, fmt.Errorf("%w: @%s entry without a key", ErrMalformedBibliography, entryType)
+	}
+
+	fields := body[comma+1:]
+	for {
+		m := bibFieldName.FindStringSubmatchIndex(fields)
+		if m == nil {
+			break
+		}
+		name := strings.ToLower(fields[m[2]:m[3]])
+		value, next, err := bibValue(fields, m[1])
+		if err != nil {
+			return Entry{}, fmt.Errorf("%w: %s field %s: %v", ErrMalformedBibliography, entry.Key, name, err)
+		}
+		fields = fields[next:]
+
+		switch name {
+		case "title":
+			entry.Title = value
+		case "author":
+			entry.Authors = bibAuthors(value)
+		case "year":
+			entry.Year, _ = strconv.Atoi(value)
+		case "date":
+			if y := yearPattern.FindString(value); y != "" && entry.Year == 0 {
+				entry.Year, _ = strconv.Atoi(y)
+			}
+		case "journal", "booktitle", "publisher":
+			if entry.Container == "" {
+				entry.Container = value
+			}
+		case "url":
+			entry.URL = value
+		case "doi":
+			entry.DOI = value
+		}
+	}
+	return entry, nil
+}
+
+// bibValue reads a braced, quoted or bare field value starting at i.
+func bibValue(s string, i int) (string, int, error) {
+	if i >= len(s) {
+		return "", i, errors.New("missing value")
+	}
+	switch s[i] {
+	case '{':
+		value, next, err := balanced(s, i)
+		return cleanBibText(value), next, err
+	case '"':
+		end := strings.IndexByte(s[i+1:], '"')
+		if end < 0 {
+			return "", i, errors.New("unterminated quoted value")
+		}
+		return cleanBibText(s[i+1 : i+1+end]), i + end + 2, nil
+	}
+	end := strings.IndexAny(s[i:], ",}\n")
+	if end < 0 {
+		end = len(s) - i
+	}
+	return strings.TrimSpace(s[i : i+end]), i + end, nil
+}
+
+// balanced returns the text inside the brace or parenthesis group opening at s[i] and
+// the offset just past its closing delimiter.
+func balanced(s string, i int) (string, int, error) {
+	open := s[i]
+	closer := byte('}')
+	if open == '(' {
+		closer = ')'
+	}
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case open:
+			depth++
+		case closer:
+			depth--
+			if depth == 0 {
+				return s[i+1 : j], j + 1, nil
+			}
+		}
+	}
+	return "", len(s), errors.New("unbalanced braces")
+}
+
+func cleanBibText(s string) string {
+	s = strings.NewReplacer("{", "", "}", "", "\n", " ", "\t", " ").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// bibAuthors splits a BibTeX author list into family names. Both "Family, Given" and
+// "Given Family" forms are accepted.
+func bibAuthors(value string) []string {
+	var authors []string
+	for _, name := range strings.Split(value, " and ") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if family, _, ok := strings.Cut(name, ","); ok {
+			authors = append(authors, strings.TrimSpace(family))
+			continue
+		}
+		parts := strings.Fields(name)
+		authors = append(authors, parts[len(parts)-1])
+	}
+	return authors
+}
+
+type cslName struct {
+	Family  string `json:"family"`
+	Given   string `json:"given"`
+	Literal string `json:"literal"`
+}
+
+type cslItem struct {
+	ID             any       `json:"id"`
+	Type           string    `json:"type"`
+	Title          string    `json:"title"`
+	Author         []cslName `json:"author"`
+	ContainerTitle string    `json:"container-title"`
+	Publisher      string    `json:"publisher"`
+	URL            string    `json:"URL"`
+	DOI            string    `json:"DOI"`
+	Issued         struct {
+		DateParts [][]any `json:"date-parts"`
+		Literal   string  `json:"literal"`
+	} `json:"issued"`
+}
+
+// ParseCSLJSON parses a CSL-JSON bibliography (an array of items).
+func ParseCSLJSON(data []byte) ([]Entry, error) {
+	var items []cslItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedBibliography, err)
+	}
+
+	entries := make([]Entry, 0, len(items))
+	for i, item := range items {
+		key := strings.TrimSpace(fmt.Sprint(item.ID))
+		if item.ID == nil || key == "" {
+			return nil, fmt.Errorf("%w: item %d has no id", ErrMalformedBibliography, i)
+		}
+		entry := Entry{
+			Key:       key,
+			Type:      item.Type,
+			Title:     item.Title,
+			Container: item.ContainerTitle,
+			URL:       item.URL,
+			DOI:       item.DOI,
+		}
+		if entry.Container == "" {
+			entry.Container = item.Publisher
+		}
+		for _, a := range item.Author {
+			switch {
+			case a.Family != "":
+				entry.Authors = append(entry.Authors, a.Family)
+			case a.Literal != "":
+				entry.Authors = append(entry.Authors, a.Literal)
+			}
+		}
+		if parts := item.Issued.DateParts; len(parts) > 0 && len(parts[0]) > 0 {
+			entry.Year, _ = strconv.Atoi(fmt.Sprint(parts[0][0]))
+		} else if y := yearPattern.FindString(item.Issued.Literal); y != "" {
+			entry.Year, _ = strconv.Atoi(y)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
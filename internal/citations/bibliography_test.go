@@ -0,0 +1,75 @@
+package citations
+
+import (
+	"errors"
+	"testing"
+)
+
+const sampleBib = `% Sources for chapter 3
+@comment{ignored}
+
+@article{smith2019,
+  author  = {Smith, Jane and Robert Jones},
+  title   = {The {Atlantic} Cod Collapse},
+  journal = "Marine Policy",
+  year    = 2019,
+  doi     = {10.1000/cod}
+}
+
+@book(lee2001,
+  author = {Lee, Ann},
+  title = {Fisheries},
+  date = {2001-05},
+  publisher = {Harbour Press}
+)
+`
+
+func TestParseBibTeX(t *testing.T) {
+	entries, err := ParseBibTeX(sampleBib)
+	if err != nil {
+		t.Fatalf("ParseBibTeX returned error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+
+	smith := entries[0]
+	if smith.Key != "smith2019" || smith.Type != "article" || smith.Title != "The Atlantic Cod Collapse" {
+		t.Fatalf("unexpected entry %+v", smith)
+	}
+	if smith.Year != 2019 || smith.Container != "Marine Policy" || smith.DOI != "10.1000/cod" {
+		t.Fatalf("unexpected entry fields %+v", smith)
+	}
+	if len(smith.Authors) != 2 || !smith.HasAuthor("jones") {
+		t.Fatalf("expected Smith and Jones as authors, got %v", smith.Authors)
+	}
+	if lee := entries[1]; lee.Year != 2001 || lee.Container != "Harbour Press" {
+		t.Fatalf("unexpected entry %+v", lee)
+	}
+}
+
+func TestParseBibTeXRejectsUnbalancedEntries(t *testing.T) {
+	if _, err := ParseBibTeX("@book{broken, title = {Oops}"); !errors.Is(err, ErrMalformedBibliography) {
+		t.Fatalf("expected ErrMalformedBibliography, got %v", err)
+	}
+}
+
+func TestParseCSLJSON(t *testing.T) {
+	entries, err := ParseCSLJSON([]byte(`[
+		{"id": "smith2019", "type": "article-journal", "title": "Cod", "author": [{"family": "Smith", "given": "Jane"}, {"literal": "NOAA"}], "issued": {"date-parts": [[2019, 3]]}, "container-title": "Marine Policy"},
+		{"id": 42, "title": "Numbered", "issued": {"literal": "circa 1998"}}
+	]`))
+	if err != nil {
+		t.Fatalf("ParseCSLJSON returned error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Year != 2019 || !entries[0].HasAuthor("NOAA") || entries[0].Container != "Marine Policy" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	if entries[1].Key != "42" || entries[1].Year != 1998 {
+		t.Fatalf("unexpected entry %+v", entries[1])
+	}
+
+	if _, err := ParseCSLJSON([]byte(`[{"title": "no id"}]`)); !errors.Is(err, ErrMalformedBibliography) {
+		t.Fatalf("expected ErrMalformedBibliography for missing id, got %v", err)
+	}
+}
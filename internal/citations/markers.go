@@ -0,0 +1,72 @@
+package citations
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Citation is a Pandoc citation key found in text, e.g. the "smith2019" in
+// "[see @smith2019, p. 4]". Start and End are byte offsets of the enclosing brackets.
+type Citation struct {
+	Key     string
+	Locator string
+	Start   int
+	End     int
+}
+
+// FootnoteRef is a Markdown footnote reference such as "[^3]".
+type FootnoteRef struct {
+	Label string
+	Start int
+	End   int
+}
+
+var (
+	citationGroup      = regexp.MustCompile(`\[[^\[\]]*@[^\[\]]*\]`)
+	citationKey        = regexp.MustCompile(`-?@([A-Za-z0-9_][A-Za-z0-9_:.#$%&+?<>~/-]*)([^;\]]*)`)
+	footnoteRef        = regexp.MustCompile(`\[\^([^\]\s]+)\]`)
+	footnoteDefinition = regexp.MustCompile(`^\[\^([^\]\s]+)\]:\s*(.*)$`)
+)
+
+// FindCitations returns the bracketed Pandoc citations in text, one per key.
+func FindCitations(text string) []Citation {
+	var out []Citation
+	for _, group := range citationGroup.FindAllStringIndex(text, -1) {
+		inner := text[group[0]+1 : group[1]-1]
+		for _, m := range citationKey.FindAllStringSubmatch(inner, -1) {
+			key := strings.TrimRight(m[1], ".:,;?")
+			if key == "" {
+				continue
+			}
+			out = append(out, Citation{
+				Key:     key,
+				Locator: strings.TrimSpace(strings.TrimLeft(m[2], ", ")),
+				Start:   group[0],
+				End:     group[1],
+			})
+		}
+	}
+	return out
+}
+
+// FindFootnoteRefs returns the footnote references in text. A definition marker at the
+// start of a line ("[^1]: ...") is not a reference.
+func FindFootnoteRefs(text string) []FootnoteRef {
+	var out []FootnoteRef
+	for _, m := range footnoteRef.FindAllStringSubmatchIndex(text, -1) {
+		if m[1] < len(text) && text[m[1]] == ':' && (m[0] == 0 || text[m[0]-1] == '\n') {
+			continue
+		}
+		out = append(out, FootnoteRef{Label: text[m[2]:m[3]], Start: m[0], End: m[1]})
+	}
+	return out
+}
+
+// ParseFootnoteDefinition parses a "[^label]: text" line.
+func ParseFootnoteDefinition(line string) (string, string, bool) {
+	m := footnoteDefinition.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], strings.TrimSpace(m[2]), true
+}
@@ -0,0 +1,33 @@
+package citations
+
+import "testing"
+
+func TestFindCitations(t *testing.T) {
+	text := "Stocks fell 40% [see @smith2019, p. 4; -@lee2001]. Mail me@example.com."
+	got := FindCitations(text)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 citations, got %+v", got)
+	}
+	if got[0].Key != "smith2019" || got[0].Locator != "p. 4" || text[got[0].Start:got[0].End] != "[see @smith2019, p. 4; -@lee2001]" {
+		t.Fatalf("unexpected first citation %+v", got[0])
+	}
+	if got[1].Key != "lee2001" {
+		t.Fatalf("unexpected second citation %+v", got[1])
+	}
+}
+
+func TestFootnotes(t *testing.T) {
+	text := "Landings halved.[^1] Then recovered.[^note]\n[^1]: Smith (2019)."
+	refs := FindFootnoteRefs(text)
+	if len(refs) != 2 || refs[0].Label != "1" || refs[1].Label != "note" {
+		t.Fatalf("unexpected footnote refs %+v", refs)
+	}
+
+	label, body, ok := ParseFootnoteDefinition("[^1]: Smith (2019).")
+	if !ok || label != "1" || body != "Smith (2019)." {
+		t.Fatalf("unexpected definition %q %q %v", label, body, ok)
+	}
+	if _, _, ok := ParseFootnoteDefinition("Not a footnote."); ok {
+		t.Fatal("expected plain text not to parse as a definition")
+	}
+}
@@ -0,0 +1,83 @@
+// Package credits enforces AI credit balances: runs reserve their estimated cost when
+// queued and settle the actual cost, or a refund, when they end.
+package credits
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// DefaultFreeGrant is the balance a user's credit account opens with.
+const DefaultFreeGrant = 500
+
+var ErrInsufficientCredits = errors.New("insufficient credits")
+
+// Store persists credit accounts and the reservations held by runs. Accounts are
+// opened with the free grant on first use.
+type Store interface {
+	Balance(ctx context.Context, userID int64, grant int) (models.CreditBalance, error)
+	// Reserve deducts amount from the project owner's balance, reporting false when
+	// the balance is too low.
+	Reserve(ctx context.Context, projectID int64, amount, grant int) (bool, error)
+	Release(ctx context.Context, projectID int64, amount int) error
+	// Settle returns a run's reservation, less its actual cost unless refund is set.
+	// Settling a run twice has no effect.
+	Settle(ctx context.Context, runID int64, refund bool) error
+}
+
+type Service struct {
+	store     Store
+	freeGrant int
+}
+
+func NewService(store Store, freeGrant int) *Service {
+	return &Service{store: store, freeGrant: freeGrant}
+}
+
+// ForCost converts a cost in cents to whole credits, rounding up.
+func ForCost(costCents float64) int {
+	if costCents <= 0 {
+		return 0
+	}
+	return int(math.Ceil(costCents))
+}
+
+func (s *Service) Balance(ctx context.Context, userID int64) (models.CreditBalance, error) {
+	return s.store.Balance(ctx, userID, s.freeGrant)
+}
+
+// Reserve holds amount credits from the project owner's balance.
+func (s *Service) Reserve(ctx context.Context, projectID int64, amount int) error {
+	if amount <= 0 {
+		return nil
+	}
+	ok, err := s.store.Reserve(ctx, projectID, amount, s.freeGrant)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInsufficientCredits
+	}
+	return nil
+}
+
+// Release returns a reservation that never became a run.
+func (s *Service) Release(ctx context.Context, projectID int64, amount int) error {
+	if amount <= 0 {
+		return nil
+	}
+	return s.store.Release(ctx, projectID, amount)
+}
+
+// Settle charges a finished run its actual cost and returns the rest of its reservation.
+func (s *Service) Settle(ctx context.Context, runID int64) error {
+	return s.store.Settle(ctx, runID, false)
+}
+
+// Refund returns a run's whole reservation.
+func (s *Service) Refund(ctx context.Context, runID int64) error {
+	return s.store.Settle(ctx, runID, true)
+}
@@ -0,0 +1,69 @@
+package credits
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+type memStore struct {
+	balance  int
+	reserved []int
+	settled  map[int64]bool
+}
+
+func (m *memStore) Balance(_ context.Context, _ int64, _ int) (models.CreditBalance, error) {
+	return models.CreditBalance{CreditsRemaining: m.balance}, nil
+}
+
+func (m *memStore) Reserve(_ context.Context, _ int64, amount, _ int) (bool, error) {
+	if m.balance < amount {
+		return false, nil
+	}
+	m.balance -= amount
+	m.reserved = append(m.reserved, amount)
+	return true, nil
+}
+
+func (m *memStore) Release(_ context.Context, _ int64, amount int) error {
+	m.balance += amount
+	return nil
+}
+
+func (m *memStore) Settle(_ context.Context, runID int64, refund bool) error {
+	m.settled[runID] = refund
+	return nil
+}
+
+func TestForCost(t *testing.T) {
+	cases := map[float64]int{0: 0, 0.01: 1, 1: 1, 1.2: 2, -3: 0}
+	for cost, want := range cases {
+		if got := ForCost(cost); got != want {
+			t.Fatalf("ForCost(%v): expected %d, got %d", cost, want, got)
+		}
+	}
+}
+
+func TestServiceReserve(t *testing.T) {
+	store := &memStore{balance: 10, settled: map[int64]bool{}}
+	svc := NewService(store, DefaultFreeGrant)
+	ctx := context.Background()
+
+	if err := svc.Reserve(ctx, 1, 0); err != nil || len(store.reserved) != 0 {
+		t.Fatalf("expected a free run to reserve nothing, got %v %v", err, store.reserved)
+	}
+	if err := svc.Reserve(ctx, 1, 8); err != nil {
+		t.Fatalf("Reserve returned error: %v", err)
+	}
+	if err := svc.Reserve(ctx, 1, 8); !errors.Is(err, ErrInsufficientCredits) {
+		t.Fatalf("expected ErrInsufficientCredits, got %v", err)
+	}
+
+	_ = svc.Settle(ctx, 3)
+	_ = svc.Refund(ctx, 4)
+	if store.settled[3] || !store.settled[4] {
+		t.Fatalf("expected run 3 settled and run 4 refunded, got %v", store.settled)
+	}
+}
@@ -2,37 +2,91 @@ package agent
 
 import (
 	"database/sql"
+	"encoding/json"
+
+	"github.com/lib/pq"
 
 	"github.com/yourusername/draft-forge/internal/models"
 )
 
 type dbAgentRun struct {
-	ID          int64          `db:"id"`
-	ProjectID   int64          `db:"project_id"`
-	AgentType   string         `db:"agent_type"`
-	Trigger     string         `db:"trigger"`
-	Status      string         `db:"status"`
-	Results     []byte         `db:"results"`
-	Error       sql.NullString `db:"error_message"`
-	StartedAt   sql.NullTime   `db:"started_at"`
-	CompletedAt sql.NullTime   `db:"completed_at"`
-	CreatedAt   sql.NullTime   `db:"created_at"`
+	ID               int64          `db:"id"`
+	ProjectID        int64          `db:"project_id"`
+	AgentType        string         `db:"agent_type"`
+	Trigger          string         `db:"trigger"`
+	Status           string         `db:"status"`
+	FilesChanged     pq.StringArray `db:"files_changed"`
+	Progress         []byte         `db:"progress"`
+	Results          []byte         `db:"results"`
+	Error            sql.NullString `db:"error_message"`
+	Attempts         int            `db:"attempts"`
+	NextAttemptAt    sql.NullTime   `db:"next_attempt_at"`
+	PromptTokens     int            `db:"prompt_tokens"`
+	CompletionTokens int            `db:"completion_tokens"`
+	CostCents        float64        `db:"cost_cents"`
+	CreditsReserved  int            `db:"credits_reserved"`
+	CreditsCharged   int            `db:"credits_charged"`
+	PipelineID       sql.NullString `db:"pipeline_id"`
+	DependsOn        pq.Int64Array  `db:"depends_on"`
+	PromptHash       sql.NullString `db:"prompt_hash"`
+	EditorialRound   sql.NullString `db:"editorial_round"`
+	StartedAt        sql.NullTime   `db:"started_at"`
+	CompletedAt      sql.NullTime   `db:"completed_at"`
+	CreatedAt        sql.NullTime   `db:"created_at"`
 }
 
 func (d dbAgentRun) toModel() models.AgentRun {
 	run := models.AgentRun{
-		ID:        d.ID,
-		ProjectID: d.ProjectID,
-		AgentType: d.AgentType,
-		Trigger:   d.Trigger,
-		Status:    d.Status,
+		ID:              d.ID,
+		ProjectID:       d.ProjectID,
+		AgentType:       d.AgentType,
+		Trigger:         d.Trigger,
+		Status:          d.Status,
+		Attempts:        d.Attempts,
+		CreditsReserved: d.CreditsReserved,
+		CreditsCharged:  d.CreditsCharged,
+	}
+	if d.PipelineID.Valid {
+		run.PipelineID = d.PipelineID.String
+	}
+	if d.PromptHash.Valid {
+		run.PromptHash = d.PromptHash.String
+	}
+	if d.EditorialRound.Valid {
+		run.EditorialRound = d.EditorialRound.String
+	}
+	if len(d.DependsOn) > 0 {
+		run.DependsOn = []int64(d.DependsOn)
+	}
+	if len(d.FilesChanged) > 0 {
+		run.FilesChanged = []string(d.FilesChanged)
+	}
+	if len(d.Progress) > 0 {
+		var progress models.RunProgress
+		if err := json.Unmarshal(d.Progress, &progress); err == nil {
+			run.Progress = &progress
+		}
 	}
 	if len(d.Results) > 0 {
-		run.Results = d.Results
+		var results models.RunResult
+		if err := json.Unmarshal(d.Results, &results); err == nil {
+			run.Results = &results
+		}
 	}
 	if d.Error.Valid {
 		run.Error = d.Error.String
 	}
+	if d.NextAttemptAt.Valid {
+		run.NextAttemptAt = &d.NextAttemptAt.Time
+	}
+	if d.PromptTokens > 0 || d.CompletionTokens > 0 {
+		run.Usage = &models.RunUsage{
+			PromptTokens:     d.PromptTokens,
+			CompletionTokens: d.CompletionTokens,
+			TotalTokens:      d.PromptTokens + d.CompletionTokens,
+			CostCents:        d.CostCents,
+		}
+	}
 	if d.StartedAt.Valid {
 		run.StartedAt = &d.StartedAt.Time
 	}
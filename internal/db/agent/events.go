@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// EventChannel is the Postgres NOTIFY channel carrying agent run events between API instances.
+const EventChannel = "agent_run_events"
+
+// notifyPayloadLimit keeps payloads under Postgres' 8000-byte NOTIFY limit.
+const notifyPayloadLimit = 7900
+
+// EventRelay publishes run events with NOTIFY so that every API instance, including
+// this one, receives them through Listen.
+type EventRelay struct {
+	db          *sqlx.DB
+	databaseURL string
+}
+
+func NewEventRelay(db *sqlx.DB, databaseURL string) *EventRelay {
+	return &EventRelay{db: db, databaseURL: databaseURL}
+}
+
+func (r *EventRelay) Publish(ctx context.Context, event models.RunEvent) error {
+	payload, err := encodeEvent(event)
+	if err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, EventChannel, string(payload)); err != nil {
+		return fmt.Errorf("notify run event: %w", err)
+	}
+	return nil
+}
+
+// Listen passes every event published on EventChannel to deliver until ctx is cancelled.
+// Events sent while the connection is re-established are lost; clients recover by
+// fetching the run.
+func (r *EventRelay) Listen(ctx context.Context, deliver func(models.RunEvent)) error {
+	listener := pq.NewListener(r.databaseURL, time.Second, time.Minute, nil)
+	defer listener.Close()
+	if err := listener.Listen(EventChannel); err != nil {
+		return fmt.Errorf("listen %s: %w", EventChannel, err)
+	}
+
+	ping := time.NewTicker(time.Minute)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ping.C:
+			go func() { _ = listener.Ping() }()
+		case notification := <-listener.Notify:
+			if notification == nil {
+				continue
+			}
+			var event models.RunEvent
+			if err := json.Unmarshal([]byte(notification.Extra), &event); err != nil {
+				continue
+			}
+			deliver(event)
+		}
+	}
+}
+
+// encodeEvent marshals an event for NOTIFY, dropping its partial issues if they would
+// push the payload over the limit.
+func encodeEvent(event models.RunEvent) ([]byte, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("marshal run event: %w", err)
+	}
+	if len(payload) <= notifyPayloadLimit {
+		return payload, nil
+	}
+	event.Issues = nil
+	payload, err = json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("marshal run event: %w", err)
+	}
+	if len(payload) > notifyPayloadLimit {
+		return nil, fmt.Errorf("run event for run %d exceeds the notify payload limit", event.RunID)
+	}
+	return payload, nil
+}
@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+func TestEventRelayPublish(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	relay := NewEventRelay(sqlx.NewDb(db, "postgres"), "")
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_notify($1, $2)`)).
+		WithArgs(EventChannel, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := relay.Publish(context.Background(), models.RunEvent{Type: models.RunEventQueued, RunID: 3}); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestEncodeEventDropsOversizedIssues(t *testing.T) {
+	event := models.RunEvent{Type: models.RunEventProgress, RunID: 3}
+	for i := 0; i < 100; i++ {
+		event.Issues = append(event.Issues, models.Issue{Message: strings.Repeat("x", 100)})
+	}
+
+	payload, err := encodeEvent(event)
+	if err != nil {
+		t.Fatalf("encodeEvent error: %v", err)
+	}
+	if len(payload) > notifyPayloadLimit || strings.Contains(string(payload), `"issues"`) {
+		t.Fatalf("expected issues to be dropped, got %d bytes", len(payload))
+	}
+}
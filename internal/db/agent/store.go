@@ -8,10 +8,14 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/yourusername/draft-forge/internal/models"
 )
 
+// runColumns lists the agent_runs columns scanned into dbAgentRun.
+const runColumns = `id, project_id, agent_type, trigger, status, files_changed, progress, results, error_message, attempts, next_attempt_at, prompt_tokens, completion_tokens, cost_cents, credits_reserved, credits_charged, pipeline_id, depends_on, prompt_hash, editorial_round, started_at, completed_at, created_at`
+
 type Store struct {
 	db *sqlx.DB
 }
@@ -21,13 +25,48 @@ func NewStore(db *sqlx.DB) *Store {
 }
 
 func (s *Store) InsertRun(ctx context.Context, run models.AgentRun) (models.AgentRun, error) {
+	return insertRun(ctx, s.db, run)
+}
+
+// InsertPipeline inserts a pipeline's runs in one transaction. Runs must be in
+// dependency order: dependsOn[i] lists the indexes of earlier runs that runs[i] waits
+// for, and is stored as their IDs.
+func (s *Store) InsertPipeline(ctx context.Context, runs []models.AgentRun, dependsOn [][]int) ([]models.AgentRun, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin pipeline insert: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	inserted := make([]models.AgentRun, 0, len(runs))
+	for i, run := range runs {
+		run.DependsOn = nil
+		for _, dep := range dependsOn[i] {
+			if dep < 0 || dep >= i {
+				return nil, fmt.Errorf("insert pipeline: run %d depends on run %d out of order", i, dep)
+			}
+			run.DependsOn = append(run.DependsOn, inserted[dep].ID)
+		}
+		created, err := insertRun(ctx, tx, run)
+		if err != nil {
+			return nil, err
+		}
+		inserted = append(inserted, created)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit pipeline insert: %w", err)
+	}
+	return inserted, nil
+}
+
+func insertRun(ctx context.Context, q sqlx.QueryerContext, run models.AgentRun) (models.AgentRun, error) {
 	query := `
-		INSERT INTO agent_runs (project_id, agent_type, trigger, status, created_at)
-		VALUES ($1, $2, $3, $4, NOW())
+		INSERT INTO agent_runs (project_id, agent_type, trigger, status, files_changed, credits_reserved, pipeline_id, depends_on, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::uuid, $8, NOW())
 		RETURNING id, created_at
 	`
 	var dbRun dbAgentRun
-	err := s.db.QueryRowxContext(ctx, query, run.ProjectID, run.AgentType, run.Trigger, run.Status).
+	err := q.QueryRowxContext(ctx, query, run.ProjectID, run.AgentType, run.Trigger, run.Status, pq.StringArray(run.FilesChanged), run.CreditsReserved, run.PipelineID, pq.Int64Array(run.DependsOn)).
 		Scan(&dbRun.ID, &dbRun.CreatedAt)
 	if err != nil {
 		return models.AgentRun{}, fmt.Errorf("insert agent run: %w", err)
@@ -36,43 +75,262 @@ func (s *Store) InsertRun(ctx context.Context, run models.AgentRun) (models.Agen
 	dbRun.AgentType = run.AgentType
 	dbRun.Trigger = run.Trigger
 	dbRun.Status = run.Status
+	dbRun.FilesChanged = run.FilesChanged
+	dbRun.CreditsReserved = run.CreditsReserved
+	dbRun.PipelineID = sql.NullString{String: run.PipelineID, Valid: run.PipelineID != ""}
+	dbRun.DependsOn = run.DependsOn
 	return dbRun.toModel(), nil
 }
 
-func (s *Store) MarkRunning(ctx context.Context, id int64, startedAt time.Time) error {
+// ClaimNextRun atomically moves the oldest queued run that is due, and whose dependencies
+// have all completed, to running, counting the attempt, and returns it. Concurrent workers
+// skip rows locked by each other; models.ErrNotFound means nothing is due.
+func (s *Store) ClaimNextRun(ctx context.Context, startedAt time.Time) (models.AgentRun, error) {
+	var dbRun dbAgentRun
+	err := s.db.GetContext(ctx, &dbRun, `
+		UPDATE agent_runs SET status = 'running', started_at = $1, attempts = attempts + 1
+		WHERE id = (
+			SELECT r.id FROM agent_runs r
+			WHERE r.status = 'queued' AND (r.next_attempt_at IS NULL OR r.next_attempt_at <= $1)
+			  AND NOT EXISTS (
+				SELECT 1 FROM agent_runs d WHERE d.id = ANY(r.depends_on) AND d.status <> 'completed'
+			  )
+			ORDER BY r.created_at, r.id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING `+runColumns, startedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.AgentRun{}, models.ErrNotFound
+		}
+		return models.AgentRun{}, fmt.Errorf("claim run: %w", err)
+	}
+	return dbRun.toModel(), nil
+}
+
+// SetPromptHash records the prompt template a run was given.
+func (s *Store) SetPromptHash(ctx context.Context, id int64, hash string) error {
 	_, err := s.db.ExecContext(ctx, `
-		UPDATE agent_runs SET status = $1, started_at = $2 WHERE id = $3
-	`, "running", startedAt, id)
+		UPDATE agent_runs SET prompt_hash = $1 WHERE id = $2
+	`, hash, id)
 	if err != nil {
-		return fmt.Errorf("mark running: %w", err)
+		return fmt.Errorf("set prompt hash: %w", err)
 	}
 	return nil
 }
 
-func (s *Store) MarkCompleted(ctx context.Context, id int64, results json.RawMessage, completedAt time.Time) error {
+// SetEditorialRound tags a run with the editing round it was made in.
+func (s *Store) SetEditorialRound(ctx context.Context, id int64, round string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE agent_runs SET editorial_round = $1 WHERE id = $2
+	`, round, id)
+	if err != nil {
+		return fmt.Errorf("set editorial round: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) UpdateProgress(ctx context.Context, id int64, progress models.RunProgress) error {
+	payload, err := json.Marshal(progress)
+	if err != nil {
+		return fmt.Errorf("marshal progress: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		UPDATE agent_runs SET progress = $1 WHERE id = $2
+	`, payload, id)
+	if err != nil {
+		return fmt.Errorf("update progress: %w", err)
+	}
+	return nil
+}
+
+// Heartbeat records that the worker executing a run is still alive.
+func (s *Store) Heartbeat(ctx context.Context, id int64, at time.Time) error {
 	_, err := s.db.ExecContext(ctx, `
-		UPDATE agent_runs SET status = $1, results = $2, completed_at = $3 WHERE id = $4
+		UPDATE agent_runs SET heartbeat_at = $1 WHERE id = $2 AND status = 'running'
+	`, at, id)
+	if err != nil {
+		return fmt.Errorf("heartbeat: %w", err)
+	}
+	return nil
+}
+
+// ClaimStaleRuns takes over running runs that have had no heartbeat (or, before the
+// first one, no start) since staleBefore, typically because their instance stopped
+// mid-run. Their heartbeat is moved to now so concurrent sweeps do not both claim them.
+func (s *Store) ClaimStaleRuns(ctx context.Context, staleBefore, now time.Time) ([]models.AgentRun, error) {
+	var rows []dbAgentRun
+	err := s.db.SelectContext(ctx, &rows, `
+		UPDATE agent_runs SET heartbeat_at = $2
+		WHERE id IN (
+			SELECT id FROM agent_runs
+			WHERE status = 'running' AND GREATEST(heartbeat_at, started_at) < $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+runColumns, staleBefore, now)
+	if err != nil {
+		return nil, fmt.Errorf("claim stale runs: %w", err)
+	}
+	runs := make([]models.AgentRun, 0, len(rows))
+	for _, r := range rows {
+		runs = append(runs, r.toModel())
+	}
+	return runs, nil
+}
+
+// MarkCompleted and the other terminal updates below only move runs that are still
+// running. models.ErrNotFound means the run was cancelled (possibly by another
+// instance) or otherwise left the running state first.
+func (s *Store) MarkCompleted(ctx context.Context, id int64, results json.RawMessage, completedAt time.Time) error {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE agent_runs SET status = $1, results = $2, completed_at = $3 WHERE id = $4 AND status = 'running'
 	`, "completed", results, completedAt, id)
 	if err != nil {
 		return fmt.Errorf("mark completed: %w", err)
 	}
-	return nil
+	return requireAffected(res, "mark completed")
 }
 
 func (s *Store) MarkFailed(ctx context.Context, id int64, message string, completedAt time.Time) error {
-	_, err := s.db.ExecContext(ctx, `
-		UPDATE agent_runs SET status = $1, error_message = $2, completed_at = $3 WHERE id = $4
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE agent_runs SET status = $1, error_message = $2, completed_at = $3 WHERE id = $4 AND status = 'running'
 	`, "failed", message, completedAt, id)
 	if err != nil {
 		return fmt.Errorf("mark failed: %w", err)
 	}
+	return requireAffected(res, "mark failed")
+}
+
+// ScheduleRetry puts a failed run back on the queue, not to be claimed before nextAttemptAt.
+func (s *Store) ScheduleRetry(ctx context.Context, id int64, message string, nextAttemptAt time.Time) error {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE agent_runs SET status = $1, error_message = $2, next_attempt_at = $3, started_at = NULL, progress = NULL WHERE id = $4 AND status = 'running'
+	`, "queued", message, nextAttemptAt, id)
+	if err != nil {
+		return fmt.Errorf("schedule retry: %w", err)
+	}
+	return requireAffected(res, "schedule retry")
+}
+
+func (s *Store) MarkDeadLetter(ctx context.Context, id int64, message string, completedAt time.Time) error {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE agent_runs SET status = $1, error_message = $2, next_attempt_at = NULL, completed_at = $3 WHERE id = $4 AND status = 'running'
+	`, "dead_letter", message, completedAt, id)
+	if err != nil {
+		return fmt.Errorf("mark dead letter: %w", err)
+	}
+	return requireAffected(res, "mark dead letter")
+}
+
+// MarkCancelled stops a queued or running run. models.ErrNotFound means the run has
+// already finished.
+func (s *Store) MarkCancelled(ctx context.Context, id int64, completedAt time.Time) error {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE agent_runs SET status = $1, next_attempt_at = NULL, completed_at = $2
+		WHERE id = $3 AND status IN ('queued', 'running')
+	`, "cancelled", completedAt, id)
+	if err != nil {
+		return fmt.Errorf("mark cancelled: %w", err)
+	}
+	return requireAffected(res, "mark cancelled")
+}
+
+// requireAffected returns models.ErrNotFound when a status-guarded update matched no
+// run, i.e. the run had already left the status the update expected (for example,
+// cancelled from another instance while it was running).
+func requireAffected(res sql.Result, op string) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return models.ErrNotFound
+	}
 	return nil
 }
 
+// CancelDependents cancels the queued runs that depend, directly or through other runs,
+// on runID, recording message as their error. It returns the cancelled runs.
+func (s *Store) CancelDependents(ctx context.Context, runID int64, message string, completedAt time.Time) ([]models.AgentRun, error) {
+	var rows []dbAgentRun
+	err := s.db.SelectContext(ctx, &rows, `
+		WITH RECURSIVE downstream AS (
+			SELECT id FROM agent_runs WHERE $1 = ANY(depends_on)
+			UNION
+			SELECT r.id FROM agent_runs r JOIN downstream ds ON ds.id = ANY(r.depends_on)
+		)
+		UPDATE agent_runs SET status = 'cancelled', error_message = $2, next_attempt_at = NULL, completed_at = $3
+		WHERE id IN (SELECT id FROM downstream) AND status = 'queued'
+		RETURNING `+runColumns, runID, message, completedAt)
+	if err != nil {
+		return nil, fmt.Errorf("cancel dependents: %w", err)
+	}
+	runs := make([]models.AgentRun, 0, len(rows))
+	for _, r := range rows {
+		runs = append(runs, r.toModel())
+	}
+	return runs, nil
+}
+
+func (s *Store) MarkTimedOut(ctx context.Context, id int64, message string, completedAt time.Time) error {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE agent_runs SET status = $1, error_message = $2, completed_at = $3 WHERE id = $4 AND status = 'running'
+	`, "timed_out", message, completedAt, id)
+	if err != nil {
+		return fmt.Errorf("mark timed out: %w", err)
+	}
+	return requireAffected(res, "mark timed out")
+}
+
+// requeueSet resets a run for a fresh start: attempt budget, usage totals and credit
+// reservation ($2).
+const requeueSet = `
+		SET status = 'queued', attempts = 0, next_attempt_at = NULL, error_message = NULL,
+			progress = NULL, started_at = NULL, completed_at = NULL,
+			prompt_tokens = 0, completion_tokens = 0, cost_cents = 0,
+			credits_reserved = $2, credits_charged = 0, credits_settled = FALSE`
+
+// RequeueRun moves a dead-lettered run back to the queue with a fresh attempt budget,
+// usage totals and credit reservation. models.ErrNotFound means no dead-lettered run
+// has that ID.
+func (s *Store) RequeueRun(ctx context.Context, id int64, creditsReserved int) (models.AgentRun, error) {
+	var dbRun dbAgentRun
+	err := s.db.GetContext(ctx, &dbRun, `
+		UPDATE agent_runs`+requeueSet+`
+		WHERE id = $1 AND status = 'dead_letter'
+		RETURNING `+runColumns, id, creditsReserved)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.AgentRun{}, models.ErrNotFound
+		}
+		return models.AgentRun{}, fmt.Errorf("requeue run: %w", err)
+	}
+	return dbRun.toModel(), nil
+}
+
+// RequeueDependent moves a pipeline run cancelled by CancelDependents back to the queue,
+// as RequeueRun does. message must match the one it was cancelled with; models.ErrNotFound
+// means no such cancelled run exists.
+func (s *Store) RequeueDependent(ctx context.Context, id int64, message string, creditsReserved int) (models.AgentRun, error) {
+	var dbRun dbAgentRun
+	err := s.db.GetContext(ctx, &dbRun, `
+		UPDATE agent_runs`+requeueSet+`
+		WHERE id = $1 AND status = 'cancelled' AND error_message = $3
+		RETURNING `+runColumns, id, creditsReserved, message)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.AgentRun{}, models.ErrNotFound
+		}
+		return models.AgentRun{}, fmt.Errorf("requeue dependent: %w", err)
+	}
+	return dbRun.toModel(), nil
+}
+
 func (s *Store) GetRun(ctx context.Context, id int64) (models.AgentRun, error) {
 	var dbRun dbAgentRun
 	err := s.db.GetContext(ctx, &dbRun, `
-		SELECT id, project_id, agent_type, trigger, status, results, error_message, started_at, completed_at, created_at
+		SELECT `+runColumns+`
 		FROM agent_runs
 		WHERE id = $1
 	`, id)
@@ -4,14 +4,12 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/jmoiron/sqlx"
-
 	"github.com/yourusername/draft-forge/internal/models"
 )
 
 func (s *Store) ListRuns(ctx context.Context, projectID int64) ([]models.AgentRun, error) {
 	query := `
-		SELECT id, project_id, agent_type, trigger, status, results, error_message, started_at, completed_at, created_at
+		SELECT ` + runColumns + `
 		FROM agent_runs
 		WHERE project_id = $1
 		ORDER BY created_at DESC
@@ -26,3 +24,41 @@ func (s *Store) ListRuns(ctx context.Context, projectID int64) ([]models.AgentRu
 	}
 	return out, nil
 }
+
+// ListDeadLetterRuns returns a project's dead-lettered runs, most recent first.
+func (s *Store) ListDeadLetterRuns(ctx context.Context, projectID int64) ([]models.AgentRun, error) {
+	query := `
+		SELECT ` + runColumns + `
+		FROM agent_runs
+		WHERE project_id = $1 AND status = 'dead_letter'
+		ORDER BY completed_at DESC, id DESC
+	`
+	var runs []dbAgentRun
+	if err := s.db.SelectContext(ctx, &runs, query, projectID); err != nil {
+		return nil, fmt.Errorf("list dead letter runs: %w", err)
+	}
+	out := make([]models.AgentRun, 0, len(runs))
+	for _, r := range runs {
+		out = append(out, r.toModel())
+	}
+	return out, nil
+}
+
+// ListPipelineRuns returns the runs of a pipeline in the order they were queued.
+func (s *Store) ListPipelineRuns(ctx context.Context, pipelineID string) ([]models.AgentRun, error) {
+	query := `
+		SELECT ` + runColumns + `
+		FROM agent_runs
+		WHERE pipeline_id = $1::uuid
+		ORDER BY id
+	`
+	var runs []dbAgentRun
+	if err := s.db.SelectContext(ctx, &runs, query, pipelineID); err != nil {
+		return nil, fmt.Errorf("list pipeline runs: %w", err)
+	}
+	out := make([]models.AgentRun, 0, len(runs))
+	for _, r := range runs {
+		out = append(out, r.toModel())
+	}
+	return out, nil
+}
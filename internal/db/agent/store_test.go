@@ -0,0 +1,235 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+var claimQuery = regexp.QuoteMeta(`
+		UPDATE agent_runs SET status = 'running', started_at = $1, attempts = attempts + 1
+		WHERE id = (
+			SELECT r.id FROM agent_runs r
+			WHERE r.status = 'queued' AND (r.next_attempt_at IS NULL OR r.next_attempt_at <= $1)
+			  AND NOT EXISTS (
+				SELECT 1 FROM agent_runs d WHERE d.id = ANY(r.depends_on) AND d.status <> 'completed'
+			  )
+			ORDER BY r.created_at, r.id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING ` + runColumns)
+
+func TestClaimNextRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(sqlx.NewDb(db, "postgres"))
+	startedAt := time.Now()
+
+	mock.ExpectQuery(claimQuery).
+		WithArgs(startedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "agent_type", "trigger", "status", "files_changed", "progress", "results", "error_message", "attempts", "next_attempt_at", "prompt_tokens", "completion_tokens", "cost_cents", "credits_reserved", "credits_charged", "started_at", "completed_at", "created_at"}).
+			AddRow(int64(7), int64(1), "continuity", "pr", "running", []byte(`{chapters/01.md,chapters/02.md}`), nil, nil, nil, 2, nil, 1200, 300, 0.036, 5, 0, startedAt, nil, startedAt))
+
+	run, err := store.ClaimNextRun(context.Background(), startedAt)
+	if err != nil {
+		t.Fatalf("ClaimNextRun error: %v", err)
+	}
+	if run.ID != 7 || run.Status != "running" || run.StartedAt == nil || run.Attempts != 2 {
+		t.Fatalf("unexpected run %+v", run)
+	}
+	if run.Usage == nil || run.Usage.TotalTokens != 1500 || run.Usage.CostCents != 0.036 {
+		t.Fatalf("unexpected usage %+v", run.Usage)
+	}
+	if len(run.FilesChanged) != 2 || run.FilesChanged[1] != "chapters/02.md" {
+		t.Fatalf("unexpected files changed %+v", run.FilesChanged)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestClaimNextRunEmptyQueue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery(claimQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = store.ClaimNextRun(context.Background(), time.Now())
+	if !errors.Is(err, models.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRequeueRunRequiresDeadLetter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE id = $1 AND status = 'dead_letter'`)).
+		WithArgs(int64(9), 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = store.RequeueRun(context.Background(), 9, 4)
+	if !errors.Is(err, models.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestRequeueDependentRequiresUpstreamCancellation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE id = $1 AND status = 'cancelled' AND error_message = $3`)).
+		WithArgs(int64(11), 4, "upstream failed").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = store.RequeueDependent(context.Background(), 11, "upstream failed", 4)
+	if !errors.Is(err, models.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestMarkCompletedRequiresRunningRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE agent_runs SET status = $1, results = $2, completed_at = $3 WHERE id = $4 AND status = 'running'`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = store.MarkCompleted(context.Background(), 9, []byte(`{}`), time.Now())
+	if !errors.Is(err, models.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a run no longer running, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestClaimStaleRunsReclaimsAbandonedRuns(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(sqlx.NewDb(db, "postgres"))
+	now := time.Now()
+	staleBefore := now.Add(-time.Minute)
+	startedAt := now.Add(-time.Hour)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE status = 'running' AND GREATEST(heartbeat_at, started_at) < $1`)).
+		WithArgs(staleBefore, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "agent_type", "status", "started_at", "created_at"}).
+			AddRow(int64(7), int64(1), "style", "running", startedAt, startedAt))
+
+	runs, err := store.ClaimStaleRuns(context.Background(), staleBefore, now)
+	if err != nil {
+		t.Fatalf("ClaimStaleRuns error: %v", err)
+	}
+	if len(runs) != 1 || runs[0].ID != 7 || runs[0].Status != "running" {
+		t.Fatalf("expected the abandoned run to be reclaimed, got %+v", runs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestInsertPipelineLinksDependencies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(sqlx.NewDb(db, "postgres"))
+	now := time.Now()
+	insert := regexp.QuoteMeta(`INSERT INTO agent_runs (project_id, agent_type, trigger, status, files_changed, credits_reserved, pipeline_id, depends_on, created_at)`)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(insert).
+		WithArgs(int64(1), "continuity", "manual", "queued", sqlmock.AnyArg(), 2, "p1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(10), now))
+	mock.ExpectQuery(insert).
+		WithArgs(int64(1), "style", "manual", "queued", sqlmock.AnyArg(), 3, "p1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(11), now))
+	mock.ExpectCommit()
+
+	runs, err := store.InsertPipeline(context.Background(), []models.AgentRun{
+		{ProjectID: 1, AgentType: "continuity", Trigger: "manual", Status: "queued", CreditsReserved: 2, PipelineID: "p1"},
+		{ProjectID: 1, AgentType: "style", Trigger: "manual", Status: "queued", CreditsReserved: 3, PipelineID: "p1"},
+	}, [][]int{nil, {0}})
+	if err != nil {
+		t.Fatalf("InsertPipeline error: %v", err)
+	}
+	if len(runs) != 2 || runs[1].ID != 11 || len(runs[1].DependsOn) != 1 || runs[1].DependsOn[0] != 10 || runs[1].PipelineID != "p1" {
+		t.Fatalf("unexpected runs %+v", runs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestCancelDependents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(sqlx.NewDb(db, "postgres"))
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE id IN (SELECT id FROM downstream) AND status = 'queued'`)).
+		WithArgs(int64(10), "upstream failed", now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "agent_type", "status", "error_message", "pipeline_id", "depends_on", "completed_at", "created_at"}).
+			AddRow(int64(11), int64(1), "style", "cancelled", "upstream failed", "p1", []byte(`{10}`), now, now))
+
+	runs, err := store.CancelDependents(context.Background(), 10, "upstream failed", now)
+	if err != nil {
+		t.Fatalf("CancelDependents error: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Status != "cancelled" || runs[0].PipelineID != "p1" || runs[0].DependsOn[0] != 10 {
+		t.Fatalf("unexpected runs %+v", runs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
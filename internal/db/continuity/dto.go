@@ -0,0 +1,38 @@
+package continuity
+
+import (
+	"database/sql"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+type dbFact struct {
+	ID         int64        `db:"id"`
+	ProjectID  int64        `db:"project_id"`
+	SourcePath string       `db:"source_path"`
+	Kind       string       `db:"kind"`
+	Subject    string       `db:"subject"`
+	Attribute  string       `db:"attribute"`
+	Value      string       `db:"value"`
+	Line       int          `db:"line"`
+	Excerpt    string       `db:"excerpt"`
+	CreatedAt  sql.NullTime `db:"created_at"`
+}
+
+func (d dbFact) toModel() models.ContinuityFact {
+	fact := models.ContinuityFact{
+		ID:         d.ID,
+		ProjectID:  d.ProjectID,
+		SourcePath: d.SourcePath,
+		Kind:       d.Kind,
+		Subject:    d.Subject,
+		Attribute:  d.Attribute,
+		Value:      d.Value,
+		Line:       d.Line,
+		Excerpt:    d.Excerpt,
+	}
+	if d.CreatedAt.Valid {
+		fact.CreatedAt = d.CreatedAt.Time
+	}
+	return fact
+}
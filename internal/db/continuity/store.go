@@ -0,0 +1,99 @@
+package continuity
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// Store persists ContinuityBot's per-project fact ledger.
+type Store struct {
+	db *sqlx.DB
+}
+
+func NewStore(db *sqlx.DB) *Store {
+	return &Store{db: db}
+}
+
+// SourceHashes returns the content hash recorded for each ledger source of a project.
+func (s *Store) SourceHashes(ctx context.Context, projectID int64) (map[string]string, error) {
+	var rows []struct {
+		SourcePath  string `db:"source_path"`
+		ContentHash string `db:"content_hash"`
+	}
+	err := s.db.SelectContext(ctx, &rows, `
+		SELECT source_path, content_hash FROM continuity_sources WHERE project_id = $1
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list continuity sources: %w", err)
+	}
+
+	hashes := make(map[string]string, len(rows))
+	for _, row := range rows {
+		hashes[row.SourcePath] = row.ContentHash
+	}
+	return hashes, nil
+}
+
+// ListFacts returns every fact in a project's ledger ordered by source and line.
+func (s *Store) ListFacts(ctx context.Context, projectID int64) ([]models.ContinuityFact, error) {
+	var rows []dbFact
+	err := s.db.SelectContext(ctx, &rows, `
+		SELECT id, project_id, source_path, kind, subject, attribute, value, line, excerpt, created_at
+		FROM continuity_facts
+		WHERE project_id = $1
+		ORDER BY source_path, line, id
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list continuity facts: %w", err)
+	}
+
+	facts := make([]models.ContinuityFact, 0, len(rows))
+	for _, row := range rows {
+		facts = append(facts, row.toModel())
+	}
+	return facts, nil
+}
+
+// ReplaceSources swaps the facts recorded for each source file and records its content
+// hash, all in one transaction so a failure leaves the ledger as it was.
+func (s *Store) ReplaceSources(ctx context.Context, projectID int64, sources []models.ContinuitySource) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin ledger update: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, source := range sources {
+		if _, err := tx.ExecContext(ctx, `
+			DELETE FROM continuity_facts WHERE project_id = $1 AND source_path = $2
+		`, projectID, source.Path); err != nil {
+			return fmt.Errorf("delete continuity facts: %w", err)
+		}
+
+		for _, fact := range source.Facts {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO continuity_facts (project_id, source_path, kind, subject, attribute, value, line, excerpt)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			`, projectID, source.Path, fact.Kind, fact.Subject, fact.Attribute, fact.Value, fact.Line, fact.Excerpt); err != nil {
+				return fmt.Errorf("insert continuity fact: %w", err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO continuity_sources (project_id, source_path, content_hash, updated_at)
+			VALUES ($1, $2, $3, NOW())
+			ON CONFLICT (project_id, source_path) DO UPDATE SET content_hash = EXCLUDED.content_hash, updated_at = NOW()
+		`, projectID, source.Path, source.Hash); err != nil {
+			return fmt.Errorf("record continuity source: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit ledger update: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package continuity
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+func newMockStore(t *testing.T) (*Store, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewStore(sqlx.NewDb(db, "postgres")), mock
+}
+
+func TestReplaceSources(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM continuity_facts WHERE project_id = $1 AND source_path = $2`)).
+		WithArgs(int64(1), "chapters/01.md").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO continuity_facts`)).
+		WithArgs(int64(1), "chapters/01.md", models.FactAttribute, "Mara", "eyes", "green", 4, "Mara's green eyes").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO continuity_sources`)).
+		WithArgs(int64(1), "chapters/01.md", "abc123").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM continuity_facts WHERE project_id = $1 AND source_path = $2`)).
+		WithArgs(int64(1), "chapters/02.md").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO continuity_sources`)).
+		WithArgs(int64(1), "chapters/02.md", "def456").
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	err := store.ReplaceSources(context.Background(), 1, []models.ContinuitySource{
+		{Path: "chapters/01.md", Hash: "abc123", Facts: []models.ContinuityFact{
+			{Kind: models.FactAttribute, Subject: "Mara", Attribute: "eyes", Value: "green", Line: 4, Excerpt: "Mara's green eyes"},
+		}},
+		{Path: "chapters/02.md", Hash: "def456"},
+	})
+	if err == nil {
+		t.Fatal("expected the failed source to fail the whole update")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestSourceHashes(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT source_path, content_hash FROM continuity_sources WHERE project_id = $1`)).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"source_path", "content_hash"}).
+			AddRow("chapters/01.md", "abc123").
+			AddRow("docs/characters/mara.md", "def456"))
+
+	hashes, err := store.SourceHashes(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("SourceHashes error: %v", err)
+	}
+	if len(hashes) != 2 || hashes["docs/characters/mara.md"] != "def456" {
+		t.Fatalf("unexpected hashes %v", hashes)
+	}
+}
@@ -0,0 +1,29 @@
+package credits
+
+import (
+	"database/sql"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+type dbBalance struct {
+	CreditsRemaining      int            `db:"credits_remaining"`
+	CreditsTotal          int            `db:"credits_total"`
+	SubscriptionTier      sql.NullString `db:"subscription_tier"`
+	SubscriptionExpiresAt sql.NullTime   `db:"subscription_expires_at"`
+}
+
+func (d dbBalance) toModel() models.CreditBalance {
+	balance := models.CreditBalance{
+		CreditsRemaining: d.CreditsRemaining,
+		CreditsTotal:     d.CreditsTotal,
+		SubscriptionTier: "free",
+	}
+	if d.SubscriptionTier.Valid {
+		balance.SubscriptionTier = d.SubscriptionTier.String
+	}
+	if d.SubscriptionExpiresAt.Valid {
+		balance.SubscriptionExpiresAt = &d.SubscriptionExpiresAt.Time
+	}
+	return balance
+}
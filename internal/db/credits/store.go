@@ -0,0 +1,148 @@
+package credits
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// Store keeps ai_credits balances and the credit reservations on agent_runs.
+type Store struct {
+	db *sqlx.DB
+}
+
+func NewStore(db *sqlx.DB) *Store {
+	return &Store{db: db}
+}
+
+// openAccount creates a user's credit account with the free grant if it does not exist.
+func openAccount(ctx context.Context, exec sqlx.ExecerContext, userID int64, grant int) error {
+	_, err := exec.ExecContext(ctx, `
+		INSERT INTO ai_credits (user_id, credits_remaining, credits_total)
+		VALUES ($1, $2, $2)
+		ON CONFLICT (user_id) DO NOTHING
+	`, userID, grant)
+	if err != nil {
+		return fmt.Errorf("open credit account: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) Balance(ctx context.Context, userID int64, grant int) (models.CreditBalance, error) {
+	if err := openAccount(ctx, s.db, userID, grant); err != nil {
+		return models.CreditBalance{}, err
+	}
+	var balance dbBalance
+	err := s.db.GetContext(ctx, &balance, `
+		SELECT credits_remaining, credits_total, subscription_tier, subscription_expires_at
+		FROM ai_credits WHERE user_id = $1
+	`, userID)
+	if err != nil {
+		return models.CreditBalance{}, fmt.Errorf("get credit balance: %w", err)
+	}
+	return balance.toModel(), nil
+}
+
+func (s *Store) Reserve(ctx context.Context, projectID int64, amount, grant int) (bool, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("begin reserve tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var userID int64
+	if err := tx.GetContext(ctx, &userID, `SELECT user_id FROM projects WHERE id = $1`, projectID); err != nil {
+		if err == sql.ErrNoRows {
+			return false, models.ErrNotFound
+		}
+		return false, fmt.Errorf("get project owner: %w", err)
+	}
+	if err := openAccount(ctx, tx, userID, grant); err != nil {
+		return false, err
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE ai_credits SET credits_remaining = credits_remaining - $1
+		WHERE user_id = $2 AND credits_remaining >= $1
+	`, amount, userID)
+	if err != nil {
+		return false, fmt.Errorf("reserve credits: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("reserve credits: %w", err)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit reserve: %w", err)
+	}
+	return true, nil
+}
+
+func (s *Store) Release(ctx context.Context, projectID int64, amount int) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE ai_credits SET credits_remaining = credits_remaining + $1
+		WHERE user_id = (SELECT user_id FROM projects WHERE id = $2)
+	`, amount, projectID)
+	if err != nil {
+		return fmt.Errorf("release credits: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) Settle(ctx context.Context, runID int64, refund bool) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin settle tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var run struct {
+		UserID   int64 `db:"user_id"`
+		Reserved int   `db:"credits_reserved"`
+		Cost     int   `db:"cost_credits"`
+		Settled  bool  `db:"credits_settled"`
+	}
+	err = tx.GetContext(ctx, &run, `
+		SELECT p.user_id, r.credits_reserved, CEIL(r.cost_cents)::INTEGER AS cost_credits, r.credits_settled
+		FROM agent_runs r JOIN projects p ON p.id = r.project_id
+		WHERE r.id = $1
+		FOR UPDATE OF r
+	`, runID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.ErrNotFound
+		}
+		return fmt.Errorf("get run reservation: %w", err)
+	}
+	if run.Settled {
+		return nil
+	}
+
+	charged := run.Cost
+	if refund {
+		charged = 0
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE ai_credits SET credits_remaining = credits_remaining + $1 WHERE user_id = $2
+	`, run.Reserved-charged, run.UserID); err != nil {
+		return fmt.Errorf("settle credits: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE agent_runs SET credits_charged = $1, credits_settled = TRUE WHERE id = $2
+	`, charged, runID); err != nil {
+		return fmt.Errorf("mark run settled: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit settle: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package credits
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+)
+
+func newMockStore(t *testing.T) (*Store, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewStore(sqlx.NewDb(db, "postgres")), mock
+}
+
+func TestReserveInsufficientBalance(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT user_id FROM projects WHERE id = $1`)).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(int64(4)))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO ai_credits`)).
+		WithArgs(int64(4), 500).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`WHERE user_id = $2 AND credits_remaining >= $1`)).
+		WithArgs(30, int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	ok, err := store.Reserve(context.Background(), 1, 30, 500)
+	if err != nil || ok {
+		t.Fatalf("expected the reservation to be refused, got %v, %v", ok, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestSettleChargesActualCost(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FOR UPDATE OF r`)).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "credits_reserved", "cost_credits", "credits_settled"}).
+			AddRow(int64(4), 30, 12, false))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE ai_credits SET credits_remaining = credits_remaining + $1 WHERE user_id = $2`)).
+		WithArgs(18, int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE agent_runs SET credits_charged = $1, credits_settled = TRUE WHERE id = $2`)).
+		WithArgs(12, int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := store.Settle(context.Background(), 7, false); err != nil {
+		t.Fatalf("Settle error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestSettleIsIdempotent(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FOR UPDATE OF r`)).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "credits_reserved", "cost_credits", "credits_settled"}).
+			AddRow(int64(4), 30, 12, true))
+	mock.ExpectRollback()
+
+	if err := store.Settle(context.Background(), 7, true); err != nil {
+		t.Fatalf("Settle error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_agent_runs_queue;
+
+ALTER TABLE agent_runs DROP COLUMN IF EXISTS heartbeat_at;
+ALTER TABLE agent_runs DROP COLUMN IF EXISTS files_changed;
@@ -0,0 +1,9 @@
+-- Persist the inputs a background worker needs to execute a queued run
+ALTER TABLE agent_runs ADD COLUMN IF NOT EXISTS files_changed TEXT[] NOT NULL DEFAULT '{}';
+
+-- Workers claim the oldest queued run first
+CREATE INDEX IF NOT EXISTS idx_agent_runs_queue ON agent_runs(status, created_at);
+
+-- Workers refresh heartbeat_at while a run executes so runs orphaned by a crashed
+-- instance can be found and reclaimed
+ALTER TABLE agent_runs ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMPTZ;
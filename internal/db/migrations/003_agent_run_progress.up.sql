@@ -0,0 +1,2 @@
+-- Per-chunk progress for long-running map/reduce agent runs
+ALTER TABLE agent_runs ADD COLUMN IF NOT EXISTS progress JSONB;
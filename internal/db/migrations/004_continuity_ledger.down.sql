@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS continuity_facts;
+DROP TABLE IF EXISTS continuity_sources;
@@ -0,0 +1,25 @@
+-- ContinuityBot's per-project fact ledger. Facts are replaced per source file; the
+-- source's content hash lets incremental runs skip files that have not changed.
+CREATE TABLE IF NOT EXISTS continuity_sources (
+    project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    source_path TEXT NOT NULL,
+    content_hash VARCHAR(64) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (project_id, source_path)
+);
+
+CREATE TABLE IF NOT EXISTS continuity_facts (
+    id SERIAL PRIMARY KEY,
+    project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    source_path TEXT NOT NULL,
+    kind VARCHAR(50) NOT NULL, -- 'attribute', 'relationship', 'location', 'object', 'status'
+    subject VARCHAR(255) NOT NULL,
+    attribute VARCHAR(255) NOT NULL,
+    value TEXT NOT NULL,
+    line INTEGER NOT NULL DEFAULT 0,
+    excerpt TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_continuity_facts_source ON continuity_facts(project_id, source_path);
+CREATE INDEX IF NOT EXISTS idx_continuity_facts_subject ON continuity_facts(project_id, subject, attribute);
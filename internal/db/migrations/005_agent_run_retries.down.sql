@@ -0,0 +1,2 @@
+ALTER TABLE agent_runs DROP COLUMN IF EXISTS next_attempt_at;
+ALTER TABLE agent_runs DROP COLUMN IF EXISTS attempts;
@@ -0,0 +1,4 @@
+-- Retry bookkeeping: runs that fail on transient provider errors are requeued with
+-- backoff until they reach the attempt limit, then parked as 'dead_letter'
+ALTER TABLE agent_runs ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE agent_runs ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,10 @@
+ALTER TABLE agent_runs DROP COLUMN IF EXISTS cost_cents;
+ALTER TABLE agent_runs DROP COLUMN IF EXISTS completion_tokens;
+ALTER TABLE agent_runs DROP COLUMN IF EXISTS prompt_tokens;
+
+DROP INDEX IF EXISTS idx_ai_usage_run_id;
+
+ALTER TABLE ai_usage_log ALTER COLUMN cost_cents TYPE INTEGER USING ROUND(cost_cents);
+ALTER TABLE ai_usage_log DROP COLUMN IF EXISTS completion_tokens;
+ALTER TABLE ai_usage_log DROP COLUMN IF EXISTS prompt_tokens;
+ALTER TABLE ai_usage_log DROP COLUMN IF EXISTS run_id;
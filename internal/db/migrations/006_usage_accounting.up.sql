@@ -0,0 +1,13 @@
+-- Per-call token accounting, linked to the agent run that made the call
+ALTER TABLE ai_usage_log ADD COLUMN IF NOT EXISTS run_id INTEGER REFERENCES agent_runs(id) ON DELETE SET NULL;
+ALTER TABLE ai_usage_log ADD COLUMN IF NOT EXISTS prompt_tokens INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE ai_usage_log ADD COLUMN IF NOT EXISTS completion_tokens INTEGER NOT NULL DEFAULT 0;
+-- A single call usually costs a fraction of a cent
+ALTER TABLE ai_usage_log ALTER COLUMN cost_cents TYPE NUMERIC(12,4);
+
+CREATE INDEX IF NOT EXISTS idx_ai_usage_run_id ON ai_usage_log(run_id);
+
+-- Running totals surfaced on each run
+ALTER TABLE agent_runs ADD COLUMN IF NOT EXISTS prompt_tokens INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE agent_runs ADD COLUMN IF NOT EXISTS completion_tokens INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE agent_runs ADD COLUMN IF NOT EXISTS cost_cents NUMERIC(12,4) NOT NULL DEFAULT 0;
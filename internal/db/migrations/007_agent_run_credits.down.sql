@@ -0,0 +1,3 @@
+ALTER TABLE agent_runs DROP COLUMN IF EXISTS credits_settled;
+ALTER TABLE agent_runs DROP COLUMN IF EXISTS credits_charged;
+ALTER TABLE agent_runs DROP COLUMN IF EXISTS credits_reserved;
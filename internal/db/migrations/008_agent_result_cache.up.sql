@@ -0,0 +1,15 @@
+-- Per-chunk agent results, reused by later runs over unchanged content. The content
+-- hash covers the chunk and the context documents sent with it; the prompt version
+-- changes whenever the agent's built-in or custom prompt does.
+CREATE TABLE IF NOT EXISTS agent_result_cache (
+    project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    agent_type VARCHAR(50) NOT NULL,
+    model VARCHAR(255) NOT NULL,
+    prompt_version VARCHAR(100) NOT NULL,
+    content_hash VARCHAR(64) NOT NULL,
+    result JSONB NOT NULL,
+    hits INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_hit_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (project_id, agent_type, model, prompt_version, content_hash)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_agent_runs_pipeline;
+ALTER TABLE agent_runs DROP COLUMN IF EXISTS depends_on;
+ALTER TABLE agent_runs DROP COLUMN IF EXISTS pipeline_id;
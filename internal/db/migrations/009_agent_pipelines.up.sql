@@ -0,0 +1,6 @@
+-- Pipelines are groups of runs queued together. A run is not claimed until every run
+-- in depends_on has completed.
+ALTER TABLE agent_runs ADD COLUMN IF NOT EXISTS pipeline_id UUID;
+ALTER TABLE agent_runs ADD COLUMN IF NOT EXISTS depends_on INTEGER[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_agent_runs_pipeline ON agent_runs(pipeline_id) WHERE pipeline_id IS NOT NULL;
@@ -0,0 +1,28 @@
+-- Edits proposed by agent runs, awaiting review. revision is the SHA-256 of the file the
+-- edit was made against; original holds the lines it replaces so later changes to them
+-- can be detected.
+CREATE TABLE IF NOT EXISTS agent_suggestions (
+    id SERIAL PRIMARY KEY,
+    project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    run_id INTEGER NOT NULL REFERENCES agent_runs(id) ON DELETE CASCADE,
+    issue_id VARCHAR(100) NOT NULL,
+    agent_type VARCHAR(50) NOT NULL,
+    file_path TEXT NOT NULL,
+    revision VARCHAR(64) NOT NULL,
+    start_line INTEGER NOT NULL,
+    end_line INTEGER NOT NULL,
+    original TEXT NOT NULL,
+    replacement TEXT NOT NULL,
+    diff TEXT NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    state VARCHAR(20) NOT NULL DEFAULT 'open', -- 'open', 'accepted', 'rejected', 'stale'
+    reason TEXT,
+    branch VARCHAR(255),
+    commit_sha VARCHAR(64),
+    patch_path TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE(run_id, issue_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_suggestions_project_state ON agent_suggestions(project_id, state);
@@ -0,0 +1 @@
+ALTER TABLE agent_runs DROP COLUMN IF EXISTS prompt_hash;
@@ -0,0 +1,2 @@
+-- SHA-256 of the prompt template a run was given, for reproducing its results.
+ALTER TABLE agent_runs ADD COLUMN IF NOT EXISTS prompt_hash VARCHAR(64);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_agent_runs_editorial_round;
+ALTER TABLE agent_runs DROP COLUMN IF EXISTS editorial_round;
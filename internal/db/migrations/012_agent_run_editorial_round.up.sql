@@ -0,0 +1,3 @@
+-- EDITORIAL.md round a run was made in, for grouping results per round.
+ALTER TABLE agent_runs ADD COLUMN IF NOT EXISTS editorial_round VARCHAR(255);
+CREATE INDEX IF NOT EXISTS idx_agent_runs_editorial_round ON agent_runs(project_id, editorial_round);
@@ -2,6 +2,7 @@ package project
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
@@ -94,3 +95,21 @@ func (s *Store) ListProjects(ctx context.Context, userID int64) ([]models.Projec
 	}
 	return projects, nil
 }
+
+func (s *Store) GetProject(ctx context.Context, id int64) (models.Project, error) {
+	query := `
+		SELECT id, user_id, name, slug, COALESCE(description, '') AS description, project_type,
+		       github_repo_id, github_repo_name, github_repo_url, created_at, updated_at
+		FROM projects
+		WHERE id = $1
+	`
+
+	var dbp dbProject
+	if err := s.db.GetContext(ctx, &dbp, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return models.Project{}, models.ErrNotFound
+		}
+		return models.Project{}, fmt.Errorf("get project: %w", err)
+	}
+	return dbp.toModel(), nil
+}
@@ -0,0 +1,54 @@
+package resultcache
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// Store persists per-chunk agent results keyed by content hash.
+type Store struct {
+	db *sqlx.DB
+}
+
+func NewStore(db *sqlx.DB) *Store {
+	return &Store{db: db}
+}
+
+// Get returns the cached result for key and records the hit. The boolean is false when
+// nothing is cached.
+func (s *Store) Get(ctx context.Context, key models.ResultCacheKey) (json.RawMessage, bool, error) {
+	var result []byte
+	err := s.db.QueryRowxContext(ctx, `
+		UPDATE agent_result_cache SET hits = hits + 1, last_hit_at = NOW()
+		WHERE project_id = $1 AND agent_type = $2 AND model = $3 AND prompt_version = $4 AND content_hash = $5
+		RETURNING result
+	`, key.ProjectID, key.AgentType, key.Model, key.PromptVersion, key.ContentHash).Scan(&result)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("get cached result: %w", err)
+	}
+	return json.RawMessage(result), true, nil
+}
+
+// Put stores a result, replacing any entry with the same key.
+func (s *Store) Put(ctx context.Context, key models.ResultCacheKey, result json.RawMessage) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO agent_result_cache (project_id, agent_type, model, prompt_version, content_hash, result)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (project_id, agent_type, model, prompt_version, content_hash)
+		DO UPDATE SET result = EXCLUDED.result, created_at = NOW()
+	`, key.ProjectID, key.AgentType, key.Model, key.PromptVersion, key.ContentHash, []byte(result))
+	if err != nil {
+		return fmt.Errorf("put cached result: %w", err)
+	}
+	return nil
+}
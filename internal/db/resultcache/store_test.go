@@ -0,0 +1,61 @@
+package resultcache
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+func newMockStore(t *testing.T) (*Store, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewStore(sqlx.NewDb(db, "postgres")), mock
+}
+
+var testKey = models.ResultCacheKey{ProjectID: 1, AgentType: "style", Model: "test/model", PromptVersion: "style/1", ContentHash: "abc123"}
+
+func TestGet(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE agent_result_cache SET hits = hits + 1`)).
+		WithArgs(int64(1), "style", "test/model", "style/1", "abc123").
+		WillReturnRows(sqlmock.NewRows([]string{"result"}).AddRow([]byte(`{"summary":"cached"}`)))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE agent_result_cache SET hits = hits + 1`)).
+		WithArgs(int64(1), "style", "test/model", "style/1", "abc123").
+		WillReturnRows(sqlmock.NewRows([]string{"result"}))
+
+	result, ok, err := store.Get(context.Background(), testKey)
+	if err != nil || !ok || string(result) != `{"summary":"cached"}` {
+		t.Fatalf("expected a cache hit, got %q %v %v", result, ok, err)
+	}
+	if _, ok, err := store.Get(context.Background(), testKey); err != nil || ok {
+		t.Fatalf("expected a cache miss, got %v %v", ok, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestPut(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO agent_result_cache`)).
+		WithArgs(int64(1), "style", "test/model", "style/1", "abc123", []byte(`{"summary":"fresh"}`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := store.Put(context.Background(), testKey, []byte(`{"summary":"fresh"}`)); err != nil {
+		t.Fatalf("Put error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
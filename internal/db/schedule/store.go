@@ -0,0 +1,154 @@
+package schedule
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// Store keeps agent schedules in the "schedules" key of projects.settings. Every write
+// holds a per-project advisory lock so API instances never fire or edit a project's
+// schedules concurrently.
+type Store struct {
+	db *sqlx.DB
+}
+
+func NewStore(db *sqlx.DB) *Store {
+	return &Store{db: db}
+}
+
+// ListProjectSchedules returns the stored schedules of every project.
+func (s *Store) ListProjectSchedules(ctx context.Context) ([]models.ProjectSchedules, error) {
+	var rows []struct {
+		ProjectID int64  `db:"id"`
+		Schedules []byte `db:"schedules"`
+	}
+	if err := s.db.SelectContext(ctx, &rows, `
+		SELECT id, settings->'schedules' AS schedules FROM projects ORDER BY id
+	`); err != nil {
+		return nil, fmt.Errorf("list schedules: %w", err)
+	}
+
+	out := make([]models.ProjectSchedules, 0, len(rows))
+	for _, row := range rows {
+		ps, err := decodeSchedules(row.ProjectID, row.Schedules)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, ps)
+	}
+	return out, nil
+}
+
+// GetProjectSchedules returns one project's stored schedules.
+func (s *Store) GetProjectSchedules(ctx context.Context, projectID int64) (models.ProjectSchedules, error) {
+	raw, err := getSchedules(ctx, s.db, projectID)
+	if err != nil {
+		return models.ProjectSchedules{}, err
+	}
+	return decodeSchedules(projectID, raw)
+}
+
+// UpdateSchedules replaces a project's schedules with the result of fn, waiting for any
+// other writer to finish first. An error from fn aborts the update.
+func (s *Store) UpdateSchedules(ctx context.Context, projectID int64, fn func(models.ProjectSchedules) ([]models.AgentSchedule, error)) ([]models.AgentSchedule, error) {
+	schedules, _, err := s.update(ctx, projectID, true, fn)
+	return schedules, err
+}
+
+// TryUpdateSchedules is UpdateSchedules without waiting: it reports false, and leaves
+// the schedules untouched, when another instance holds the project's lock.
+func (s *Store) TryUpdateSchedules(ctx context.Context, projectID int64, fn func(models.ProjectSchedules) ([]models.AgentSchedule, error)) (bool, error) {
+	_, locked, err := s.update(ctx, projectID, false, fn)
+	return locked, err
+}
+
+func (s *Store) update(ctx context.Context, projectID int64, wait bool, fn func(models.ProjectSchedules) ([]models.AgentSchedule, error)) ([]models.AgentSchedule, bool, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("begin schedule update: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if wait {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('agent_schedules'), $1)`, projectID); err != nil {
+			return nil, false, fmt.Errorf("lock schedules: %w", err)
+		}
+	} else {
+		var locked bool
+		if err := tx.QueryRowxContext(ctx, `SELECT pg_try_advisory_xact_lock(hashtext('agent_schedules'), $1)`, projectID).Scan(&locked); err != nil {
+			return nil, false, fmt.Errorf("lock schedules: %w", err)
+		}
+		if !locked {
+			return nil, false, nil
+		}
+	}
+
+	raw, err := getSchedules(ctx, tx, projectID)
+	if err != nil {
+		return nil, true, err
+	}
+	current, err := decodeSchedules(projectID, raw)
+	if err != nil {
+		return nil, true, err
+	}
+	schedules, err := fn(current)
+	if err != nil {
+		return nil, true, err
+	}
+
+	stored := make([]models.AgentSchedule, len(schedules))
+	for i, sch := range schedules {
+		sch.ProjectID = projectID
+		sch.NextRunAt = nil
+		stored[i] = sch
+	}
+	payload, err := json.Marshal(stored)
+	if err != nil {
+		return nil, true, fmt.Errorf("encode schedules: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE projects
+		SET settings = jsonb_set(COALESCE(settings, '{}'::jsonb), '{schedules}', $2::jsonb), updated_at = NOW()
+		WHERE id = $1
+	`, projectID, string(payload)); err != nil {
+		return nil, true, fmt.Errorf("update schedules: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, true, fmt.Errorf("commit schedules: %w", err)
+	}
+	return stored, true, nil
+}
+
+func getSchedules(ctx context.Context, q sqlx.QueryerContext, projectID int64) ([]byte, error) {
+	var raw []byte
+	err := q.QueryRowxContext(ctx, `SELECT settings->'schedules' FROM projects WHERE id = $1`, projectID).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get schedules: %w", err)
+	}
+	return raw, nil
+}
+
+func decodeSchedules(projectID int64, raw []byte) (models.ProjectSchedules, error) {
+	ps := models.ProjectSchedules{ProjectID: projectID}
+	if len(raw) == 0 {
+		return ps, nil
+	}
+	if err := json.Unmarshal(raw, &ps.Schedules); err != nil {
+		return models.ProjectSchedules{}, fmt.Errorf("decode schedules for project %d: %w", projectID, err)
+	}
+	for i := range ps.Schedules {
+		ps.Schedules[i].ProjectID = projectID
+	}
+	ps.Configured = true
+	return ps, nil
+}
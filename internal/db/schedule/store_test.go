@@ -0,0 +1,108 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+func newMockStore(t *testing.T) (*Store, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewStore(sqlx.NewDb(db, "postgres")), mock
+}
+
+func TestListProjectSchedules(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, settings->'schedules' AS schedules FROM projects ORDER BY id`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "schedules"}).
+			AddRow(int64(1), nil).
+			AddRow(int64(2), []byte(`[{"id":1,"agent_type":"style","cron":"@daily","enabled":true}]`)))
+
+	projects, err := store.ListProjectSchedules(context.Background())
+	if err != nil {
+		t.Fatalf("ListProjectSchedules error: %v", err)
+	}
+	if len(projects) != 2 || projects[0].Configured || !projects[1].Configured {
+		t.Fatalf("expected only the second project to have saved schedules, got %+v", projects)
+	}
+	if sch := projects[1].Schedules; len(sch) != 1 || sch[0].ProjectID != 2 || sch[0].AgentType != "style" {
+		t.Fatalf("unexpected schedules: %+v", sch)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestUpdateSchedules(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock(hashtext('agent_schedules'), $1)`)).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT settings->'schedules' FROM projects WHERE id = $1`)).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"schedules"}).AddRow(nil))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE projects`)).
+		WithArgs(int64(1), `[{"id":1,"project_id":1,"agent_type":"style","cron":"@daily","enabled":true}]`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	schedules, err := store.UpdateSchedules(context.Background(), 1, func(ps models.ProjectSchedules) ([]models.AgentSchedule, error) {
+		if ps.Configured {
+			t.Fatalf("expected an unconfigured project, got %+v", ps)
+		}
+		return []models.AgentSchedule{{ID: 1, AgentType: "style", Cron: "@daily", Enabled: true}}, nil
+	})
+	if err != nil || len(schedules) != 1 || schedules[0].ProjectID != 1 {
+		t.Fatalf("unexpected UpdateSchedules result: %+v %v", schedules, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestTryUpdateSchedulesWhenLocked(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT pg_try_advisory_xact_lock(hashtext('agent_schedules'), $1)`)).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(false))
+	mock.ExpectRollback()
+
+	locked, err := store.TryUpdateSchedules(context.Background(), 1, func(models.ProjectSchedules) ([]models.AgentSchedule, error) {
+		t.Fatal("fn must not run without the lock")
+		return nil, nil
+	})
+	if err != nil || locked {
+		t.Fatalf("expected the update to be skipped, got %v %v", locked, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetProjectSchedulesNotFound(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT settings->'schedules' FROM projects WHERE id = $1`)).
+		WithArgs(int64(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"schedules"}))
+
+	if _, err := store.GetProjectSchedules(context.Background(), 9); !errors.Is(err, models.ErrNotFound) {
+		t.Fatalf("expected models.ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package suggestion
+
+import (
+	"database/sql"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+type dbSuggestion struct {
+	ID          int64          `db:"id"`
+	ProjectID   int64          `db:"project_id"`
+	RunID       int64          `db:"run_id"`
+	IssueID     string         `db:"issue_id"`
+	AgentType   string         `db:"agent_type"`
+	FilePath    string         `db:"file_path"`
+	Revision    string         `db:"revision"`
+	StartLine   int            `db:"start_line"`
+	EndLine     int            `db:"end_line"`
+	Original    string         `db:"original"`
+	Replacement string         `db:"replacement"`
+	Diff        string         `db:"diff"`
+	Message     string         `db:"message"`
+	State       string         `db:"state"`
+	Reason      sql.NullString `db:"reason"`
+	Branch      sql.NullString `db:"branch"`
+	CommitSHA   sql.NullString `db:"commit_sha"`
+	PatchPath   sql.NullString `db:"patch_path"`
+	CreatedAt   sql.NullTime   `db:"created_at"`
+	ResolvedAt  sql.NullTime   `db:"resolved_at"`
+}
+
+func (d dbSuggestion) toModel() models.Suggestion {
+	suggestion := models.Suggestion{
+		ID:          d.ID,
+		ProjectID:   d.ProjectID,
+		RunID:       d.RunID,
+		IssueID:     d.IssueID,
+		AgentType:   d.AgentType,
+		File:        d.FilePath,
+		Revision:    d.Revision,
+		StartLine:   d.StartLine,
+		EndLine:     d.EndLine,
+		Original:    d.Original,
+		Replacement: d.Replacement,
+		Diff:        d.Diff,
+		Message:     d.Message,
+		State:       d.State,
+		Reason:      d.Reason.String,
+		Branch:      d.Branch.String,
+		Commit:      d.CommitSHA.String,
+		PatchPath:   d.PatchPath.String,
+	}
+	if d.CreatedAt.Valid {
+		suggestion.CreatedAt = d.CreatedAt.Time
+	}
+	if d.ResolvedAt.Valid {
+		suggestion.ResolvedAt = &d.ResolvedAt.Time
+	}
+	return suggestion
+}
@@ -0,0 +1,115 @@
+package suggestion
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+const suggestionColumns = `id, project_id, run_id, issue_id, agent_type, file_path, revision, start_line, end_line, original, replacement, diff, message, state, reason, branch, commit_sha, patch_path, created_at, resolved_at`
+
+// Store persists the edits agent runs suggest and their review outcome.
+type Store struct {
+	db *sqlx.DB
+}
+
+func NewStore(db *sqlx.DB) *Store {
+	return &Store{db: db}
+}
+
+// InsertSuggestions records a run's suggestions as open. Suggestions already recorded
+// for the same run and issue are left as they are.
+func (s *Store) InsertSuggestions(ctx context.Context, suggestions []models.Suggestion) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin suggestions insert: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, sg := range suggestions {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO agent_suggestions (project_id, run_id, issue_id, agent_type, file_path, revision, start_line, end_line, original, replacement, diff, message, state)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, 'open')
+			ON CONFLICT (run_id, issue_id) DO NOTHING
+		`, sg.ProjectID, sg.RunID, sg.IssueID, sg.AgentType, sg.File, sg.Revision, sg.StartLine, sg.EndLine, sg.Original, sg.Replacement, sg.Diff, sg.Message); err != nil {
+			return fmt.Errorf("insert suggestion: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit suggestions insert: %w", err)
+	}
+	return nil
+}
+
+// ListSuggestions returns a project's suggestions, newest first. An empty state lists
+// every state.
+func (s *Store) ListSuggestions(ctx context.Context, projectID int64, state string) ([]models.Suggestion, error) {
+	var rows []dbSuggestion
+	err := s.db.SelectContext(ctx, &rows, `
+		SELECT `+suggestionColumns+`
+		FROM agent_suggestions
+		WHERE project_id = $1 AND ($2 = '' OR state = $2)
+		ORDER BY created_at DESC, id DESC
+	`, projectID, state)
+	if err != nil {
+		return nil, fmt.Errorf("list suggestions: %w", err)
+	}
+
+	suggestions := make([]models.Suggestion, 0, len(rows))
+	for _, row := range rows {
+		suggestions = append(suggestions, row.toModel())
+	}
+	return suggestions, nil
+}
+
+func (s *Store) GetSuggestion(ctx context.Context, projectID, id int64) (models.Suggestion, error) {
+	var row dbSuggestion
+	err := s.db.GetContext(ctx, &row, `
+		SELECT `+suggestionColumns+`
+		FROM agent_suggestions
+		WHERE id = $1 AND project_id = $2
+	`, id, projectID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Suggestion{}, models.ErrNotFound
+	}
+	if err != nil {
+		return models.Suggestion{}, fmt.Errorf("get suggestion: %w", err)
+	}
+	return row.toModel(), nil
+}
+
+// MarkStale moves an open suggestion to stale.
+func (s *Store) MarkStale(ctx context.Context, id int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE agent_suggestions SET state = 'stale' WHERE id = $1 AND state = 'open'
+	`, id)
+	if err != nil {
+		return fmt.Errorf("mark suggestion stale: %w", err)
+	}
+	return nil
+}
+
+// Resolve records the review outcome of an open suggestion. models.ErrNotFound means it
+// was no longer open.
+func (s *Store) Resolve(ctx context.Context, sg models.Suggestion) (models.Suggestion, error) {
+	var row dbSuggestion
+	err := s.db.GetContext(ctx, &row, `
+		UPDATE agent_suggestions
+		SET state = $2, reason = NULLIF($3, ''), branch = NULLIF($4, ''), commit_sha = NULLIF($5, ''),
+			patch_path = NULLIF($6, ''), resolved_at = $7
+		WHERE id = $1 AND state = 'open'
+		RETURNING `+suggestionColumns, sg.ID, sg.State, sg.Reason, sg.Branch, sg.Commit, sg.PatchPath, sg.ResolvedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Suggestion{}, models.ErrNotFound
+	}
+	if err != nil {
+		return models.Suggestion{}, fmt.Errorf("resolve suggestion: %w", err)
+	}
+	return row.toModel(), nil
+}
@@ -0,0 +1,80 @@
+package suggestion
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+func newMockStore(t *testing.T) (*Store, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewStore(sqlx.NewDb(db, "postgres")), mock
+}
+
+func TestInsertSuggestions(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`ON CONFLICT (run_id, issue_id) DO NOTHING`)).
+		WithArgs(int64(1), int64(9), "continuity-abc", "continuity", "chapters/01.md", "rev", 3, 3, "blue", "green", "@@ -3,1 +3,1 @@", "Eyes.").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := store.InsertSuggestions(context.Background(), []models.Suggestion{{
+		ProjectID: 1, RunID: 9, IssueID: "continuity-abc", AgentType: "continuity", File: "chapters/01.md",
+		Revision: "rev", StartLine: 3, EndLine: 3, Original: "blue", Replacement: "green", Diff: "@@ -3,1 +3,1 @@", Message: "Eyes.",
+	}})
+	if err != nil {
+		t.Fatalf("InsertSuggestions error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestListSuggestions(t *testing.T) {
+	store, mock := newMockStore(t)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE project_id = $1 AND ($2 = '' OR state = $2)`)).
+		WithArgs(int64(1), "open").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "run_id", "issue_id", "file_path", "start_line", "end_line", "state", "reason", "created_at"}).
+			AddRow(int64(4), int64(1), int64(9), "continuity-abc", "chapters/01.md", 3, 3, "open", nil, now))
+
+	list, err := store.ListSuggestions(context.Background(), 1, "open")
+	if err != nil {
+		t.Fatalf("ListSuggestions error: %v", err)
+	}
+	if len(list) != 1 || list[0].File != "chapters/01.md" || list[0].State != "open" || list[0].Reason != "" {
+		t.Fatalf("unexpected suggestions %+v", list)
+	}
+}
+
+func TestResolveRequiresOpenSuggestion(t *testing.T) {
+	store, mock := newMockStore(t)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE id = $1 AND state = 'open'`)).
+		WithArgs(int64(4), "rejected", "Intentional.", "", "", "", &now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := store.Resolve(context.Background(), models.Suggestion{ID: 4, State: "rejected", Reason: "Intentional.", ResolvedAt: &now})
+	if !errors.Is(err, models.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+package usage
+
+import (
+	"database/sql"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+type dbUsageRow struct {
+	ProjectID        sql.NullInt64 `db:"project_id"`
+	AgentType        string        `db:"agent_type"`
+	Calls            int           `db:"calls"`
+	PromptTokens     int           `db:"prompt_tokens"`
+	CompletionTokens int           `db:"completion_tokens"`
+	TotalTokens      int           `db:"total_tokens"`
+	CostCents        float64       `db:"cost_cents"`
+}
+
+func (d dbUsageRow) toTotals() models.UsageTotals {
+	return models.UsageTotals{
+		Calls:            d.Calls,
+		PromptTokens:     d.PromptTokens,
+		CompletionTokens: d.CompletionTokens,
+		TotalTokens:      d.TotalTokens,
+		CostCents:        d.CostCents,
+	}
+}
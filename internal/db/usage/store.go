@@ -0,0 +1,125 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// Store records model calls in ai_usage_log and aggregates them into reports.
+type Store struct {
+	db *sqlx.DB
+}
+
+func NewStore(db *sqlx.DB) *Store {
+	return &Store{db: db}
+}
+
+// RecordUsage logs a model call against the project's owner and adds its tokens and
+// cost to the run's running totals.
+func (s *Store) RecordUsage(ctx context.Context, entry models.UsageEntry) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin usage tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var runID any
+	if entry.RunID > 0 {
+		runID = entry.RunID
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO ai_usage_log (user_id, project_id, run_id, agent_type, model_name, prompt_tokens, completion_tokens, tokens_used, cost_cents, status, error_message)
+		SELECT user_id, id, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, '')
+		FROM projects WHERE id = $1
+	`, entry.ProjectID, runID, entry.AgentType, entry.Model, entry.PromptTokens, entry.CompletionTokens,
+		entry.PromptTokens+entry.CompletionTokens, entry.CostCents, entry.Status, entry.Error)
+	if err != nil {
+		return fmt.Errorf("insert usage: %w", err)
+	}
+
+	if entry.RunID > 0 {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE agent_runs
+			SET prompt_tokens = prompt_tokens + $1, completion_tokens = completion_tokens + $2, cost_cents = cost_cents + $3
+			WHERE id = $4
+		`, entry.PromptTokens, entry.CompletionTokens, entry.CostCents, entry.RunID)
+		if err != nil {
+			return fmt.Errorf("update run usage: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit usage: %w", err)
+	}
+	return nil
+}
+
+// usageAggregates is the select list shared by every report query.
+const usageAggregates = `COUNT(*) AS calls,
+	COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens,
+	COALESCE(SUM(completion_tokens), 0) AS completion_tokens,
+	COALESCE(SUM(tokens_used), 0) AS total_tokens,
+	COALESCE(SUM(cost_cents), 0) AS cost_cents`
+
+// UsageReport aggregates a user's model calls overall, per project and per agent.
+func (s *Store) UsageReport(ctx context.Context, filter models.UsageFilter) (models.UsageReport, error) {
+	where, args := usageWhere(filter)
+	report := models.UsageReport{ByProject: []models.UsageGroup{}, ByAgent: []models.UsageGroup{}}
+
+	var totals dbUsageRow
+	if err := s.db.GetContext(ctx, &totals, `SELECT `+usageAggregates+` FROM ai_usage_log WHERE `+where, args...); err != nil {
+		return models.UsageReport{}, fmt.Errorf("usage totals: %w", err)
+	}
+	report.Totals = totals.toTotals()
+
+	var byProject []dbUsageRow
+	if err := s.db.SelectContext(ctx, &byProject, `
+		SELECT project_id, `+usageAggregates+`
+		FROM ai_usage_log WHERE `+where+` AND project_id IS NOT NULL
+		GROUP BY project_id ORDER BY cost_cents DESC, project_id
+	`, args...); err != nil {
+		return models.UsageReport{}, fmt.Errorf("usage by project: %w", err)
+	}
+	for _, row := range byProject {
+		report.ByProject = append(report.ByProject, models.UsageGroup{ProjectID: row.ProjectID.Int64, UsageTotals: row.toTotals()})
+	}
+
+	var byAgent []dbUsageRow
+	if err := s.db.SelectContext(ctx, &byAgent, `
+		SELECT agent_type, `+usageAggregates+`
+		FROM ai_usage_log WHERE `+where+`
+		GROUP BY agent_type ORDER BY cost_cents DESC, agent_type
+	`, args...); err != nil {
+		return models.UsageReport{}, fmt.Errorf("usage by agent: %w", err)
+	}
+	for _, row := range byAgent {
+		report.ByAgent = append(report.ByAgent, models.UsageGroup{AgentType: row.AgentType, UsageTotals: row.toTotals()})
+	}
+	return report, nil
+}
+
+// usageWhere builds the WHERE clause for a filter; the user is always constrained.
+func usageWhere(filter models.UsageFilter) (string, []any) {
+	conditions := []string{"user_id = $1"}
+	args := []any{filter.UserID}
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, strings.Replace(condition, "?", "$"+strconv.Itoa(len(args)), 1))
+	}
+	if filter.ProjectID > 0 {
+		add("project_id = ?", filter.ProjectID)
+	}
+	if filter.Since != nil {
+		add("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		add("created_at < ?", *filter.Until)
+	}
+	return strings.Join(conditions, " AND "), args
+}
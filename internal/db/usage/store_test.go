@@ -0,0 +1,80 @@
+package usage
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+func newMockStore(t *testing.T) (*Store, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewStore(sqlx.NewDb(db, "postgres")), mock
+}
+
+func TestRecordUsage(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO ai_usage_log`)).
+		WithArgs(int64(1), int64(7), "style", "openai/gpt-4o-mini", 1000, 200, 1200, 0.027, models.UsageSuccess, "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE agent_runs`)).
+		WithArgs(1000, 200, 0.027, int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := store.RecordUsage(context.Background(), models.UsageEntry{
+		RunID: 7, ProjectID: 1, AgentType: "style", Model: "openai/gpt-4o-mini",
+		PromptTokens: 1000, CompletionTokens: 200, CostCents: 0.027, Status: models.UsageSuccess,
+	})
+	if err != nil {
+		t.Fatalf("RecordUsage error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestUsageReport(t *testing.T) {
+	store, mock := newMockStore(t)
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"calls", "prompt_tokens", "completion_tokens", "total_tokens", "cost_cents"}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM ai_usage_log WHERE user_id = $1 AND project_id = $2 AND created_at >= $3`)).
+		WithArgs(int64(4), int64(1), since).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(3, 3000, 600, 3600, 0.81))
+	mock.ExpectQuery(regexp.QuoteMeta(`GROUP BY project_id`)).
+		WillReturnRows(sqlmock.NewRows(append([]string{"project_id"}, columns...)).AddRow(int64(1), 3, 3000, 600, 3600, 0.81))
+	mock.ExpectQuery(regexp.QuoteMeta(`GROUP BY agent_type`)).
+		WillReturnRows(sqlmock.NewRows(append([]string{"agent_type"}, columns...)).
+			AddRow("style", 2, 2000, 400, 2400, 0.54).
+			AddRow("timeline", 1, 1000, 200, 1200, 0.27))
+
+	report, err := store.UsageReport(context.Background(), models.UsageFilter{UserID: 4, ProjectID: 1, Since: &since})
+	if err != nil {
+		t.Fatalf("UsageReport error: %v", err)
+	}
+	if report.Totals.Calls != 3 || report.Totals.TotalTokens != 3600 {
+		t.Fatalf("unexpected totals %+v", report.Totals)
+	}
+	if len(report.ByProject) != 1 || report.ByProject[0].ProjectID != 1 {
+		t.Fatalf("unexpected project breakdown %+v", report.ByProject)
+	}
+	if len(report.ByAgent) != 2 || report.ByAgent[1].AgentType != "timeline" || report.ByAgent[1].CostCents != 0.27 {
+		t.Fatalf("unexpected agent breakdown %+v", report.ByAgent)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
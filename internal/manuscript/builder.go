@@ -0,0 +1,230 @@
+package manuscript
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultBudget is the token budget used when a Builder is created without one.
+const DefaultBudget = 32000
+
+var ErrPathOutsideRoot = errors.New("path escapes working tree")
+
+// Request selects which parts of the working tree to load.
+type Request struct {
+	// ChangedFiles are repo-relative paths, loaded first and in the given order.
+	ChangedFiles []string
+	// StoryBible loads CREATIVE.md, docs/characters/* and docs/world/*.
+	StoryBible bool
+	// Editorial loads EDITORIAL.md.
+	Editorial bool
+	// Sources loads the BibTeX and CSL-JSON bibliographies in docs/sources.
+	Sources bool
+}
+
+// Builder assembles agent context from a project's working tree.
+//
+// Changed files are always loaded whole, in request order; long files are split into
+// chunks afterwards (see ChunkDocuments). Reference material is limited to the token
+// budget and prioritised, highest first:
+//  1. EDITORIAL.md
+//  2. CREATIVE.md
+//  3. character sheets mentioned in the changed files, then the remaining sheets
+//  4. world documents
+//
+// Reference documents that do not fit are omitted whole.
+type Builder struct {
+	Budget int
+}
+
+func NewBuilder(budget int) *Builder {
+	if budget <= 0 {
+		budget = DefaultBudget
+	}
+	return &Builder{Budget: budget}
+}
+
+// Build loads the requested documents from root.
+func (b *Builder) Build(root string, req Request) (Context, error) {
+	ctx := Context{Budget: b.Budget}
+	remaining := b.Budget
+
+	var changedText strings.Builder
+	for _, rel := range req.ChangedFiles {
+		content, clean, err := readFile(root, rel)
+		if errors.Is(err, fs.ErrNotExist) {
+			ctx.Missing = append(ctx.Missing, clean)
+			continue
+		}
+		if err != nil {
+			return Context{}, err
+		}
+		changedText.WriteString(strings.ToLower(content))
+		ctx.Changed = append(ctx.Changed, newDocument(clean, KindChapter, content))
+	}
+
+	var optional []Document
+	if req.Editorial {
+		docs, err := loadFiles(root, KindEditorial, EditorialPath)
+		if err != nil {
+			return Context{}, err
+		}
+		optional = append(optional, docs...)
+	}
+	if req.StoryBible {
+		creative, err := loadFiles(root, KindCreative, "CREATIVE.md")
+		if err != nil {
+			return Context{}, err
+		}
+		characters, err := loadDir(root, KindCharacter, "docs/characters", isTextFile)
+		if err != nil {
+			return Context{}, err
+		}
+		world, err := loadDir(root, KindWorld, "docs/world", isTextFile)
+		if err != nil {
+			return Context{}, err
+		}
+		optional = append(optional, creative...)
+		optional = append(optional, prioritiseMentioned(characters, changedText.String())...)
+		optional = append(optional, world...)
+	}
+
+	if req.Sources {
+		sources, err := loadDir(root, KindSource, "docs/sources", isBibliographyFile)
+		if err != nil {
+			return Context{}, err
+		}
+		ctx.Sources = sources
+	}
+
+	for _, doc := range optional {
+		if doc.Tokens > remaining {
+			ctx.Omitted = append(ctx.Omitted, doc.Path)
+			continue
+		}
+		remaining -= doc.Tokens
+		ctx.Documents = append(ctx.Documents, doc)
+	}
+
+	ctx.Tokens = b.Budget - remaining
+	return ctx, nil
+}
+
+func newDocument(rel string, kind Kind, content string) Document {
+	return Document{Path: rel, Kind: kind, Content: content, Tokens: EstimateTokens(content)}
+}
+
+// prioritiseMentioned moves documents whose file stem appears in text to the front,
+// keeping the relative order otherwise.
+func prioritiseMentioned(docs []Document, text string) []Document {
+	var mentioned, rest []Document
+	for _, doc := range docs {
+		stem := strings.TrimSuffix(path.Base(doc.Path), path.Ext(doc.Path))
+		name := strings.ToLower(strings.NewReplacer("-", " ", "_", " ").Replace(stem))
+		if name != "" && strings.Contains(text, name) {
+			mentioned = append(mentioned, doc)
+		} else {
+			rest = append(rest, doc)
+		}
+	}
+	return append(mentioned, rest...)
+}
+
+func loadFiles(root string, kind Kind, rels ...string) ([]Document, error) {
+	var docs []Document
+	for _, rel := range rels {
+		content, clean, err := readFile(root, rel)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, newDocument(clean, kind, content))
+	}
+	return docs, nil
+}
+
+// loadDir loads the visible files directly under dir accepted by match, sorted by name.
+func loadDir(root string, kind Kind, dir string, match func(name string) bool) ([]Document, error) {
+	rels, err := listDir(root, dir, match)
+	if err != nil {
+		return nil, err
+	}
+	return loadFiles(root, kind, rels...)
+}
+
+// ListChapters returns the repo-relative paths of the chapter files in chapters/,
+// sorted by name. It is used when a run should cover the whole manuscript.
+func ListChapters(root string) ([]string, error) {
+	return listDir(root, "chapters", isTextFile)
+}
+
+// listDir returns the visible files directly under dir accepted by match, sorted by name.
+func listDir(root, dir string, match func(name string) bool) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(root, filepath.FromSlash(dir)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", dir, err)
+	}
+
+	var rels []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") || !match(name) {
+			continue
+		}
+		rels = append(rels, path.Join(dir, name))
+	}
+	sort.Strings(rels)
+	return rels, nil
+}
+
+func isTextFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".md", ".markdown", ".txt", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+func isBibliographyFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".bib", ".json":
+		return true
+	}
+	return false
+}
+
+// ReadFile reads a repo-relative file from the working tree at root, refusing paths
+// that escape it.
+func ReadFile(root, rel string) (string, error) {
+	content, _, err := readFile(root, rel)
+	return content, err
+}
+
+// readFile reads a repo-relative path, refusing paths that escape root.
+// It returns the cleaned slash-separated path alongside the content.
+func readFile(root, rel string) (string, string, error) {
+	clean := path.Clean(strings.TrimPrefix(filepath.ToSlash(rel), "/"))
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", clean, fmt.Errorf("%w: %s", ErrPathOutsideRoot, rel)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(clean)))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", clean, err
+		}
+		return "", clean, fmt.Errorf("read %s: %w", clean, err)
+	}
+	return string(data), clean, nil
+}
@@ -0,0 +1,126 @@
+package manuscript
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for rel, content := range files {
+		target := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+	return root
+}
+
+func paths(docs []Document) []string {
+	out := make([]string, 0, len(docs))
+	for _, d := range docs {
+		out = append(out, d.Path)
+	}
+	return out
+}
+
+func TestBuildPrioritisesChangedFilesAndMentionedCharacters(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"chapters/01.md":             "Alice opened the door.\n",
+		"CREATIVE.md":                "Tone: hopeful.\n",
+		"EDITORIAL.md":               "Goal: tighten dialogue.\n",
+		"docs/characters/bob.md":     "Bob is tall.\n",
+		"docs/characters/alice.md":   "Alice has green eyes.\n",
+		"docs/characters/.gitkeep":   "",
+		"docs/world/city.md":         "The city floats.\n",
+		"docs/world/notes/ignore.md": "nested dirs are not loaded",
+	})
+
+	ctx, err := NewBuilder(1000).Build(root, Request{
+		ChangedFiles: []string{"chapters/01.md", "chapters/99.md"},
+		StoryBible:   true,
+		Editorial:    true,
+	})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	if got := paths(ctx.Changed); len(got) != 1 || got[0] != "chapters/01.md" {
+		t.Fatalf("expected changed chapter, got %v", got)
+	}
+	want := []string{"EDITORIAL.md", "CREATIVE.md", "docs/characters/alice.md", "docs/characters/bob.md", "docs/world/city.md"}
+	if got := paths(ctx.Documents); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected order %v, got %v", want, got)
+	}
+	if len(ctx.Missing) != 1 || ctx.Missing[0] != "chapters/99.md" {
+		t.Fatalf("expected missing chapter to be reported, got %v", ctx.Missing)
+	}
+	if ctx.Tokens == 0 || ctx.Tokens > ctx.Budget {
+		t.Fatalf("unexpected token accounting %d/%d", ctx.Tokens, ctx.Budget)
+	}
+}
+
+func TestBuildRespectsBudgetForReferenceDocuments(t *testing.T) {
+	chapter := strings.Repeat("The rain kept falling on the harbour.\n", 200)
+	root := writeTree(t, map[string]string{
+		"chapters/01.md":         chapter,
+		"CREATIVE.md":            strings.Repeat("Long style guide. ", 50),
+		"docs/characters/bob.md": "Bob.\n",
+	})
+
+	ctx, err := NewBuilder(50).Build(root, Request{ChangedFiles: []string{"chapters/01.md"}, StoryBible: true})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	if len(ctx.Changed) != 1 || ctx.Changed[0].Content != chapter {
+		t.Fatal("expected changed chapter to be loaded whole regardless of budget")
+	}
+	if got := paths(ctx.Documents); len(got) != 1 || got[0] != "docs/characters/bob.md" {
+		t.Fatalf("expected only the small character sheet to fit, got %v", got)
+	}
+	if ctx.Tokens > ctx.Budget {
+		t.Fatalf("expected tokens within budget %d, got %d", ctx.Budget, ctx.Tokens)
+	}
+	if strings.Join(ctx.Omitted, ",") != "CREATIVE.md" {
+		t.Fatalf("unexpected omitted files %v", ctx.Omitted)
+	}
+}
+
+func TestBuildLoadsSourcesOutsideBudget(t *testing.T) {
+	bib := strings.Repeat("@book{key, title = {Long}}\n", 100)
+	root := writeTree(t, map[string]string{
+		"docs/sources/refs.bib":   bib,
+		"docs/sources/refs.json":  "[]",
+		"docs/sources/README.md":  "How to cite.",
+		"docs/sources/.draft.bib": "@book{hidden, title = {x}}",
+	})
+
+	ctx, err := NewBuilder(10).Build(root, Request{Sources: true})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	if got := paths(ctx.Sources); strings.Join(got, ",") != "docs/sources/refs.bib,docs/sources/refs.json" {
+		t.Fatalf("expected only visible bibliography files, got %v", got)
+	}
+	if ctx.Sources[0].Kind != KindSource || ctx.Tokens != 0 || len(ctx.Omitted) != 0 {
+		t.Fatalf("expected sources to bypass the reference budget, got %+v", ctx)
+	}
+}
+
+func TestBuildRejectsPathsOutsideRoot(t *testing.T) {
+	root := writeTree(t, map[string]string{"chapters/01.md": "x"})
+
+	_, err := NewBuilder(0).Build(root, Request{ChangedFiles: []string{"../secrets.txt"}})
+	if !errors.Is(err, ErrPathOutsideRoot) {
+		t.Fatalf("expected ErrPathOutsideRoot, got %v", err)
+	}
+}
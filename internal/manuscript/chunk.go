@@ -0,0 +1,191 @@
+package manuscript
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	DefaultChunkTokens   = 6000
+	DefaultOverlapTokens = 200
+)
+
+// ChunkOptions controls how documents are split. MaxTokens bounds the new text in a
+// chunk; up to OverlapTokens of trailing text from the previous chunk is prepended on
+// top so findings that straddle a boundary are still visible in one piece.
+type ChunkOptions struct {
+	MaxTokens     int
+	OverlapTokens int
+}
+
+// Chunk is a contiguous range of lines from a single document.
+type Chunk struct {
+	Index     int    `json:"index"`
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"` // 1-based, includes overlap
+	EndLine   int    `json:"end_line"`   // inclusive
+	// OverlapLines is how many leading lines repeat the end of the previous chunk.
+	OverlapLines int    `json:"overlap_lines,omitempty"`
+	Content      string `json:"-"`
+	Tokens       int    `json:"tokens"`
+}
+
+// Label identifies the chunk for logs and summaries, e.g. "chapters/01.md:1-120".
+func (c Chunk) Label() string {
+	return fmt.Sprintf("%s:%d-%d", c.Path, c.StartLine, c.EndLine)
+}
+
+// NumberedContent prefixes each line with its line number in the source file so
+// models can anchor findings to exact lines.
+func (c Chunk) NumberedContent() string {
+	var b strings.Builder
+	for i, line := range strings.Split(c.Content, "\n") {
+		fmt.Fprintf(&b, "%d | %s\n", c.StartLine+i, line)
+	}
+	return b.String()
+}
+
+// sceneBreak matches Markdown headings and the usual scene separators (***, * * *, ---, ⁂).
+var sceneBreak = regexp.MustCompile(`^\s*(#{1,6}\s|(\*\s*){3,}$|(-\s*){3,}$|⁂)`)
+
+type line struct {
+	number int
+	text   string
+}
+
+// ChunkDocuments splits documents into chunks that never span files. Scenes are kept
+// whole when they fit; oversized scenes fall back to paragraph and then line splits.
+func ChunkDocuments(docs []Document, opts ChunkOptions) []Chunk {
+	if opts.MaxTokens <= 0 {
+		opts.MaxTokens = DefaultChunkTokens
+	}
+	if opts.OverlapTokens < 0 {
+		opts.OverlapTokens = 0
+	}
+
+	var chunks []Chunk
+	for _, doc := range docs {
+		lines := splitLines(doc.Content)
+		if len(lines) == 0 {
+			continue
+		}
+
+		var previous []line
+		for _, group := range pack(pieces(lines, opts.MaxTokens), opts.MaxTokens) {
+			overlap := tail(previous, opts.OverlapTokens)
+			all := append(append([]line{}, overlap...), group...)
+			content := joinLines(all)
+			chunks = append(chunks, Chunk{
+				Index:        len(chunks),
+				Path:         doc.Path,
+				StartLine:    all[0].number,
+				EndLine:      all[len(all)-1].number,
+				OverlapLines: len(overlap),
+				Content:      content,
+				Tokens:       EstimateTokens(content),
+			})
+			previous = group
+		}
+	}
+	return chunks
+}
+
+func splitLines(content string) []line {
+	raw := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if len(raw) == 1 && strings.TrimSpace(raw[0]) == "" {
+		return nil
+	}
+	lines := make([]line, len(raw))
+	for i, text := range raw {
+		lines[i] = line{number: i + 1, text: text}
+	}
+	return lines
+}
+
+// pieces breaks lines into the largest units (scene, paragraph, line) that fit maxTokens.
+func pieces(lines []line, maxTokens int) [][]line {
+	var out [][]line
+	for _, scene := range splitWhere(lines, isSceneStart) {
+		if tokens(scene) <= maxTokens {
+			out = append(out, scene)
+			continue
+		}
+		for _, para := range splitWhere(scene, isParagraphStart) {
+			if tokens(para) <= maxTokens {
+				out = append(out, para)
+				continue
+			}
+			for _, l := range para {
+				out = append(out, []line{l})
+			}
+		}
+	}
+	return out
+}
+
+// pack greedily groups consecutive pieces into chunks of at most maxTokens. A single
+// piece larger than maxTokens (one very long line) becomes its own chunk.
+func pack(pieces [][]line, maxTokens int) [][]line {
+	var groups [][]line
+	var current []line
+	currentTokens := 0
+	for _, p := range pieces {
+		pTokens := tokens(p)
+		if len(current) > 0 && currentTokens+pTokens > maxTokens {
+			groups = append(groups, current)
+			current, currentTokens = nil, 0
+		}
+		current = append(current, p...)
+		currentTokens += pTokens
+	}
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+	return groups
+}
+
+// splitWhere cuts lines before every line (after the first) for which start reports true.
+func splitWhere(lines []line, start func(prev, cur line) bool) [][]line {
+	var out [][]line
+	begin := 0
+	for i := 1; i < len(lines); i++ {
+		if start(lines[i-1], lines[i]) {
+			out = append(out, lines[begin:i])
+			begin = i
+		}
+	}
+	return append(out, lines[begin:])
+}
+
+func isSceneStart(_, cur line) bool {
+	return sceneBreak.MatchString(cur.text)
+}
+
+func isParagraphStart(prev, cur line) bool {
+	return strings.TrimSpace(prev.text) == "" && strings.TrimSpace(cur.text) != ""
+}
+
+// tail returns the trailing lines of previous that fit within maxTokens.
+func tail(previous []line, maxTokens int) []line {
+	if maxTokens == 0 {
+		return nil
+	}
+	start := len(previous)
+	for start > 0 && tokens(previous[start-1:]) <= maxTokens {
+		start--
+	}
+	return previous[start:]
+}
+
+func tokens(lines []line) int {
+	return EstimateTokens(joinLines(lines))
+}
+
+func joinLines(lines []line) string {
+	texts := make([]string, len(lines))
+	for i, l := range lines {
+		texts[i] = l.text
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,67 @@
+package manuscript
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChunkDocumentsKeepsScenesWhole(t *testing.T) {
+	scene := strings.Repeat("She walked along the quay.\n", 10) // ~68 tokens
+	content := "# Chapter One\n" + scene + "\n***\n" + scene + "\n***\n" + scene
+	doc := newDocument("chapters/01.md", KindChapter, content)
+
+	chunks := ChunkDocuments([]Document{doc}, ChunkOptions{MaxTokens: 100})
+
+	if len(chunks) != 3 {
+		t.Fatalf("expected one chunk per scene, got %d", len(chunks))
+	}
+	if chunks[0].StartLine != 1 || !strings.HasPrefix(chunks[1].Content, "***") {
+		t.Fatalf("expected chunks to break at scene separators, got %q", chunks[1].Content[:10])
+	}
+	for i, c := range chunks {
+		if c.Index != i || c.Path != "chapters/01.md" {
+			t.Fatalf("unexpected chunk metadata %+v", c)
+		}
+		if i > 0 && c.StartLine != chunks[i-1].EndLine+1 {
+			t.Fatalf("expected contiguous chunks without overlap, got %s after %s", c.Label(), chunks[i-1].Label())
+		}
+	}
+	if last := chunks[len(chunks)-1]; last.EndLine != len(strings.Split(content, "\n"))-1 {
+		t.Fatalf("expected final chunk to end at the last line, got %d", last.EndLine)
+	}
+}
+
+func TestChunkDocumentsSplitsLongScenesWithOverlap(t *testing.T) {
+	paragraph := strings.Repeat("The storm rolled in over the hills. ", 6) + "\n\n" // ~54 tokens
+	doc := newDocument("chapters/02.md", KindChapter, strings.Repeat(paragraph, 6))
+
+	chunks := ChunkDocuments([]Document{doc}, ChunkOptions{MaxTokens: 120, OverlapTokens: 60})
+
+	if len(chunks) < 3 {
+		t.Fatalf("expected the scene to be split at paragraphs, got %d chunks", len(chunks))
+	}
+	for _, c := range chunks[1:] {
+		if c.OverlapLines == 0 {
+			t.Fatalf("expected overlap on %s", c.Label())
+		}
+	}
+	if chunks[1].StartLine > chunks[0].EndLine {
+		t.Fatalf("expected chunk 1 to repeat the end of chunk 0, got %s after %s", chunks[1].Label(), chunks[0].Label())
+	}
+}
+
+func TestChunkDocumentsNeverSpansFiles(t *testing.T) {
+	docs := []Document{
+		newDocument("chapters/01.md", KindChapter, "One.\n"),
+		newDocument("chapters/02.md", KindChapter, "Two.\n"),
+		newDocument("chapters/03.md", KindChapter, ""),
+	}
+
+	chunks := ChunkDocuments(docs, ChunkOptions{})
+	if len(chunks) != 2 || chunks[1].Path != "chapters/02.md" || chunks[1].Index != 1 {
+		t.Fatalf("unexpected chunks %+v", chunks)
+	}
+	if got := chunks[1].NumberedContent(); got != "1 | Two.\n" {
+		t.Fatalf("unexpected numbered content %q", got)
+	}
+}
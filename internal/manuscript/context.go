@@ -0,0 +1,76 @@
+package manuscript
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"unicode/utf8"
+)
+
+// Kind classifies a document by the role it plays in an agent's context.
+type Kind string
+
+const (
+	KindChapter   Kind = "chapter"
+	KindEditorial Kind = "editorial"
+	KindCreative  Kind = "creative"
+	KindCharacter Kind = "character"
+	KindWorld     Kind = "world"
+	KindSource    Kind = "source"
+)
+
+// Document is a single file loaded from a project's working tree.
+type Document struct {
+	Path    string `json:"path"` // repo-relative, slash separated
+	Kind    Kind   `json:"kind"`
+	Content string `json:"-"`
+	Tokens  int    `json:"tokens"`
+}
+
+// Hash returns the hex SHA-256 of the document content, used to detect unchanged files
+// between runs.
+func (d Document) Hash() string {
+	sum := sha256.Sum256([]byte(d.Content))
+	return hex.EncodeToString(sum[:])
+}
+
+// Context is the material handed to an agent. Changed files are loaded whole so they
+// can be chunked; Documents holds the reference material (story bible, editorial notes)
+// in priority order, limited to the token budget.
+type Context struct {
+	Changed   []Document `json:"changed"`
+	Documents []Document `json:"documents"`
+	// Omitted lists reference files that were wanted but did not fit in the budget.
+	Omitted []string `json:"omitted,omitempty"`
+	// Sources holds the bibliography files from docs/sources. Agents parse them locally,
+	// so they are loaded whole and do not count against the budget.
+	Sources []Document `json:"sources,omitempty"`
+	// Missing lists changed files that do not exist in the working tree (e.g. deleted in the PR).
+	Missing []string `json:"missing,omitempty"`
+	// Tokens counts the reference documents only; Budget applies to them alone.
+	Tokens int `json:"tokens"`
+	Budget int `json:"budget"`
+}
+
+// ByKind returns the reference documents of the given kinds, preserving priority order.
+func (c Context) ByKind(kinds ...Kind) []Document {
+	var out []Document
+	for _, doc := range c.Documents {
+		for _, k := range kinds {
+			if doc.Kind == k {
+				out = append(out, doc)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// EstimateTokens approximates a token count using the common ~4 characters per token
+// heuristic. It is deliberately conservative (rounds up) since it drives budgets.
+func EstimateTokens(text string) int {
+	n := utf8.RuneCountInString(text)
+	if n == 0 {
+		return 0
+	}
+	return (n + 3) / 4
+}
@@ -0,0 +1,145 @@
+package manuscript
+
+import (
+	"strings"
+	"unicode"
+)
+
+// EditorialPath is the brief for the current editing round, at the repository root.
+// Closed rounds are archived under editorial/ as YYYY-MM-DD-<round>.md.
+const EditorialPath = "EDITORIAL.md"
+
+// Editorial is the parsed focus of the current editing round.
+type Editorial struct {
+	// Round identifies the round the way its archive file will be named, e.g.
+	// "2024-03-01-pacing-pass". It is empty when EDITORIAL.md names no round, status
+	// or start date.
+	Round     string
+	Name      string
+	StartDate string
+	Goals     []string
+	AntiGoals []string
+}
+
+// ParseEditorial reads the round fields ("**Round**:", "**Status**:", "**Start Date**:")
+// and the bullets under the "Goals" and "Anti-Goals" headings of an EDITORIAL.md.
+// Bracketed template placeholders such as "[YYYY-MM-DD]" are treated as unset, and
+// checkbox markers are stripped from bullets.
+func ParseEditorial(content string) Editorial {
+	var e Editorial
+	var status string
+	var section *[]string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if heading, ok := strings.CutPrefix(line, "#"); ok {
+			switch normaliseHeading(heading) {
+			case "goals":
+				section = &e.Goals
+			case "antigoals":
+				section = &e.AntiGoals
+			default:
+				section = nil
+			}
+			continue
+		}
+
+		if key, value, ok := editorialField(line); ok {
+			switch key {
+			case "round", "current round":
+				e.Name = value
+			case "status":
+				status = value
+			case "start date":
+				e.StartDate = value
+			}
+			continue
+		}
+
+		if section == nil {
+			continue
+		}
+		item, ok := bullet(line)
+		if ok && item != "" {
+			*section = append(*section, item)
+		}
+	}
+
+	if e.Name == "" {
+		e.Name = status
+	}
+	e.Round = slugify(strings.TrimSpace(e.StartDate + " " + e.Name))
+	return e
+}
+
+// editorialField parses "**Key**: value", "Key: value" or "- Key: value" lines.
+func editorialField(line string) (string, string, bool) {
+	line = strings.TrimSpace(strings.TrimPrefix(line, "- "))
+	key, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", "", false
+	}
+	key = strings.ToLower(strings.TrimSpace(strings.Trim(key, "*_ ")))
+	switch key {
+	case "round", "current round", "status", "start date":
+	default:
+		return "", "", false
+	}
+	value = strings.TrimSpace(strings.Trim(strings.TrimSpace(value), "*_"))
+	if isPlaceholder(value) {
+		value = ""
+	}
+	return key, value, true
+}
+
+// bullet returns the text of a Markdown list item without its checkbox.
+func bullet(line string) (string, bool) {
+	var item string
+	switch {
+	case strings.HasPrefix(line, "- "), strings.HasPrefix(line, "* "), strings.HasPrefix(line, "+ "):
+		item = strings.TrimSpace(line[2:])
+	default:
+		return "", false
+	}
+	for _, box := range []string{"[ ]", "[x]", "[X]"} {
+		if rest, ok := strings.CutPrefix(item, box); ok {
+			item = strings.TrimSpace(rest)
+			break
+		}
+	}
+	if isPlaceholder(item) {
+		return "", true
+	}
+	return item, true
+}
+
+func isPlaceholder(value string) bool {
+	return strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]")
+}
+
+func normaliseHeading(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		if unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// slugify lowercases s and joins its letters and digits with single hyphens.
+func slugify(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		hyphen = true
+	}
+	return b.String()
+}
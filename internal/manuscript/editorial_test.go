@@ -0,0 +1,52 @@
+package manuscript
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEditorial(t *testing.T) {
+	e := ParseEditorial(`# Current Editorial Focus
+
+**Round**: Pacing Pass
+**Status**: Editing
+**Start Date**: 2024-03-01
+
+## Goals
+- [ ] Tighten pacing in chapters 1-5
+- [x] Fix tense slips
+
+## Anti-Goals
+- [ ] Don't worry about adverbs or sentence-length yet
+
+## Notes
+- Status of chapter 4 is unclear
+- Keep the ferry scene
+`)
+
+	if e.Round != "2024-03-01-pacing-pass" || e.Name != "Pacing Pass" || e.StartDate != "2024-03-01" {
+		t.Fatalf("unexpected round fields %+v", e)
+	}
+	if want := []string{"Tighten pacing in chapters 1-5", "Fix tense slips"}; !reflect.DeepEqual(e.Goals, want) {
+		t.Fatalf("expected goals %v, got %v", want, e.Goals)
+	}
+	if want := []string{"Don't worry about adverbs or sentence-length yet"}; !reflect.DeepEqual(e.AntiGoals, want) {
+		t.Fatalf("expected anti-goals %v, got %v", want, e.AntiGoals)
+	}
+}
+
+func TestParseEditorialIgnoresTemplatePlaceholders(t *testing.T) {
+	e := ParseEditorial(`**Status**: [Drafting / Editing / Polishing]
+**Start Date**: [YYYY-MM-DD]
+
+## Anti-Goals
+- [ ] [Thing to ignore]
+`)
+	if e.Round != "" || len(e.AntiGoals) != 0 {
+		t.Fatalf("expected an unset round, got %+v", e)
+	}
+
+	if e := ParseEditorial("**Status**: First Draft\n"); e.Round != "first-draft" {
+		t.Fatalf("expected the status to name the round, got %q", e.Round)
+	}
+}
@@ -0,0 +1,29 @@
+package manuscript
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseFrontmatter extracts a leading YAML frontmatter block delimited by "---" lines.
+// It returns the decoded fields and the line number of the closing delimiter, or a nil
+// map and 0 when the content has no frontmatter.
+func ParseFrontmatter(content string) (map[string]any, int, error) {
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return nil, 0, nil
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != "---" {
+			continue
+		}
+		fields := map[string]any{}
+		if err := yaml.Unmarshal([]byte(strings.Join(lines[1:i], "\n")), &fields); err != nil {
+			return nil, 0, fmt.Errorf("parse frontmatter: %w", err)
+		}
+		return fields, i + 1, nil
+	}
+	return nil, 0, nil
+}
@@ -0,0 +1,22 @@
+package manuscript
+
+import "testing"
+
+func TestParseFrontmatter(t *testing.T) {
+	fields, end, err := ParseFrontmatter("---\ntitle: Arrival\ndate: 2024-03-05\n---\nText.\n")
+	if err != nil {
+		t.Fatalf("ParseFrontmatter returned error: %v", err)
+	}
+	if end != 4 || fields["title"] != "Arrival" {
+		t.Fatalf("unexpected frontmatter %v (end %d)", fields, end)
+	}
+
+	fields, end, err = ParseFrontmatter("No frontmatter here.\n---\n")
+	if err != nil || fields != nil || end != 0 {
+		t.Fatalf("expected no frontmatter, got %v %d %v", fields, end, err)
+	}
+
+	if _, _, err := ParseFrontmatter("---\ntitle: [unclosed\n---\n"); err == nil {
+		t.Fatal("expected invalid YAML to return an error")
+	}
+}
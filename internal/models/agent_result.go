@@ -0,0 +1,124 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RunResultSchemaVersion is bumped whenever RunResult changes in a way consumers must handle.
+const RunResultSchemaVersion = 1
+
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+func (s Severity) Valid() bool {
+	switch s {
+	case SeverityInfo, SeverityWarning, SeverityError:
+		return true
+	}
+	return false
+}
+
+// RunResult is the persisted, versioned output of an agent run.
+type RunResult struct {
+	SchemaVersion int     `json:"schema_version"`
+	Summary       string  `json:"summary"`
+	Issues        []Issue `json:"issues"`
+	// Metrics holds agent-specific measurements (e.g. StyleBot readability scores).
+	Metrics map[string]any `json:"metrics,omitempty"`
+	Stats   RunStats       `json:"stats"`
+	// Artifacts lists extra files written for the run, relative to the artifact
+	// directory (e.g. "run-12/timeline.json").
+	Artifacts []string `json:"artifacts,omitempty"`
+}
+
+// RunStats describes how much work a run did. CacheHits counts chunks whose results
+// were reused from an earlier run instead of being analysed again.
+type RunStats struct {
+	FilesAnalyzed  int `json:"files_analyzed"`
+	ChunksAnalyzed int `json:"chunks_analyzed"`
+	CacheHits      int `json:"cache_hits"`
+	TokensUsed     int `json:"tokens_used"`
+}
+
+// Issue is a single finding. When StartLine is set the issue is anchored to File
+// (a repo-relative path) so the frontend and PR bots can point at exact lines.
+// Lines and columns are 1-based; EndLine/EndColumn are inclusive. Edit, when set,
+// rewrites the anchored lines and requires an anchor.
+type Issue struct {
+	ID          string    `json:"id"`
+	Severity    Severity  `json:"severity"`
+	Category    string    `json:"category"`
+	Message     string    `json:"message"`
+	File        string    `json:"file,omitempty"`
+	StartLine   int       `json:"start_line,omitempty"`
+	EndLine     int       `json:"end_line,omitempty"`
+	StartColumn int       `json:"start_column,omitempty"`
+	EndColumn   int       `json:"end_column,omitempty"`
+	Excerpt     string    `json:"excerpt,omitempty"`
+	Suggestion  string    `json:"suggestion,omitempty"`
+	Edit        *TextEdit `json:"edit,omitempty"`
+	Confidence  float64   `json:"confidence"`
+}
+
+// Validate checks that the result matches the current schema. It returns an error
+// wrapping ErrInvalidRunResult describing the first problem found.
+func (r RunResult) Validate() error {
+	if r.SchemaVersion != RunResultSchemaVersion {
+		return fmt.Errorf("%w: unsupported schema version %d", ErrInvalidRunResult, r.SchemaVersion)
+	}
+
+	seen := make(map[string]bool, len(r.Issues))
+	for i, issue := range r.Issues {
+		if err := issue.validate(); err != nil {
+			return fmt.Errorf("%w: issue %d: %s", ErrInvalidRunResult, i, err)
+		}
+		if seen[issue.ID] {
+			return fmt.Errorf("%w: issue %d: duplicate id %q", ErrInvalidRunResult, i, issue.ID)
+		}
+		seen[issue.ID] = true
+	}
+	return nil
+}
+
+func (i Issue) validate() error {
+	switch {
+	case strings.TrimSpace(i.ID) == "":
+		return fmt.Errorf("id is required")
+	case !i.Severity.Valid():
+		return fmt.Errorf("invalid severity %q", i.Severity)
+	case strings.TrimSpace(i.Category) == "":
+		return fmt.Errorf("category is required")
+	case strings.TrimSpace(i.Message) == "":
+		return fmt.Errorf("message is required")
+	case i.Confidence < 0 || i.Confidence > 1:
+		return fmt.Errorf("confidence %v outside [0, 1]", i.Confidence)
+	case i.StartLine < 0 || i.EndLine < 0 || i.StartColumn < 0 || i.EndColumn < 0:
+		return fmt.Errorf("line and column numbers must be positive")
+	}
+
+	if i.StartLine == 0 {
+		if i.EndLine != 0 || i.StartColumn != 0 || i.EndColumn != 0 {
+			return fmt.Errorf("range set without start_line")
+		}
+		if i.Edit != nil {
+			return fmt.Errorf("edit set without start_line")
+		}
+		return nil
+	}
+	if i.File == "" {
+		return fmt.Errorf("file is required when start_line is set")
+	}
+	if i.EndLine < i.StartLine {
+		return fmt.Errorf("end_line %d before start_line %d", i.EndLine, i.StartLine)
+	}
+	if i.EndLine == i.StartLine && i.EndColumn != 0 && i.EndColumn < i.StartColumn {
+		return fmt.Errorf("end_column %d before start_column %d", i.EndColumn, i.StartColumn)
+	}
+	return nil
+}
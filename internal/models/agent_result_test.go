@@ -0,0 +1,65 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRunResultValidate(t *testing.T) {
+	valid := Issue{
+		ID:         "continuity-1",
+		Severity:   SeverityError,
+		Category:   "character",
+		Message:    "Sarah's eyes change from blue to brown.",
+		File:       "chapters/05.md",
+		StartLine:  45,
+		EndLine:    45,
+		Excerpt:    "her brown eyes",
+		Confidence: 0.9,
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(r *RunResult)
+		wantErr bool
+	}{
+		{name: "valid", mutate: func(r *RunResult) {}},
+		{name: "unanchored issue", mutate: func(r *RunResult) {
+			r.Issues[0].File, r.Issues[0].StartLine, r.Issues[0].EndLine = "", 0, 0
+		}},
+		{name: "anchored edit", mutate: func(r *RunResult) { r.Issues[0].Edit = &TextEdit{Replacement: "her blue eyes"} }},
+		{name: "edit without anchor", mutate: func(r *RunResult) {
+			r.Issues[0].File, r.Issues[0].StartLine, r.Issues[0].EndLine = "", 0, 0
+			r.Issues[0].Edit = &TextEdit{Replacement: "her blue eyes"}
+		}, wantErr: true},
+		{name: "wrong schema version", mutate: func(r *RunResult) { r.SchemaVersion = 0 }, wantErr: true},
+		{name: "missing id", mutate: func(r *RunResult) { r.Issues[0].ID = "" }, wantErr: true},
+		{name: "bad severity", mutate: func(r *RunResult) { r.Issues[0].Severity = "fatal" }, wantErr: true},
+		{name: "line without file", mutate: func(r *RunResult) { r.Issues[0].File = "" }, wantErr: true},
+		{name: "inverted range", mutate: func(r *RunResult) { r.Issues[0].EndLine = 40 }, wantErr: true},
+		{name: "inverted columns", mutate: func(r *RunResult) {
+			r.Issues[0].StartColumn, r.Issues[0].EndColumn = 10, 4
+		}, wantErr: true},
+		{name: "confidence out of range", mutate: func(r *RunResult) { r.Issues[0].Confidence = 1.5 }, wantErr: true},
+		{name: "duplicate ids", mutate: func(r *RunResult) { r.Issues = append(r.Issues, r.Issues[0]) }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RunResult{
+				SchemaVersion: RunResultSchemaVersion,
+				Summary:       "1 issue",
+				Issues:        []Issue{valid},
+			}
+			tt.mutate(&result)
+
+			err := result.Validate()
+			if tt.wantErr && !errors.Is(err, ErrInvalidRunResult) {
+				t.Fatalf("expected ErrInvalidRunResult, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
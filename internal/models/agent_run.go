@@ -1,19 +1,37 @@
 package models
 
-import (
-	"encoding/json"
-	"time"
-)
+import "time"
 
 type AgentRun struct {
-	ID          int64           `json:"id"`
-	ProjectID   int64           `json:"project_id"`
-	AgentType   string          `json:"agent_type"`
-	Trigger     string          `json:"trigger"`
-	Status      string          `json:"status"`
-	Results     json.RawMessage `json:"results,omitempty"`
-	Error       string          `json:"error_message,omitempty"`
-	StartedAt   *time.Time      `json:"started_at,omitempty"`
-	CompletedAt *time.Time      `json:"completed_at,omitempty"`
-	CreatedAt   time.Time       `json:"created_at"`
+	ID              int64        `json:"id"`
+	ProjectID       int64        `json:"project_id"`
+	AgentType       string       `json:"agent_type"`
+	Trigger         string       `json:"trigger"`
+	Status          string       `json:"status"`
+	FilesChanged    []string     `json:"files_changed,omitempty"`
+	Progress        *RunProgress `json:"progress,omitempty"`
+	Results         *RunResult   `json:"results,omitempty"`
+	Error           string       `json:"error_message,omitempty"`
+	Attempts        int          `json:"attempts"`
+	NextAttemptAt   *time.Time   `json:"next_attempt_at,omitempty"`
+	Usage           *RunUsage    `json:"usage,omitempty"`
+	CreditsReserved int          `json:"credits_reserved,omitempty"`
+	CreditsCharged  int          `json:"credits_charged,omitempty"`
+	PipelineID      string       `json:"pipeline_id,omitempty"`
+	DependsOn       []int64      `json:"depends_on,omitempty"`
+	// PromptHash is the SHA-256 of the prompt template the run was given.
+	PromptHash string `json:"prompt_hash,omitempty"`
+	// EditorialRound names the EDITORIAL.md round the run was made in, e.g.
+	// "2024-03-01-pacing-pass", so results can be grouped per round.
+	EditorialRound string     `json:"editorial_round,omitempty"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// RunProgress tracks chunked processing of a run.
+type RunProgress struct {
+	ChunksTotal     int    `json:"chunks_total"`
+	ChunksCompleted int    `json:"chunks_completed"`
+	CurrentChunk    string `json:"current_chunk,omitempty"`
 }
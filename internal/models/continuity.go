@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Continuity fact kinds.
+const (
+	FactAttribute    = "attribute"
+	FactRelationship = "relationship"
+	FactLocation     = "location"
+	FactObject       = "object"
+	FactStatus       = "status"
+)
+
+// ContinuitySource is one ledger source (a chapter or story-bible document) with the
+// content hash it was read at and the facts it establishes.
+type ContinuitySource struct {
+	Path  string
+	Hash  string
+	Facts []ContinuityFact
+}
+
+// ContinuityFact is one entry in a project's continuity ledger: something the story
+// bible or a chapter establishes about a character, place or object.
+type ContinuityFact struct {
+	ID         int64     `json:"id"`
+	ProjectID  int64     `json:"project_id"`
+	SourcePath string    `json:"source_path"`
+	Kind       string    `json:"kind"`
+	Subject    string    `json:"subject"`
+	Attribute  string    `json:"attribute"`
+	Value      string    `json:"value"`
+	Line       int       `json:"line,omitempty"`
+	Excerpt    string    `json:"excerpt,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// CreditBalance is a user's AI credit account. One credit buys one US cent of model usage.
+// Queueing a run holds its estimated cost (AgentRun.CreditsReserved) until the run ends
+// and the actual cost is charged (AgentRun.CreditsCharged).
+type CreditBalance struct {
+	CreditsRemaining      int        `json:"credits_remaining"`
+	CreditsTotal          int        `json:"credits_total"`
+	SubscriptionTier      string     `json:"subscription_tier"`
+	SubscriptionExpiresAt *time.Time `json:"subscription_expires_at,omitempty"`
+}
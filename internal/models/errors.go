@@ -2,4 +2,7 @@ package models
 
 import "errors"
 
-var ErrNotFound = errors.New("not found")
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrInvalidRunResult = errors.New("invalid run result")
+)
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Pipeline is a group of agent runs queued by one request. Runs wait for the runs they
+// depend on (AgentRun.DependsOn) to complete and receive their results as context.
+type Pipeline struct {
+	ID        string     `json:"id"`
+	ProjectID int64      `json:"project_id"`
+	Status    string     `json:"status"`
+	Runs      []AgentRun `json:"runs"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// PipelineStatus aggregates run statuses. A pipeline is queued until a run starts and
+// running while any run is queued or running. Once every run has ended it is completed
+// if they all completed, failed if any failed, timed out or was dead-lettered, and
+// cancelled otherwise.
+func PipelineStatus(runs []AgentRun) string {
+	var active, started, failed bool
+	completed := 0
+	for _, run := range runs {
+		switch run.Status {
+		case "queued":
+			active = true
+			if run.Attempts > 0 {
+				started = true
+			}
+		case "running":
+			active, started = true, true
+		case "completed":
+			completed++
+			started = true
+		case "failed", "dead_letter", "timed_out":
+			failed, started = true, true
+		default:
+			started = true
+		}
+	}
+	switch {
+	case active && started:
+		return "running"
+	case active:
+		return "queued"
+	case completed == len(runs):
+		return "completed"
+	case failed:
+		return "failed"
+	default:
+		return "cancelled"
+	}
+}
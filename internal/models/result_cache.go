@@ -0,0 +1,11 @@
+package models
+
+// ResultCacheKey identifies a cached per-chunk agent result. Entries are scoped to a
+// project so one author's results are never served to another.
+type ResultCacheKey struct {
+	ProjectID     int64
+	AgentType     string
+	Model         string
+	PromptVersion string
+	ContentHash   string
+}
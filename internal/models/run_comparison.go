@@ -0,0 +1,27 @@
+package models
+
+import "strings"
+
+// RunComparison diffs the issues of two runs of the same agent: Resolved issues were
+// reported by the base run only, New issues by the head run only, and Unchanged issues
+// (as reported by the head run) by both.
+type RunComparison struct {
+	BaseRunID int64   `json:"base_run_id"`
+	HeadRunID int64   `json:"head_run_id"`
+	AgentType string  `json:"agent_type"`
+	Resolved  []Issue `json:"resolved"`
+	New       []Issue `json:"new"`
+	Unchanged []Issue `json:"unchanged"`
+}
+
+// Fingerprint identifies an issue across runs by its file, category and quoted text
+// (the message when nothing is quoted), ignoring case and whitespace. Line numbers are
+// left out so revisions elsewhere in a chapter do not make a persisting issue look new.
+func (i Issue) Fingerprint() string {
+	text := i.Excerpt
+	if strings.TrimSpace(text) == "" {
+		text = i.Message
+	}
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	return i.File + "\x00" + strings.ToLower(i.Category) + "\x00" + text
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Run lifecycle event types.
+const (
+	RunEventQueued     = "queued"
+	RunEventRunning    = "running"
+	RunEventProgress   = "progress"
+	RunEventCompleted  = "completed"
+	RunEventFailed     = "failed"
+	RunEventRetrying   = "retrying"
+	RunEventDeadLetter = "dead_letter"
+	RunEventCancelled  = "cancelled"
+	RunEventTimedOut   = "timed_out"
+)
+
+// RunEvent is a change in an agent run's lifecycle, streamed to clients as it happens.
+type RunEvent struct {
+	Type      string       `json:"type"`
+	RunID     int64        `json:"run_id"`
+	ProjectID int64        `json:"project_id"`
+	AgentType string       `json:"agent_type"`
+	Status    string       `json:"status"`
+	Progress  *RunProgress `json:"progress,omitempty"`
+	// Issues holds the findings of the chunk that just finished, for progress events.
+	Issues        []Issue    `json:"issues,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	Timestamp     time.Time  `json:"timestamp"`
+}
+
+// Terminal reports whether no further events follow for the run.
+func (e RunEvent) Terminal() bool {
+	switch e.Type {
+	case RunEventCompleted, RunEventFailed, RunEventDeadLetter, RunEventCancelled, RunEventTimedOut:
+		return true
+	}
+	return false
+}
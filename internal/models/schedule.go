@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// AgentSchedule fires an agent run on a cron schedule. Schedules are stored in the
+// project's settings under "schedules"; times are UTC.
+type AgentSchedule struct {
+	ID        int64  `json:"id"`
+	ProjectID int64  `json:"project_id"`
+	AgentType string `json:"agent_type"`
+	Cron      string `json:"cron"`
+	// Files limits the run to these repo-relative paths. Empty means every chapter.
+	Files     []string   `json:"files,omitempty"`
+	Enabled   bool       `json:"enabled"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	// NextRunAt is computed when schedules are listed; it is not stored.
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+}
+
+// ProjectSchedules is a project's stored schedule list. Configured is false when the
+// project has never saved schedules, in which case the defaults apply.
+type ProjectSchedules struct {
+	ProjectID  int64
+	Schedules  []AgentSchedule
+	Configured bool
+}
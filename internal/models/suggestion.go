@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Suggestion states. Open suggestions await review; stale ones can no longer be applied
+// because the text they target has changed since the run.
+const (
+	SuggestionOpen     = "open"
+	SuggestionAccepted = "accepted"
+	SuggestionRejected = "rejected"
+	SuggestionStale    = "stale"
+)
+
+// TextEdit is a concrete rewrite proposed with an issue: lines StartLine..EndLine of the
+// issue's File replaced by Replacement. Revision (the SHA-256 of the file the edit was
+// made against) and Diff (a unified-diff hunk) are filled in when the run is recorded.
+type TextEdit struct {
+	Replacement string `json:"replacement"`
+	Revision    string `json:"revision,omitempty"`
+	Diff        string `json:"diff,omitempty"`
+}
+
+// Suggestion is a reviewable edit taken from an agent run. Agents never apply edits
+// themselves; an author accepts a suggestion (producing a commit on its own branch, or
+// a patch file when the workspace is not a git checkout) or rejects it with a reason.
+type Suggestion struct {
+	ID          int64      `json:"id"`
+	ProjectID   int64      `json:"project_id"`
+	RunID       int64      `json:"run_id"`
+	IssueID     string     `json:"issue_id"`
+	AgentType   string     `json:"agent_type"`
+	File        string     `json:"file"`
+	Revision    string     `json:"revision"`
+	StartLine   int        `json:"start_line"`
+	EndLine     int        `json:"end_line"`
+	Original    string     `json:"original"`
+	Replacement string     `json:"replacement"`
+	Diff        string     `json:"diff"`
+	Message     string     `json:"message"`
+	State       string     `json:"state"`
+	Reason      string     `json:"reason,omitempty"`
+	Branch      string     `json:"branch,omitempty"`
+	Commit      string     `json:"commit,omitempty"`
+	PatchPath   string     `json:"patch_path,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Usage entry statuses.
+const (
+	UsageSuccess = "success"
+	UsageFailed  = "failed"
+)
+
+// UsageEntry is one model call recorded in ai_usage_log.
+type UsageEntry struct {
+	ID               int64     `json:"id"`
+	RunID            int64     `json:"run_id,omitempty"`
+	ProjectID        int64     `json:"project_id"`
+	AgentType        string    `json:"agent_type"`
+	Model            string    `json:"model"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	CostCents        float64   `json:"cost_cents"`
+	Status           string    `json:"status"`
+	Error            string    `json:"error_message,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// RunUsage totals the model calls made on behalf of a run.
+type RunUsage struct {
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	CostCents        float64 `json:"cost_cents"`
+}
+
+// UsageFilter narrows a usage report to one user's calls, optionally for a single
+// project and a time window.
+type UsageFilter struct {
+	UserID    int64
+	ProjectID int64
+	Since     *time.Time
+	Until     *time.Time
+}
+
+// UsageTotals aggregates a set of model calls.
+type UsageTotals struct {
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	CostCents        float64 `json:"cost_cents"`
+}
+
+// UsageGroup is the usage for one project or one agent type.
+type UsageGroup struct {
+	ProjectID int64  `json:"project_id,omitempty"`
+	AgentType string `json:"agent_type,omitempty"`
+	UsageTotals
+}
+
+// UsageReport breaks model usage down per project and per agent.
+type UsageReport struct {
+	Totals    UsageTotals  `json:"totals"`
+	ByProject []UsageGroup `json:"by_project"`
+	ByAgent   []UsageGroup `json:"by_agent"`
+}
@@ -0,0 +1,166 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCron = errors.New("invalid cron expression")
+
+// Spec is a parsed five-field cron expression: minute, hour, day of month, month and
+// day of week. Fields accept "*", numbers, ranges ("1-5"), steps ("*/15", "0-30/10"),
+// lists ("1,15") and three-letter month and weekday names. The descriptors @hourly,
+// @daily, @midnight, @weekly, @monthly, @yearly and @annually are also accepted.
+// Times are evaluated in UTC.
+type Spec struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny record an unrestricted ("*" or "*/n") day field. When both
+	// day fields are restricted a day matches if either does, as in standard cron.
+	domAny, dowAny bool
+}
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// Parse parses a cron expression.
+func Parse(expr string) (Spec, error) {
+	expr = strings.TrimSpace(expr)
+	if d, ok := descriptors[strings.ToLower(expr)]; ok {
+		expr = d
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return Spec{}, fmt.Errorf("%w: %q needs 5 fields", ErrInvalidCron, expr)
+	}
+
+	var spec Spec
+	var err error
+	if spec.minute, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return Spec{}, err
+	}
+	if spec.hour, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return Spec{}, err
+	}
+	if spec.dom, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return Spec{}, err
+	}
+	if spec.month, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return Spec{}, err
+	}
+	// Day of week allows 7 for Sunday.
+	if spec.dow, err = parseField(fields[4], 0, 7, dayNames); err != nil {
+		return Spec{}, err
+	}
+	if spec.dow&(1<<7) != 0 {
+		spec.dow |= 1
+	}
+	spec.domAny = strings.HasPrefix(fields[2], "*")
+	spec.dowAny = strings.HasPrefix(fields[4], "*")
+	return spec, nil
+}
+
+// parseField returns a bit set of the values a field matches.
+func parseField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%w: bad step in %q", ErrInvalidCron, part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = fieldValue(bounds[0], names); err != nil {
+				return 0, err
+			}
+			if hi, err = fieldValue(bounds[1], names); err != nil {
+				return 0, err
+			}
+		default:
+			v, err := fieldValue(rangePart, names)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = v, v
+			if strings.Contains(part, "/") {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%w: %q is outside %d-%d", ErrInvalidCron, part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func fieldValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a number", ErrInvalidCron, s)
+	}
+	return v, nil
+}
+
+// Next returns the first time after t that the spec matches, or the zero time if none
+// falls within the next five years (e.g. "0 0 31 2 *").
+func (s Spec) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s Spec) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
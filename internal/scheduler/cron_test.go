@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSpecNext(t *testing.T) {
+	// 2026-03-04 is a Wednesday.
+	from := time.Date(2026, 3, 4, 10, 17, 30, 0, time.UTC)
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 4, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{"0 6 * * 1", time.Date(2026, 3, 9, 6, 0, 0, 0, time.UTC)},
+		{"0 6 * * mon", time.Date(2026, 3, 9, 6, 0, 0, 0, time.UTC)},
+		{"30 9 1,15 * *", time.Date(2026, 3, 15, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2026, 3, 4, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: the 5th or any Friday, whichever comes first.
+		{"0 0 5 * fri", time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+	for _, tc := range cases {
+		spec, err := Parse(tc.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", tc.expr, err)
+		}
+		if got := spec.Next(from); !got.Equal(tc.want) {
+			t.Errorf("Next(%q) = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestParseRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		if _, err := Parse(expr); !errors.Is(err, ErrInvalidCron) {
+			t.Errorf("Parse(%q) = %v, want ErrInvalidCron", expr, err)
+		}
+	}
+}
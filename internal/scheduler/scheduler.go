@@ -0,0 +1,297 @@
+// Package scheduler fires agent runs from per-project cron schedules.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/yourusername/draft-forge/internal/agents"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+const (
+	defaultTickInterval = time.Minute
+	// catchUpWindow is how late a firing may be and still run, e.g. after a restart.
+	// Older missed firings are skipped rather than replayed.
+	catchUpWindow = time.Hour
+)
+
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+// errNotDue aborts a claim whose slot another instance has already fired.
+var errNotDue = errors.New("schedule not due")
+
+// DefaultSchedules apply to projects that have never saved their own: TimelineBot
+// rebuilds the timeline every Monday at 06:00 UTC.
+var DefaultSchedules = []models.AgentSchedule{
+	{ID: 1, AgentType: "timeline", Cron: "0 6 * * 1", Enabled: true},
+}
+
+// Store persists schedules per project. TryUpdateSchedules must not wait for a lock
+// held by another instance; it reports false instead.
+type Store interface {
+	ListProjectSchedules(ctx context.Context) ([]models.ProjectSchedules, error)
+	GetProjectSchedules(ctx context.Context, projectID int64) (models.ProjectSchedules, error)
+	UpdateSchedules(ctx context.Context, projectID int64, fn func(models.ProjectSchedules) ([]models.AgentSchedule, error)) ([]models.AgentSchedule, error)
+	TryUpdateSchedules(ctx context.Context, projectID int64, fn func(models.ProjectSchedules) ([]models.AgentSchedule, error)) (bool, error)
+}
+
+// RunQueuer queues agent runs; *agents.Service implements it.
+type RunQueuer interface {
+	QueueRun(ctx context.Context, req agents.RunRequest) (models.AgentRun, error)
+	HasAgent(name string) bool
+	HasChapters(ctx context.Context, projectID int64) (bool, error)
+}
+
+// ScheduleInput is the editable part of a schedule. A nil Enabled means enabled.
+type ScheduleInput struct {
+	AgentType string
+	Cron      string
+	Files     []string
+	Enabled   *bool
+}
+
+// Scheduler manages schedules and fires their runs. Any number of API instances may
+// run one: each firing is claimed under the project's lock and recorded as the
+// schedule's last run before the run is queued, so a slot fires once.
+type Scheduler struct {
+	store    Store
+	runs     RunQueuer
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewScheduler builds a scheduler; a non-positive interval falls back to one minute.
+func NewScheduler(store Store, runs RunQueuer, interval time.Duration) *Scheduler {
+	if interval <= 0 {
+		interval = defaultTickInterval
+	}
+	return &Scheduler{store: store, runs: runs, interval: interval, now: time.Now}
+}
+
+// Run fires due schedules every interval until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Tick(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("agent scheduler: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick queues a run for every enabled schedule with a firing due since its last run.
+func (s *Scheduler) Tick(ctx context.Context) error {
+	projects, err := s.store.ListProjectSchedules(ctx)
+	if err != nil {
+		return fmt.Errorf("list schedules: %w", err)
+	}
+	now := s.now().UTC()
+	for _, ps := range projects {
+		for _, sch := range effective(ps) {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.fire(ctx, sch, !ps.Configured, now)
+		}
+	}
+	return nil
+}
+
+// fire claims and queues sch's due firing. A default schedule only fires once the
+// project has chapters, so empty projects get no runs and keep their defaults unsaved.
+func (s *Scheduler) fire(ctx context.Context, sch models.AgentSchedule, isDefault bool, now time.Time) {
+	if !sch.Enabled {
+		return
+	}
+	spec, err := Parse(sch.Cron)
+	if err != nil {
+		log.Printf("agent scheduler: project %d schedule %d: %v", sch.ProjectID, sch.ID, err)
+		return
+	}
+	slot := dueSlot(spec, sch.LastRunAt, now)
+	if slot.IsZero() {
+		return
+	}
+	if isDefault {
+		ok, err := s.runs.HasChapters(ctx, sch.ProjectID)
+		if err != nil {
+			log.Printf("agent scheduler: project %d: %v", sch.ProjectID, err)
+			return
+		}
+		if !ok {
+			return
+		}
+	}
+
+	claimed, err := s.store.TryUpdateSchedules(ctx, sch.ProjectID, func(ps models.ProjectSchedules) ([]models.AgentSchedule, error) {
+		schedules := effective(ps)
+		i := indexOf(schedules, sch.ID)
+		if i < 0 {
+			return nil, errNotDue
+		}
+		if last := schedules[i].LastRunAt; last != nil && !slot.After(*last) {
+			return nil, errNotDue
+		}
+		schedules[i].LastRunAt = &slot
+		return schedules, nil
+	})
+	if errors.Is(err, errNotDue) || (err == nil && !claimed) {
+		return
+	}
+	if err != nil {
+		log.Printf("agent scheduler: claim project %d schedule %d: %v", sch.ProjectID, sch.ID, err)
+		return
+	}
+
+	if _, err := s.runs.QueueRun(ctx, agents.RunRequest{
+		ProjectID:    sch.ProjectID,
+		AgentType:    sch.AgentType,
+		Trigger:      "scheduled",
+		FilesChanged: sch.Files,
+	}); err != nil {
+		log.Printf("agent scheduler: queue %s for project %d: %v", sch.AgentType, sch.ProjectID, err)
+	}
+}
+
+// dueSlot returns the latest firing within the catch-up window that is after lastRun,
+// or the zero time when nothing is due.
+func dueSlot(spec Spec, lastRun *time.Time, now time.Time) time.Time {
+	var slot time.Time
+	for t := spec.Next(now.Add(-catchUpWindow)); !t.IsZero() && !t.After(now); t = spec.Next(t) {
+		slot = t
+	}
+	if slot.IsZero() || (lastRun != nil && !slot.After(*lastRun)) {
+		return time.Time{}
+	}
+	return slot
+}
+
+// List returns a project's schedules with their next firing time.
+func (s *Scheduler) List(ctx context.Context, projectID int64) ([]models.AgentSchedule, error) {
+	ps, err := s.store.GetProjectSchedules(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withNextRun(effective(ps)), nil
+}
+
+// Create adds a schedule to a project.
+func (s *Scheduler) Create(ctx context.Context, projectID int64, input ScheduleInput) (models.AgentSchedule, error) {
+	sch, err := s.validate(input)
+	if err != nil {
+		return models.AgentSchedule{}, err
+	}
+	sch.ProjectID = projectID
+	schedules, err := s.store.UpdateSchedules(ctx, projectID, func(ps models.ProjectSchedules) ([]models.AgentSchedule, error) {
+		schedules := effective(ps)
+		for _, existing := range schedules {
+			sch.ID = max(sch.ID, existing.ID)
+		}
+		sch.ID++
+		return append(schedules, sch), nil
+	})
+	if err != nil {
+		return models.AgentSchedule{}, err
+	}
+	return s.withNextRun(schedules[len(schedules)-1:])[0], nil
+}
+
+// Update replaces a schedule's settings, keeping its ID and last run.
+func (s *Scheduler) Update(ctx context.Context, projectID, scheduleID int64, input ScheduleInput) (models.AgentSchedule, error) {
+	sch, err := s.validate(input)
+	if err != nil {
+		return models.AgentSchedule{}, err
+	}
+	var updated models.AgentSchedule
+	_, err = s.store.UpdateSchedules(ctx, projectID, func(ps models.ProjectSchedules) ([]models.AgentSchedule, error) {
+		schedules := effective(ps)
+		i := indexOf(schedules, scheduleID)
+		if i < 0 {
+			return nil, ErrScheduleNotFound
+		}
+		sch.ID = scheduleID
+		sch.LastRunAt = schedules[i].LastRunAt
+		schedules[i] = sch
+		updated = sch
+		return schedules, nil
+	})
+	if err != nil {
+		return models.AgentSchedule{}, err
+	}
+	updated.ProjectID = projectID
+	return s.withNextRun([]models.AgentSchedule{updated})[0], nil
+}
+
+// Delete removes a schedule. Deleting a default schedule opts the project out of it.
+func (s *Scheduler) Delete(ctx context.Context, projectID, scheduleID int64) error {
+	_, err := s.store.UpdateSchedules(ctx, projectID, func(ps models.ProjectSchedules) ([]models.AgentSchedule, error) {
+		schedules := effective(ps)
+		i := indexOf(schedules, scheduleID)
+		if i < 0 {
+			return nil, ErrScheduleNotFound
+		}
+		return slices.Delete(schedules, i, i+1), nil
+	})
+	return err
+}
+
+func (s *Scheduler) validate(input ScheduleInput) (models.AgentSchedule, error) {
+	if !s.runs.HasAgent(input.AgentType) {
+		return models.AgentSchedule{}, agents.ErrInvalidAgentType
+	}
+	if _, err := Parse(input.Cron); err != nil {
+		return models.AgentSchedule{}, err
+	}
+	enabled := input.Enabled == nil || *input.Enabled
+	return models.AgentSchedule{
+		AgentType: input.AgentType,
+		Cron:      input.Cron,
+		Files:     input.Files,
+		Enabled:   enabled,
+	}, nil
+}
+
+func (s *Scheduler) withNextRun(schedules []models.AgentSchedule) []models.AgentSchedule {
+	now := s.now()
+	for i := range schedules {
+		schedules[i].NextRunAt = nil
+		if !schedules[i].Enabled {
+			continue
+		}
+		if spec, err := Parse(schedules[i].Cron); err == nil {
+			if next := spec.Next(now); !next.IsZero() {
+				schedules[i].NextRunAt = &next
+			}
+		}
+	}
+	return schedules
+}
+
+// effective returns the project's schedules, or the defaults if it has never saved any.
+func effective(ps models.ProjectSchedules) []models.AgentSchedule {
+	source := ps.Schedules
+	if !ps.Configured {
+		source = DefaultSchedules
+	}
+	schedules := make([]models.AgentSchedule, len(source))
+	for i, sch := range source {
+		sch.ProjectID = ps.ProjectID
+		sch.Files = slices.Clone(sch.Files)
+		schedules[i] = sch
+	}
+	return schedules
+}
+
+func indexOf(schedules []models.AgentSchedule, id int64) int {
+	return slices.IndexFunc(schedules, func(sch models.AgentSchedule) bool { return sch.ID == id })
+}
@@ -0,0 +1,223 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourusername/draft-forge/internal/agents"
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+type memStore struct {
+	mu       sync.Mutex
+	projects map[int64]models.ProjectSchedules
+	// locked simulates a project lock held by another instance.
+	locked map[int64]bool
+}
+
+func newMemStore(projectIDs ...int64) *memStore {
+	m := &memStore{projects: map[int64]models.ProjectSchedules{}, locked: map[int64]bool{}}
+	for _, id := range projectIDs {
+		m.projects[id] = models.ProjectSchedules{ProjectID: id}
+	}
+	return m
+}
+
+func (m *memStore) ListProjectSchedules(_ context.Context) ([]models.ProjectSchedules, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []models.ProjectSchedules
+	for id := int64(1); id <= int64(len(m.projects)); id++ {
+		out = append(out, m.projects[id])
+	}
+	return out, nil
+}
+
+func (m *memStore) GetProjectSchedules(_ context.Context, projectID int64) (models.ProjectSchedules, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ps, ok := m.projects[projectID]
+	if !ok {
+		return models.ProjectSchedules{}, models.ErrNotFound
+	}
+	return ps, nil
+}
+
+func (m *memStore) UpdateSchedules(_ context.Context, projectID int64, fn func(models.ProjectSchedules) ([]models.AgentSchedule, error)) ([]models.AgentSchedule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.update(projectID, fn)
+}
+
+func (m *memStore) TryUpdateSchedules(_ context.Context, projectID int64, fn func(models.ProjectSchedules) ([]models.AgentSchedule, error)) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locked[projectID] {
+		return false, nil
+	}
+	_, err := m.update(projectID, fn)
+	return true, err
+}
+
+func (m *memStore) update(projectID int64, fn func(models.ProjectSchedules) ([]models.AgentSchedule, error)) ([]models.AgentSchedule, error) {
+	ps, ok := m.projects[projectID]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	schedules, err := fn(ps)
+	if err != nil {
+		return nil, err
+	}
+	m.projects[projectID] = models.ProjectSchedules{ProjectID: projectID, Schedules: schedules, Configured: true}
+	return schedules, nil
+}
+
+type stubQueuer struct {
+	mu     sync.Mutex
+	queued []agents.RunRequest
+	// empty lists projects without a workspace or chapters.
+	empty map[int64]bool
+}
+
+func (q *stubQueuer) QueueRun(_ context.Context, req agents.RunRequest) (models.AgentRun, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.queued = append(q.queued, req)
+	return models.AgentRun{ID: int64(len(q.queued)), ProjectID: req.ProjectID, AgentType: req.AgentType}, nil
+}
+
+func (q *stubQueuer) HasAgent(name string) bool {
+	return name == "timeline" || name == "style"
+}
+
+func (q *stubQueuer) HasChapters(_ context.Context, projectID int64) (bool, error) {
+	return !q.empty[projectID], nil
+}
+
+func newTestScheduler(store Store, runs RunQueuer, now time.Time) *Scheduler {
+	s := NewScheduler(store, runs, 0)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+// monday is the first firing of the default TimelineBot schedule after 2026-03-04.
+var monday = time.Date(2026, 3, 9, 6, 0, 0, 0, time.UTC)
+
+func TestTickFiresDefaultTimelineScheduleOnce(t *testing.T) {
+	store := newMemStore(1, 2)
+	runs := &stubQueuer{}
+	ctx := context.Background()
+
+	if err := newTestScheduler(store, runs, monday.Add(-time.Minute)).Tick(ctx); err != nil {
+		t.Fatalf("Tick returned error: %v", err)
+	}
+	if len(runs.queued) != 0 {
+		t.Fatalf("expected nothing before Monday 06:00, got %+v", runs.queued)
+	}
+
+	// Two instances ticking in the same minute share one firing per project.
+	first := newTestScheduler(store, runs, monday.Add(30*time.Second))
+	second := newTestScheduler(store, runs, monday.Add(45*time.Second))
+	for _, s := range []*Scheduler{first, second, first} {
+		if err := s.Tick(ctx); err != nil {
+			t.Fatalf("Tick returned error: %v", err)
+		}
+	}
+	if len(runs.queued) != 2 {
+		t.Fatalf("expected one run per project, got %+v", runs.queued)
+	}
+	for _, req := range runs.queued {
+		if req.AgentType != "timeline" || req.Trigger != "scheduled" || len(req.FilesChanged) != 0 {
+			t.Fatalf("expected a whole-manuscript scheduled timeline run, got %+v", req)
+		}
+	}
+	if last := store.projects[1].Schedules[0].LastRunAt; last == nil || !last.Equal(monday) {
+		t.Fatalf("expected the slot to be recorded as the last run, got %v", last)
+	}
+}
+
+func TestTickSkipsDefaultScheduleForEmptyProjects(t *testing.T) {
+	store := newMemStore(1, 2)
+	runs := &stubQueuer{empty: map[int64]bool{2: true}}
+
+	if err := newTestScheduler(store, runs, monday).Tick(context.Background()); err != nil {
+		t.Fatalf("Tick returned error: %v", err)
+	}
+	if len(runs.queued) != 1 || runs.queued[0].ProjectID != 1 {
+		t.Fatalf("expected a run for project 1 only, got %+v", runs.queued)
+	}
+	if ps := store.projects[2]; ps.Configured || len(ps.Schedules) != 0 {
+		t.Fatalf("expected the empty project's defaults to stay unsaved, got %+v", ps)
+	}
+}
+
+func TestTickSkipsLockedProjectsAndStaleSlots(t *testing.T) {
+	store := newMemStore(1)
+	store.locked[1] = true
+	runs := &stubQueuer{}
+	ctx := context.Background()
+
+	if err := newTestScheduler(store, runs, monday).Tick(ctx); err != nil {
+		t.Fatalf("Tick returned error: %v", err)
+	}
+	if len(runs.queued) != 0 {
+		t.Fatalf("expected a project locked by another instance to be skipped, got %+v", runs.queued)
+	}
+
+	store.locked[1] = false
+	if err := newTestScheduler(store, runs, monday.Add(2*time.Hour)).Tick(ctx); err != nil {
+		t.Fatalf("Tick returned error: %v", err)
+	}
+	if len(runs.queued) != 0 {
+		t.Fatalf("expected a firing outside the catch-up window to be skipped, got %+v", runs.queued)
+	}
+}
+
+func TestScheduleCRUD(t *testing.T) {
+	store := newMemStore(1)
+	s := newTestScheduler(store, &stubQueuer{}, monday.Add(-time.Hour))
+	ctx := context.Background()
+
+	list, err := s.List(ctx, 1)
+	if err != nil || len(list) != 1 || list[0].AgentType != "timeline" || list[0].NextRunAt == nil || !list[0].NextRunAt.Equal(monday) {
+		t.Fatalf("expected the default timeline schedule with its next run, got %+v %v", list, err)
+	}
+
+	created, err := s.Create(ctx, 1, ScheduleInput{AgentType: "style", Cron: "0 * * * *", Files: []string{"chapters/01.md"}})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if created.ID != 2 || !created.Enabled || created.ProjectID != 1 {
+		t.Fatalf("expected an enabled schedule with the next free id, got %+v", created)
+	}
+
+	disabled := false
+	updated, err := s.Update(ctx, 1, created.ID, ScheduleInput{AgentType: "style", Cron: "@daily", Enabled: &disabled})
+	if err != nil || updated.Enabled || updated.Cron != "@daily" || updated.NextRunAt != nil {
+		t.Fatalf("expected a disabled daily schedule, got %+v %v", updated, err)
+	}
+
+	if err := s.Delete(ctx, 1, 1); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	list, _ = s.List(ctx, 1)
+	if len(list) != 1 || list[0].ID != created.ID {
+		t.Fatalf("expected only the style schedule after opting out of the default, got %+v", list)
+	}
+
+	if _, err := s.Create(ctx, 1, ScheduleInput{AgentType: "nope", Cron: "@daily"}); !errors.Is(err, agents.ErrInvalidAgentType) {
+		t.Fatalf("expected ErrInvalidAgentType, got %v", err)
+	}
+	if _, err := s.Create(ctx, 1, ScheduleInput{AgentType: "style", Cron: "every day"}); !errors.Is(err, ErrInvalidCron) {
+		t.Fatalf("expected ErrInvalidCron, got %v", err)
+	}
+	if err := s.Delete(ctx, 1, 99); !errors.Is(err, ErrScheduleNotFound) {
+		t.Fatalf("expected ErrScheduleNotFound, got %v", err)
+	}
+	if _, err := s.List(ctx, 7); !errors.Is(err, models.ErrNotFound) {
+		t.Fatalf("expected models.ErrNotFound for a missing project, got %v", err)
+	}
+}
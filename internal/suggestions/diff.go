@@ -0,0 +1,130 @@
+package suggestions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// contextLines is how many unchanged lines surround a hunk, as in `diff -u`.
+const contextLines = 3
+
+// Revision identifies the version of a file an edit was made against.
+func Revision(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// Target returns lines start..end (1-based, inclusive) of content. ok is false when the
+// range falls outside the file.
+func Target(content string, start, end int) (string, bool) {
+	lines := splitLines(content)
+	if start < 1 || end < start || end > len(lines) {
+		return "", false
+	}
+	return strings.Join(lines[start-1:end], "\n"), true
+}
+
+// Apply returns content with lines start..end replaced by replacement. An empty
+// replacement deletes the lines.
+func Apply(content string, start, end int, replacement string) string {
+	lines := splitLines(content)
+	out := make([]string, 0, len(lines))
+	out = append(out, lines[:start-1]...)
+	out = append(out, splitLines(replacement)...)
+	out = append(out, lines[end:]...)
+	joined := strings.Join(out, "\n")
+	if strings.HasSuffix(content, "\n") && len(out) > 0 {
+		joined += "\n"
+	}
+	return joined
+}
+
+// Patch renders a git-style unified diff of path replacing lines start..end of content.
+func Patch(path, content string, start, end int, replacement string) string {
+	return fmt.Sprintf("--- a/%s\n+++ b/%s\n%s", path, path, Hunk(content, start, end, replacement))
+}
+
+// Hunk renders the unified-diff hunk replacing lines start..end of content.
+func Hunk(content string, start, end int, replacement string) string {
+	lines := splitLines(content)
+	added := splitLines(replacement)
+	from := max(0, start-1-contextLines)
+	to := min(len(lines), end+contextLines)
+	oldCount := to - from
+	newCount := oldCount - (end - start + 1) + len(added)
+	// Without a trailing newline the file's last line needs git's marker after it.
+	noEOL := !strings.HasSuffix(content, "\n") && to == len(lines)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(from, oldCount), hunkRange(from, newCount))
+	for _, line := range lines[from : start-1] {
+		b.WriteString(" " + line + "\n")
+	}
+	for i, line := range lines[start-1 : end] {
+		b.WriteString("-" + line + "\n")
+		if noEOL && end == to && i == end-start {
+			b.WriteString("\\ No newline at end of file\n")
+		}
+	}
+	for i, line := range added {
+		b.WriteString("+" + line + "\n")
+		if noEOL && end == to && i == len(added)-1 {
+			b.WriteString("\\ No newline at end of file\n")
+		}
+	}
+	for i, line := range lines[end:to] {
+		b.WriteString(" " + line + "\n")
+		if noEOL && end+i+1 == to {
+			b.WriteString("\\ No newline at end of file\n")
+		}
+	}
+	return b.String()
+}
+
+// hunkRange formats one side of a hunk header; empty ranges start at the line before.
+func hunkRange(from, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", from)
+	}
+	return fmt.Sprintf("%d,%d", from+1, count)
+}
+
+// Locate finds the lines a suggestion targets in content: its recorded range when that
+// still holds the original text, otherwise the one other place the text now appears
+// (lines above it may have been added or removed). ok is false when the text was edited
+// or is ambiguous, meaning the suggestion is stale.
+func Locate(content string, s models.Suggestion) (start, end int, ok bool) {
+	if target, ok := Target(content, s.StartLine, s.EndLine); ok && target == s.Original {
+		return s.StartLine, s.EndLine, true
+	}
+
+	lines := splitLines(content)
+	want := splitLines(s.Original)
+	found := 0
+	for i := 0; i+len(want) <= len(lines); i++ {
+		if strings.Join(lines[i:i+len(want)], "\n") == s.Original {
+			if found > 0 {
+				return 0, 0, false
+			}
+			found = i + 1
+		}
+	}
+	if found == 0 || len(want) == 0 {
+		return 0, 0, false
+	}
+	return found, found + len(want) - 1, true
+}
+
+// splitLines splits text into lines without terminators; a final newline does not add
+// an empty line.
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,83 @@
+package suggestions
+
+import (
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+const chapter = `# Chapter One
+
+Mara's blue eyes caught the light.
+
+She walked to the harbour.
+The ferry was late.
+Gulls circled overhead.
+Night fell.
+`
+
+func TestHunk(t *testing.T) {
+	got := Hunk(chapter, 3, 3, "Mara's green eyes caught the light.")
+	want := `@@ -1,6 +1,6 @@
+ # Chapter One
+ 
+-Mara's blue eyes caught the light.
++Mara's green eyes caught the light.
+ 
+ She walked to the harbour.
+ The ferry was late.
+`
+	if got != want {
+		t.Fatalf("unexpected hunk:\n%s", got)
+	}
+
+	got = Hunk(chapter, 7, 8, "")
+	want = `@@ -4,5 +4,3 @@
+ 
+ She walked to the harbour.
+ The ferry was late.
+-Gulls circled overhead.
+-Night fell.
+`
+	if got != want {
+		t.Fatalf("unexpected deletion hunk:\n%s", got)
+	}
+
+	got = Hunk("Night fell.", 1, 1, "Night came.")
+	want = "@@ -1,1 +1,1 @@\n-Night fell.\n\\ No newline at end of file\n+Night came.\n\\ No newline at end of file\n"
+	if got != want {
+		t.Fatalf("unexpected hunk without trailing newline:\n%s", got)
+	}
+}
+
+func TestApply(t *testing.T) {
+	got := Apply(chapter, 5, 6, "She ran to the harbour; the ferry was late.")
+	want := `# Chapter One
+
+Mara's blue eyes caught the light.
+
+She ran to the harbour; the ferry was late.
+Gulls circled overhead.
+Night fell.
+`
+	if got != want {
+		t.Fatalf("unexpected content:\n%s", got)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	s := models.Suggestion{StartLine: 3, EndLine: 3, Original: "Mara's blue eyes caught the light."}
+
+	if start, end, ok := Locate(chapter, s); !ok || start != 3 || end != 3 {
+		t.Fatalf("expected the original range, got %d-%d %v", start, end, ok)
+	}
+	if start, end, ok := Locate("Prologue.\n\n"+chapter, s); !ok || start != 5 || end != 5 {
+		t.Fatalf("expected the range to follow the moved text, got %d-%d %v", start, end, ok)
+	}
+	if _, _, ok := Locate(Apply(chapter, 3, 3, "Mara's grey eyes caught the light."), s); ok {
+		t.Fatal("expected edited text to be stale")
+	}
+	if _, _, ok := Locate("Intro.\n\nChanged.\n"+s.Original+"\n"+s.Original+"\n", s); ok {
+		t.Fatal("expected ambiguous text to be stale")
+	}
+}
@@ -0,0 +1,143 @@
+package suggestions
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+// branchPrefix namespaces the branches accepted suggestions are committed to.
+const branchPrefix = "draftforge/suggestion-"
+
+// commitIdentity is the author and committer of suggestion commits.
+var commitIdentity = []string{
+	"GIT_AUTHOR_NAME=DraftForge", "GIT_AUTHOR_EMAIL=draftforge@localhost",
+	"GIT_COMMITTER_NAME=DraftForge", "GIT_COMMITTER_EMAIL=draftforge@localhost",
+}
+
+// errNotCommitted reports that the suggestion's file has no committed version to build
+// on; Accept writes a patch instead.
+var errNotCommitted = errors.New("file not committed")
+
+// workspaceCheckout returns root when it is the top level of its own git checkout, or ""
+// otherwise. A workspace that merely sits inside another repository (e.g. a scaffold
+// under the server's own checkout) is not committed to.
+func workspaceCheckout(ctx context.Context, root string) string {
+	out, err := runGit(ctx, root, nil, "", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return ""
+	}
+	top, err := filepath.EvalSymlinks(out)
+	if err != nil {
+		return ""
+	}
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return ""
+	}
+	if abs, err = filepath.EvalSymlinks(abs); err != nil || abs != top {
+		return ""
+	}
+	return top
+}
+
+// commitSuggestion commits the suggestion, applied to the file as of HEAD, onto its own
+// branch. It builds the commit with a private index so the checkout's working tree,
+// index and current branch are left alone.
+func commitSuggestion(ctx context.Context, top, root string, s models.Suggestion) (branch, commit string, err error) {
+	abs, err := filepath.Abs(filepath.Join(root, filepath.FromSlash(s.File)))
+	if err != nil {
+		return "", "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		abs = filepath.Join(resolved, filepath.Base(abs))
+	}
+	rel, err := filepath.Rel(top, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", "", fmt.Errorf("%s is outside the checkout", s.File)
+	}
+	rel = filepath.ToSlash(rel)
+
+	head, err := runGit(ctx, top, nil, "", "rev-parse", "--verify", "HEAD")
+	if err != nil {
+		// No commits yet.
+		return "", "", fmt.Errorf("%w: %s", errNotCommitted, s.File)
+	}
+	entry, err := runGit(ctx, top, nil, "", "ls-tree", head, "--", rel)
+	if err != nil {
+		return "", "", err
+	}
+	mode, _, found := strings.Cut(entry, " ")
+	if !found {
+		return "", "", fmt.Errorf("%w: %s", errNotCommitted, s.File)
+	}
+	content, err := runGit(ctx, top, nil, "", "cat-file", "blob", head+":"+rel)
+	if err != nil {
+		return "", "", err
+	}
+	start, end, ok := Locate(content, s)
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s changed since the run", ErrSuggestionStale, s.File)
+	}
+
+	blob, err := runGit(ctx, top, nil, Apply(content, start, end, s.Replacement), "hash-object", "-w", "--stdin")
+	if err != nil {
+		return "", "", err
+	}
+
+	indexDir, err := os.MkdirTemp("", "draftforge-index-")
+	if err != nil {
+		return "", "", err
+	}
+	defer os.RemoveAll(indexDir)
+	env := append([]string{"GIT_INDEX_FILE=" + filepath.Join(indexDir, "index")}, commitIdentity...)
+
+	if _, err := runGit(ctx, top, env, "", "read-tree", head); err != nil {
+		return "", "", err
+	}
+	if _, err := runGit(ctx, top, env, "", "update-index", "--cacheinfo", mode+","+blob+","+rel); err != nil {
+		return "", "", err
+	}
+	tree, err := runGit(ctx, top, env, "", "write-tree")
+	if err != nil {
+		return "", "", err
+	}
+	message := fmt.Sprintf("Apply %s suggestion %d to %s\n\n%s\n", s.AgentType, s.ID, s.File, s.Message)
+	commit, err = runGit(ctx, top, env, "", "commit-tree", tree, "-p", head, "-m", message)
+	if err != nil {
+		return "", "", err
+	}
+
+	branch = fmt.Sprintf("%s%d", branchPrefix, s.ID)
+	if _, err := runGit(ctx, top, nil, "", "update-ref", "refs/heads/"+branch, commit); err != nil {
+		return "", "", err
+	}
+	return branch, commit, nil
+}
+
+// runGit runs git in dir and returns its trimmed output. Blob contents are returned
+// untrimmed.
+func runGit(ctx context.Context, dir string, env []string, stdin string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	if args[0] == "cat-file" {
+		return stdout.String(), nil
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
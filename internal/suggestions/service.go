@@ -0,0 +1,224 @@
+package suggestions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+var (
+	ErrSuggestionNotFound = errors.New("suggestion not found")
+	ErrSuggestionNotOpen  = errors.New("suggestion is not open")
+	ErrSuggestionStale    = errors.New("suggestion is stale")
+	ErrReasonRequired     = errors.New("a reason is required to reject a suggestion")
+	ErrInvalidState       = errors.New("invalid suggestion state")
+)
+
+// Store persists suggestions.
+type Store interface {
+	ListSuggestions(ctx context.Context, projectID int64, state string) ([]models.Suggestion, error)
+	GetSuggestion(ctx context.Context, projectID, id int64) (models.Suggestion, error)
+	MarkStale(ctx context.Context, id int64) error
+	// Resolve records an open suggestion's new state and outcome. models.ErrNotFound
+	// means the suggestion is no longer open.
+	Resolve(ctx context.Context, suggestion models.Suggestion) (models.Suggestion, error)
+}
+
+// WorkspaceResolver locates the working tree of a project.
+type WorkspaceResolver interface {
+	WorkspacePath(ctx context.Context, projectID int64) (string, error)
+}
+
+// Service reviews the edits agents suggest. Accepting one commits it to a branch named
+// after the suggestion when the workspace is a git checkout, and otherwise writes a
+// patch file under the artifact directory.
+type Service struct {
+	store       Store
+	workspaces  WorkspaceResolver
+	artifactDir string
+	now         func() time.Time
+}
+
+func NewService(store Store, workspaces WorkspaceResolver, artifactDir string) *Service {
+	return &Service{store: store, workspaces: workspaces, artifactDir: artifactDir, now: time.Now}
+}
+
+// List returns a project's suggestions, newest first, optionally filtered by state.
+// Open suggestions whose target text has changed are marked stale first.
+func (s *Service) List(ctx context.Context, projectID int64, state string) ([]models.Suggestion, error) {
+	switch state {
+	case "", models.SuggestionOpen, models.SuggestionAccepted, models.SuggestionRejected, models.SuggestionStale:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidState, state)
+	}
+	if err := s.refreshStale(ctx, projectID); err != nil {
+		return nil, err
+	}
+	return s.store.ListSuggestions(ctx, projectID, state)
+}
+
+// Get returns one suggestion of a project.
+func (s *Service) Get(ctx context.Context, projectID, id int64) (models.Suggestion, error) {
+	suggestion, err := s.store.GetSuggestion(ctx, projectID, id)
+	if errors.Is(err, models.ErrNotFound) {
+		return models.Suggestion{}, ErrSuggestionNotFound
+	}
+	return suggestion, err
+}
+
+// Accept applies an open suggestion to a new branch and marks it accepted. Workspaces that
+// are not their own git checkout, and files not yet committed, get a patch file instead.
+// A suggestion whose target text has changed is marked stale and ErrSuggestionStale
+// returned.
+func (s *Service) Accept(ctx context.Context, projectID, id int64) (models.Suggestion, error) {
+	suggestion, err := s.openSuggestion(ctx, projectID, id)
+	if err != nil {
+		return models.Suggestion{}, err
+	}
+	root, err := s.workspaces.WorkspacePath(ctx, projectID)
+	if err != nil {
+		return models.Suggestion{}, fmt.Errorf("resolve workspace: %w", err)
+	}
+
+	content, found, err := readWorkspaceFile(root, suggestion.File)
+	if err != nil {
+		return models.Suggestion{}, err
+	}
+	start, end, ok := Locate(content, suggestion)
+	if !found || !ok {
+		return models.Suggestion{}, s.markStale(ctx, suggestion)
+	}
+
+	if top := workspaceCheckout(ctx, root); top != "" {
+		suggestion.Branch, suggestion.Commit, err = commitSuggestion(ctx, top, root, suggestion)
+		if errors.Is(err, ErrSuggestionStale) {
+			return models.Suggestion{}, s.markStale(ctx, suggestion)
+		}
+		if err != nil && !errors.Is(err, errNotCommitted) {
+			return models.Suggestion{}, fmt.Errorf("commit suggestion: %w", err)
+		}
+	}
+	if suggestion.Commit == "" {
+		suggestion.PatchPath, err = s.writePatch(suggestion, Patch(suggestion.File, content, start, end, suggestion.Replacement))
+		if err != nil {
+			return models.Suggestion{}, fmt.Errorf("write patch: %w", err)
+		}
+	}
+
+	suggestion.State = models.SuggestionAccepted
+	return s.resolve(ctx, suggestion)
+}
+
+// Reject marks an open suggestion rejected, recording why.
+func (s *Service) Reject(ctx context.Context, projectID, id int64, reason string) (models.Suggestion, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return models.Suggestion{}, ErrReasonRequired
+	}
+	suggestion, err := s.openSuggestion(ctx, projectID, id)
+	if err != nil {
+		return models.Suggestion{}, err
+	}
+	suggestion.State = models.SuggestionRejected
+	suggestion.Reason = reason
+	return s.resolve(ctx, suggestion)
+}
+
+func (s *Service) openSuggestion(ctx context.Context, projectID, id int64) (models.Suggestion, error) {
+	suggestion, err := s.Get(ctx, projectID, id)
+	if err != nil {
+		return models.Suggestion{}, err
+	}
+	if suggestion.State != models.SuggestionOpen {
+		return models.Suggestion{}, fmt.Errorf("%w: it is %s", ErrSuggestionNotOpen, suggestion.State)
+	}
+	return suggestion, nil
+}
+
+func (s *Service) resolve(ctx context.Context, suggestion models.Suggestion) (models.Suggestion, error) {
+	now := s.now()
+	suggestion.ResolvedAt = &now
+	resolved, err := s.store.Resolve(ctx, suggestion)
+	if errors.Is(err, models.ErrNotFound) {
+		// Resolved concurrently by another request.
+		return models.Suggestion{}, ErrSuggestionNotOpen
+	}
+	return resolved, err
+}
+
+// refreshStale marks the project's open suggestions whose target text can no longer be
+// found in the workspace as stale.
+func (s *Service) refreshStale(ctx context.Context, projectID int64) error {
+	open, err := s.store.ListSuggestions(ctx, projectID, models.SuggestionOpen)
+	if err != nil || len(open) == 0 {
+		return err
+	}
+	root, err := s.workspaces.WorkspacePath(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("resolve workspace: %w", err)
+	}
+
+	files := make(map[string]string)
+	for _, suggestion := range open {
+		content, ok := files[suggestion.File]
+		if !ok {
+			// A deleted file reads as empty, so its suggestions go stale.
+			content, _, err = readWorkspaceFile(root, suggestion.File)
+			if err != nil {
+				return err
+			}
+			files[suggestion.File] = content
+		}
+		if _, _, ok := Locate(content, suggestion); !ok {
+			if err := s.store.MarkStale(ctx, suggestion.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// markStale records that a suggestion can no longer be applied and returns the error
+// reported to the caller.
+func (s *Service) markStale(ctx context.Context, suggestion models.Suggestion) error {
+	if err := s.store.MarkStale(ctx, suggestion.ID); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s changed since run %d", ErrSuggestionStale, suggestion.File, suggestion.RunID)
+}
+
+// writePatch writes a suggestion's patch under the artifact directory and returns its
+// path relative to that directory.
+func (s *Service) writePatch(suggestion models.Suggestion, patch string) (string, error) {
+	rel := filepath.Join("suggestions", fmt.Sprintf("suggestion-%d.patch", suggestion.ID))
+	path := filepath.Join(s.artifactDir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(patch), 0o644); err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// readWorkspaceFile reads a repo-relative file. found is false when it no longer exists.
+func readWorkspaceFile(root, rel string) (content string, found bool, err error) {
+	clean := filepath.Clean(filepath.FromSlash(rel))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", false, nil
+	}
+	data, err := os.ReadFile(filepath.Join(root, clean))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("read %s: %w", rel, err)
+	}
+	return string(data), true, nil
+}
@@ -0,0 +1,266 @@
+package suggestions
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yourusername/draft-forge/internal/models"
+)
+
+type memStore struct {
+	mu          sync.Mutex
+	suggestions map[int64]models.Suggestion
+}
+
+func newMemStore(suggestions ...models.Suggestion) *memStore {
+	store := &memStore{suggestions: map[int64]models.Suggestion{}}
+	for _, s := range suggestions {
+		store.suggestions[s.ID] = s
+	}
+	return store
+}
+
+func (m *memStore) ListSuggestions(_ context.Context, projectID int64, state string) ([]models.Suggestion, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []models.Suggestion
+	for _, s := range m.suggestions {
+		if s.ProjectID == projectID && (state == "" || s.State == state) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (m *memStore) GetSuggestion(_ context.Context, projectID, id int64) (models.Suggestion, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.suggestions[id]
+	if !ok || s.ProjectID != projectID {
+		return models.Suggestion{}, models.ErrNotFound
+	}
+	return s, nil
+}
+
+func (m *memStore) MarkStale(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s := m.suggestions[id]; s.State == models.SuggestionOpen {
+		s.State = models.SuggestionStale
+		m.suggestions[id] = s
+	}
+	return nil
+}
+
+func (m *memStore) Resolve(_ context.Context, suggestion models.Suggestion) (models.Suggestion, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.suggestions[suggestion.ID].State != models.SuggestionOpen {
+		return models.Suggestion{}, models.ErrNotFound
+	}
+	m.suggestions[suggestion.ID] = suggestion
+	return suggestion, nil
+}
+
+type stubWorkspaces map[int64]string
+
+func (w stubWorkspaces) WorkspacePath(_ context.Context, projectID int64) (string, error) {
+	return w[projectID], nil
+}
+
+func eyeColourSuggestion() models.Suggestion {
+	return models.Suggestion{
+		ID: 4, ProjectID: 1, RunID: 9, AgentType: "continuity", File: "chapters/01.md",
+		StartLine: 3, EndLine: 3,
+		Original:    "Mara's blue eyes caught the light.",
+		Replacement: "Mara's green eyes caught the light.",
+		Message:     "Mara's eyes are green in the story bible.",
+		State:       models.SuggestionOpen,
+	}
+}
+
+func writeChapter(t *testing.T, root, content string) {
+	t.Helper()
+	path := filepath.Join(root, "chapters", "01.md")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := runGit(context.Background(), dir, commitIdentity, "", args...)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	return out
+}
+
+func TestAcceptWritesPatchOutsideGit(t *testing.T) {
+	root, artifacts := t.TempDir(), t.TempDir()
+	writeChapter(t, root, chapter)
+	store := newMemStore(eyeColourSuggestion())
+	svc := NewService(store, stubWorkspaces{1: root}, artifacts)
+
+	accepted, err := svc.Accept(context.Background(), 1, 4)
+	if err != nil {
+		t.Fatalf("Accept returned error: %v", err)
+	}
+	if accepted.State != models.SuggestionAccepted || accepted.PatchPath != "suggestions/suggestion-4.patch" || accepted.ResolvedAt == nil {
+		t.Fatalf("unexpected suggestion %+v", accepted)
+	}
+	patch, err := os.ReadFile(filepath.Join(artifacts, accepted.PatchPath))
+	if err != nil {
+		t.Fatalf("read patch: %v", err)
+	}
+	if !strings.HasPrefix(string(patch), "--- a/chapters/01.md\n+++ b/chapters/01.md\n@@ -1,6 +1,6 @@") ||
+		!strings.Contains(string(patch), "+Mara's green eyes caught the light.") {
+		t.Fatalf("unexpected patch:\n%s", patch)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "chapters", "01.md")); string(data) != chapter {
+		t.Fatal("expected the workspace to be left untouched")
+	}
+
+	if _, err := svc.Accept(context.Background(), 1, 4); !errors.Is(err, ErrSuggestionNotOpen) {
+		t.Fatalf("expected ErrSuggestionNotOpen, got %v", err)
+	}
+}
+
+func TestAcceptCommitsToBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	git(t, root, "init", "-q", "-b", "main")
+	writeChapter(t, root, "Prologue.\n\n"+chapter)
+	git(t, root, "add", ".")
+	git(t, root, "commit", "-q", "-m", "Draft")
+	head := git(t, root, "rev-parse", "HEAD")
+
+	store := newMemStore(eyeColourSuggestion())
+	svc := NewService(store, stubWorkspaces{1: root}, t.TempDir())
+
+	accepted, err := svc.Accept(context.Background(), 1, 4)
+	if err != nil {
+		t.Fatalf("Accept returned error: %v", err)
+	}
+	if accepted.Branch != "draftforge/suggestion-4" || accepted.Commit == "" || accepted.PatchPath != "" {
+		t.Fatalf("unexpected suggestion %+v", accepted)
+	}
+	if parent := git(t, root, "rev-parse", accepted.Commit+"^"); parent != head {
+		t.Fatalf("expected the commit to build on HEAD, got parent %s", parent)
+	}
+	if content, _ := runGit(context.Background(), root, nil, "", "cat-file", "blob", accepted.Branch+":chapters/01.md"); !strings.Contains(content, "\nMara's green eyes caught the light.\n") {
+		t.Fatalf("expected the edit on the branch, got:\n%s", content)
+	}
+	if branch := git(t, root, "rev-parse", "--abbrev-ref", "HEAD"); branch != "main" || git(t, root, "status", "--porcelain") != "" {
+		t.Fatalf("expected the checkout to stay clean on main, got %s", branch)
+	}
+}
+
+func TestAcceptWritesPatchInsideUnrelatedRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	outer := t.TempDir()
+	git(t, outer, "init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(outer, "README.md"), []byte("Server.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, outer, "add", ".")
+	git(t, outer, "commit", "-q", "-m", "Server")
+
+	// An untracked scaffold inside the server's checkout, as with ./scaffolds/<slug>.
+	root := filepath.Join(outer, "scaffolds", "novel")
+	writeChapter(t, root, chapter)
+	store := newMemStore(eyeColourSuggestion())
+	svc := NewService(store, stubWorkspaces{1: root}, t.TempDir())
+
+	accepted, err := svc.Accept(context.Background(), 1, 4)
+	if err != nil {
+		t.Fatalf("Accept returned error: %v", err)
+	}
+	if accepted.State != models.SuggestionAccepted || accepted.PatchPath == "" || accepted.Branch != "" {
+		t.Fatalf("expected a patch rather than a commit, got %+v", accepted)
+	}
+	if branches := git(t, outer, "branch", "--list", "draftforge/*"); branches != "" {
+		t.Fatalf("expected no branches in the outer repository, got %q", branches)
+	}
+}
+
+func TestAcceptWritesPatchForUncommittedFile(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	git(t, root, "init", "-q", "-b", "main")
+	writeChapter(t, root, chapter)
+
+	store := newMemStore(eyeColourSuggestion())
+	svc := NewService(store, stubWorkspaces{1: root}, t.TempDir())
+
+	accepted, err := svc.Accept(context.Background(), 1, 4)
+	if err != nil {
+		t.Fatalf("Accept returned error: %v", err)
+	}
+	if accepted.State != models.SuggestionAccepted || accepted.PatchPath == "" {
+		t.Fatalf("expected a patch for an uncommitted file, got %+v", accepted)
+	}
+}
+
+func TestListMarksChangedSuggestionsStale(t *testing.T) {
+	root := t.TempDir()
+	writeChapter(t, root, Apply(chapter, 3, 3, "Mara's grey eyes caught the light."))
+	moved := eyeColourSuggestion()
+	moved.ID, moved.File = 5, "chapters/02.md"
+	store := newMemStore(eyeColourSuggestion(), moved)
+	svc := NewService(store, stubWorkspaces{1: root}, t.TempDir())
+
+	open, err := svc.List(context.Background(), 1, models.SuggestionOpen)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(open) != 0 {
+		t.Fatalf("expected no open suggestions, got %+v", open)
+	}
+	if stale, _ := svc.List(context.Background(), 1, models.SuggestionStale); len(stale) != 2 {
+		t.Fatalf("expected both suggestions to be stale, got %+v", stale)
+	}
+	if _, err := svc.List(context.Background(), 1, "merged"); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState, got %v", err)
+	}
+
+	store.suggestions[4] = eyeColourSuggestion()
+	writeChapter(t, root, "Rewritten.\n")
+	if _, err := svc.Accept(context.Background(), 1, 4); !errors.Is(err, ErrSuggestionStale) {
+		t.Fatalf("expected ErrSuggestionStale, got %v", err)
+	}
+	if store.suggestions[4].State != models.SuggestionStale {
+		t.Fatalf("expected accept to mark the suggestion stale, got %+v", store.suggestions[4])
+	}
+}
+
+func TestRejectRequiresReason(t *testing.T) {
+	store := newMemStore(eyeColourSuggestion())
+	svc := NewService(store, stubWorkspaces{}, t.TempDir())
+
+	if _, err := svc.Reject(context.Background(), 1, 4, "  "); !errors.Is(err, ErrReasonRequired) {
+		t.Fatalf("expected ErrReasonRequired, got %v", err)
+	}
+	rejected, err := svc.Reject(context.Background(), 1, 4, "Her eyes change on purpose.")
+	if err != nil || rejected.State != models.SuggestionRejected || rejected.Reason != "Her eyes change on purpose." {
+		t.Fatalf("unexpected rejection %+v, %v", rejected, err)
+	}
+	if _, err := svc.Reject(context.Background(), 2, 4, "wrong project"); !errors.Is(err, ErrSuggestionNotFound) {
+		t.Fatalf("expected ErrSuggestionNotFound, got %v", err)
+	}
+}